@@ -17,8 +17,13 @@
 // CheckpointRestore-aware mutating webhook server
 // - HTTPS on :8443 using /tls/tls.crt and /tls/tls.key
 // - Health endpoints: /healthz, /readyz (HTTPS)
-// - Looks up CheckpointRestore (GVR from env) and, if matched by podName or podGenerateName prefix,
-//   replaces container (and initContainer) images using spec.containers[].image (or fallback spec.image)
+// - Looks up CheckpointRestore (GVR from env) and, if matched by podName or by the CR's podMapping
+//   (Ordinal, Label or RoundRobin), replaces container (and initContainer) images using
+//   spec.containers[].image (or fallback spec.image)
+// - Records the checkpoint → pod mapping in the CR status and labels the pod with the CR name
+// - Validates pods/eviction: holds the eviction of a pod with a scheduled CheckpointBackup until
+//   a checkpoint taken after the eviction request is pushed, at most EVICTION_CHECKPOINT_TIMEOUT
+//   and registers that checkpoint as a CheckpointRestore for the replacement pod
 
 package main
 
@@ -30,12 +35,18 @@ import (
         "net/http"
         "os"
         "path/filepath"
+        "sort"
+        "strconv"
         "strings"
+        "time"
 
         admissionv1 "k8s.io/api/admission/v1"
         corev1 "k8s.io/api/core/v1"
+        apierrors "k8s.io/apimachinery/pkg/api/errors"
         metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+        "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
         "k8s.io/apimachinery/pkg/runtime/schema"
+        "k8s.io/apimachinery/pkg/types"
         "k8s.io/client-go/dynamic"
         "k8s.io/client-go/rest"
         "k8s.io/client-go/tools/clientcmd"
@@ -49,8 +60,34 @@ var (
         crGroup    = getenvDefault("CHECKPOINT_RESTORE_GVR_GROUP", "migration.dcnlab.com")
         crVersion  = getenvDefault("CHECKPOINT_RESTORE_GVR_VERSION", "v1")
         crResource = getenvDefault("CHECKPOINT_RESTORE_GVR_RESOURCE", "checkpointrestores")
+
+        // CheckpointBackups of the pods whose eviction is held
+        backupGVR = schema.GroupVersionResource{Group: crGroup, Version: crVersion, Resource: "checkpointbackups"}
+
+        // How long an eviction is held for the checkpoint before it is let through anyway
+        evictionCheckpointTimeout = parseDurationDefault(os.Getenv("EVICTION_CHECKPOINT_TIMEOUT"), 3*time.Minute)
+
+        // An eviction annotation older than this belongs to an earlier drain, not to the current one
+        evictionWindow = 2 * evictionCheckpointTimeout
+
+        // Client for the cluster, built once at startup (admission calls time out after a few seconds)
+        kubeClient dynamic.Interface
 )
 
+// labelCheckpointRestore is set on restored pods to the name of the CheckpointRestore they were restored from
+const labelCheckpointRestore = "migration.dcnlab.com/checkpoint-restore"
+
+// annoVolumeReady marks a claim whose data was transferred by a MigrationRun
+const annoVolumeReady = "migration.dcnlab.com/volume-ready"
+
+// annoEvictionCheckpoint is set on a pod to the time its eviction was first requested; the
+// checkpoint agent takes a checkpoint of the pod for it. On the CheckpointRestore created for
+// the eviction it holds the pod name, which may be too long for a label value.
+const annoEvictionCheckpoint = "migration.dcnlab.com/eviction-checkpoint"
+
+// evictionRetrySeconds is how soon a held eviction is retried (kubectl drain retries on 429)
+const evictionRetrySeconds = 5
+
 func getenvDefault(k, d string) string {
         if v := os.Getenv(k); v != "" {
                 return v
@@ -58,7 +95,32 @@ func getenvDefault(k, d string) string {
         return d
 }
 
+func parseDurationDefault(v string, d time.Duration) time.Duration {
+        if p, err := time.ParseDuration(v); err == nil && p > 0 {
+                return p
+        }
+        return d
+}
+
+// dynamicClient returns a client for the cluster (in-cluster first, fall back to local for dev)
+func dynamicClient() (dynamic.Interface, error) {
+        cfg, err := rest.InClusterConfig()
+        if err != nil {
+                kubeconfig := filepath.Join(os.Getenv("HOME"), ".kube", "config")
+                cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+                if err != nil {
+                        return nil, err
+                }
+        }
+        return dynamic.NewForConfig(cfg)
+}
+
 func main() {
+        var err error
+        if kubeClient, err = dynamicClient(); err != nil {
+                panic(err)
+        }
+
         // Health endpoints for probes (avoid 404 causing restarts)
         http.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
                 w.WriteHeader(http.StatusOK)
@@ -70,6 +132,7 @@ func main() {
         })
 
         http.HandleFunc("/mutate", handleMutate)
+        http.HandleFunc("/validate-eviction", handleEviction)
 
         fmt.Println("Starting webhook server on :8443")
         if err := http.ListenAndServeTLS(":8443", "/tls/tls.crt", "/tls/tls.key", nil); err != nil {
@@ -110,23 +173,7 @@ func handleMutate(w http.ResponseWriter, r *http.Request) {
         fmt.Printf("💡 Pod CREATE admission: ns=%q name=%q generateName=%q containers=%d\n",
                 ns, pod.Name, pod.GenerateName, len(pod.Spec.Containers))
 
-        // Kube config (in-cluster first, fall back to local for dev)
-        cfg, err := rest.InClusterConfig()
-        if err != nil {
-                kubeconfig := filepath.Join(os.Getenv("HOME"), ".kube", "config")
-                cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
-                if err != nil {
-                        http.Error(w, "cannot get kubeconfig", http.StatusInternalServerError)
-                        return
-                }
-        }
-
-        dc, err := dynamic.NewForConfig(cfg)
-        if err != nil {
-                http.Error(w, "failed to create dynamic client", http.StatusInternalServerError)
-                return
-        }
-
+        dc := kubeClient
         gvr := schema.GroupVersionResource{Group: crGroup, Version: crVersion, Resource: crResource}
         crList, err := dc.Resource(gvr).Namespace(ns).List(context.TODO(), metav1.ListOptions{})
         if err != nil {
@@ -136,28 +183,47 @@ func handleMutate(w http.ResponseWriter, r *http.Request) {
                 return
         }
 
+        // A restore whose volumes were moved by a MigrationRun must not start on an empty claim:
+        // reject the pod until every claim it mounts is marked ready, the controller retries it
+        if requiresTransferredVolumes(crList.Items, &pod) {
+                if reason := pendingVolume(dc, ns, &pod); reason != "" {
+                        fmt.Printf("⏳ Rejecting pod %q: %s\n", pod.Name+pod.GenerateName, reason)
+                        writeDenied(w, review, reason)
+                        return
+                }
+        }
+
         targetName := pod.Name
         genPrefix := pod.GenerateName // may be empty; prefix match if present
 
+        // 1) Exact podName match (StatefulSet ordinals, bare Pods)
+        // 2) Otherwise a CR whose podSelector/podGenerateName selects the pod, chosen by the CR's
+        //    podMapping strategy (Ordinal, Label or RoundRobin over unclaimed CRs), so replicas
+        //    restore from distinct checkpoints regardless of their names
+        ri := dc.Resource(gvr).Namespace(ns)
+        var matched *unstructured.Unstructured
+        if targetName != "" {
+                for i := range crList.Items {
+                        specPodName, _, _ := unstructured.NestedString(crList.Items[i].Object, "spec", "podName")
+                        if specPodName == targetName {
+                                matched = &crList.Items[i]
+                                if claimed := claimCheckpoint(ri, matched, &pod, string(review.Request.UID)); claimed != nil {
+                                        matched = claimed
+                                }
+                                break
+                        }
+                }
+        }
+        if matched == nil && (genPrefix != "" || len(pod.Labels) > 0) {
+                matched = selectCheckpoint(ri, crList.Items, &pod, string(review.Request.UID))
+        }
+
         // Build container-name -> image map from matching CheckpointRestore
         imageMap := make(map[string]string)
         var defaultImage string
 
-        for _, it := range crList.Items {
-                spec, ok := it.Object["spec"].(map[string]interface{})
-                if !ok {
-                        continue
-                }
-                specPodName, _ := spec["podName"].(string)
-                specGenName, _ := spec["podGenerateName"].(string)
-
-                nameMatch := (targetName != "" && specPodName == targetName) ||
-                        (genPrefix != "" && specGenName != "" && strings.HasPrefix(specGenName, genPrefix)) ||
-                        (genPrefix != "" && specPodName != "" && strings.HasPrefix(specPodName, genPrefix))
-
-                if !nameMatch {
-                        continue
-                }
+        if matched != nil {
+                spec, _ := matched.Object["spec"].(map[string]interface{})
 
                 // Prefer spec.containers[]
                 if raw, ok := spec["containers"].([]interface{}); ok {
@@ -186,8 +252,7 @@ func handleMutate(w http.ResponseWriter, r *http.Request) {
                         }
                 }
 
-                fmt.Printf("✅ Matched CR %q → images=%v default=%q\n", it.GetName(), imageMap, defaultImage)
-                break
+                fmt.Printf("✅ Matched CR %q → images=%v default=%q\n", matched.GetName(), imageMap, defaultImage)
         }
 
         if len(imageMap) == 0 && defaultImage == "" {
@@ -230,6 +295,23 @@ func handleMutate(w http.ResponseWriter, r *http.Request) {
                 })
         }
 
+        // Record on the pod which checkpoint it was restored from
+        if len(patches) > 0 {
+                if pod.Labels == nil {
+                        patches = append(patches, map[string]interface{}{
+                                "op":    "add",
+                                "path":  "/metadata/labels",
+                                "value": map[string]string{labelCheckpointRestore: matched.GetName()},
+                        })
+                } else {
+                        patches = append(patches, map[string]interface{}{
+                                "op":    "add",
+                                "path":  "/metadata/labels/" + strings.ReplaceAll(labelCheckpointRestore, "/", "~1"),
+                                "value": matched.GetName(),
+                        })
+                }
+        }
+
         if len(patches) == 0 {
                 fmt.Println("ℹ️  Nothing to patch (images already as desired) → allowing without patch")
                 writeResponse(w, review, nil)
@@ -244,6 +326,136 @@ func handleMutate(w http.ResponseWriter, r *http.Request) {
         writeResponse(w, review, patchBytes)
 }
 
+// selectCheckpoint picks the CheckpointRestore for a pod that is not matched by name and claims it.
+// Ordinal and Label mappings are deterministic; RoundRobin takes the first CR nobody has claimed.
+// Claims are optimistic-concurrency status updates, so concurrent pod creations never take the
+// same checkpoint.
+func selectCheckpoint(ri dynamic.ResourceInterface, items []unstructured.Unstructured, pod *corev1.Pod, requestUID string) *unstructured.Unstructured {
+        candidates := make([]unstructured.Unstructured, 0, len(items))
+        for _, it := range items {
+                if selectsPod(&it, pod) {
+                        candidates = append(candidates, it)
+                }
+        }
+        sort.Slice(candidates, func(i, j int) bool { return candidates[i].GetName() < candidates[j].GetName() })
+
+        for i := range candidates {
+                cr := &candidates[i]
+                strategy, _, _ := unstructured.NestedString(cr.Object, "spec", "podMapping", "strategy")
+                switch strategy {
+                case "Ordinal":
+                        want, found, _ := unstructured.NestedInt64(cr.Object, "spec", "podOrdinal")
+                        if ord, ok := podOrdinal(pod); !found || !ok || ord != want {
+                                continue
+                        }
+                case "Label":
+                        key, _, _ := unstructured.NestedString(cr.Object, "spec", "podMapping", "labelKey")
+                        want, _, _ := unstructured.NestedString(cr.Object, "spec", "podLabelValue")
+                        if key == "" || want == "" || pod.Labels[key] != want {
+                                continue
+                        }
+                default: // RoundRobin
+                        if claimedBy, _, _ := unstructured.NestedString(cr.Object, "status", "claimedBy"); claimedBy != "" && claimedBy != requestUID {
+                                continue
+                        }
+                }
+                if claimed := claimCheckpoint(ri, cr, pod, requestUID); claimed != nil {
+                        return claimed
+                }
+                if strategy == "Ordinal" || strategy == "Label" {
+                        // Deterministic match: use it even if the claim could not be recorded
+                        return cr
+                }
+        }
+        fmt.Printf("❌ No CheckpointRestore selects pod generateName=%q labels=%v\n", pod.GenerateName, pod.Labels)
+        return nil
+}
+
+// selectsPod reports whether the CR's podSelector (or, without one, its podGenerateName) selects the pod
+func selectsPod(cr *unstructured.Unstructured, pod *corev1.Pod) bool {
+        if sel, _, _ := unstructured.NestedStringMap(cr.Object, "spec", "podSelector"); len(sel) > 0 {
+                for k, v := range sel {
+                        if pod.Labels[k] != v {
+                                return false
+                        }
+                }
+                return true
+        }
+        if pod.GenerateName == "" {
+                return false
+        }
+        specPodName, _, _ := unstructured.NestedString(cr.Object, "spec", "podName")
+        specGenName, _, _ := unstructured.NestedString(cr.Object, "spec", "podGenerateName")
+        return specGenName == pod.GenerateName ||
+                (specGenName == "" && specPodName != "" && strings.HasPrefix(specPodName, pod.GenerateName))
+}
+
+// requiresTransferredVolumes reports whether a CR that could restore the pod has spec.transferredVolumes
+func requiresTransferredVolumes(items []unstructured.Unstructured, pod *corev1.Pod) bool {
+        for i := range items {
+                if transferred, _, _ := unstructured.NestedBool(items[i].Object, "spec", "transferredVolumes"); !transferred {
+                        continue
+                }
+                specPodName, _, _ := unstructured.NestedString(items[i].Object, "spec", "podName")
+                if (pod.Name != "" && specPodName == pod.Name) || selectsPod(&items[i], pod) {
+                        return true
+                }
+        }
+        return false
+}
+
+// pendingVolume returns why a claim mounted by the pod is not ready yet, or "" when all of them are
+func pendingVolume(dc dynamic.Interface, ns string, pod *corev1.Pod) string {
+        pvcs := dc.Resource(schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}).Namespace(ns)
+        for _, v := range pod.Spec.Volumes {
+                if v.PersistentVolumeClaim == nil {
+                        continue
+                }
+                claim := v.PersistentVolumeClaim.ClaimName
+                pvc, err := pvcs.Get(context.TODO(), claim, metav1.GetOptions{})
+                if err != nil {
+                        return fmt.Sprintf("persistent volume claim %s is not available yet: %v", claim, err)
+                }
+                if pvc.GetAnnotations()[annoVolumeReady] != "true" {
+                        return fmt.Sprintf("persistent volume claim %s is still receiving data from the source cluster", claim)
+                }
+        }
+        return ""
+}
+
+// claimCheckpoint records in the CR status which pod took the checkpoint. It returns nil when
+// the CR changed meanwhile (e.g. another pod claimed it first).
+func claimCheckpoint(ri dynamic.ResourceInterface, cr *unstructured.Unstructured, pod *corev1.Pod, requestUID string) *unstructured.Unstructured {
+        claim := cr.DeepCopy()
+        _ = unstructured.SetNestedField(claim.Object, requestUID, "status", "claimedBy")
+        _ = unstructured.SetNestedField(claim.Object, pod.Name, "status", "restoredPodName")
+        _ = unstructured.SetNestedField(claim.Object, metav1.Now().UTC().Format(time.RFC3339), "status", "claimTime")
+        updated, err := ri.UpdateStatus(context.TODO(), claim, metav1.UpdateOptions{})
+        if err != nil {
+                fmt.Printf("⚠️  Claim of CR %q failed: %v\n", cr.GetName(), err)
+                return nil
+        }
+        return updated
+}
+
+// podOrdinal returns the ordinal of a pod from the apps.kubernetes.io/pod-index label or its name suffix
+func podOrdinal(pod *corev1.Pod) (int64, bool) {
+        if idx := pod.Labels["apps.kubernetes.io/pod-index"]; idx != "" {
+                if n, err := strconv.ParseInt(idx, 10, 32); err == nil {
+                        return n, true
+                }
+        }
+        i := strings.LastIndex(pod.Name, "-")
+        if i < 0 {
+                return 0, false
+        }
+        n, err := strconv.ParseInt(pod.Name[i+1:], 10, 32)
+        if err != nil || n < 0 {
+                return 0, false
+        }
+        return n, true
+}
+
 func writeResponse(w http.ResponseWriter, ar admissionv1.AdmissionReview, patch []byte) {
         resp := admissionv1.AdmissionReview{
                 TypeMeta: metav1.TypeMeta{
@@ -263,3 +475,254 @@ func writeResponse(w http.ResponseWriter, ar admissionv1.AdmissionReview, patch
         w.Header().Set("Content-Type", "application/json")
         _ = json.NewEncoder(w).Encode(resp)
 }
+
+func writeDenied(w http.ResponseWriter, ar admissionv1.AdmissionReview, reason string) {
+        resp := admissionv1.AdmissionReview{
+                TypeMeta: metav1.TypeMeta{
+                        APIVersion: "admission.k8s.io/v1",
+                        Kind:       "AdmissionReview",
+                },
+                Response: &admissionv1.AdmissionResponse{
+                        UID:     ar.Request.UID,
+                        Allowed: false,
+                        Result: &metav1.Status{
+                                Status:  metav1.StatusFailure,
+                                Reason:  metav1.StatusReasonForbidden,
+                                Code:    http.StatusForbidden,
+                                Message: reason,
+                        },
+                },
+        }
+        w.Header().Set("Content-Type", "application/json")
+        _ = json.NewEncoder(w).Encode(resp)
+}
+
+// handleEviction holds the eviction of a pod protected by a scheduled CheckpointBackup
+// until every such backup pushed a checkpoint that started after the eviction was requested.
+// The first request marks the pod for the checkpoint agent; evictions are answered with 429 so
+// drains retry them. A failed checkpoint or the timeout lets the eviction through. A mark older
+// than evictionWindow is left from an earlier eviction and is renewed for a new final checkpoint.
+func handleEviction(w http.ResponseWriter, r *http.Request) {
+        body, err := io.ReadAll(r.Body)
+        if err != nil {
+                http.Error(w, "could not read request", http.StatusBadRequest)
+                return
+        }
+        var review admissionv1.AdmissionReview
+        if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+                http.Error(w, "could not parse admission review", http.StatusBadRequest)
+                return
+        }
+        req := review.Request
+        if req.Operation != admissionv1.Create || req.SubResource != "eviction" {
+                writeResponse(w, review, nil)
+                return
+        }
+        ns, name := req.Namespace, req.Name
+
+        dc := kubeClient
+        pods := dc.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace(ns)
+        pod, err := pods.Get(context.TODO(), name, metav1.GetOptions{})
+        if err != nil {
+                writeResponse(w, review, nil)
+                return
+        }
+        backups := protectingBackups(dc, ns, name)
+        if len(backups) == 0 {
+                writeResponse(w, review, nil)
+                return
+        }
+
+        // 1) 첫 축출 요청 또는 이전 축출의 기록: Pod에 요청 시각을 (다시) 기록해 에이전트가
+        //    새 최종 체크포인트를 시작하도록 함
+        requested, err := time.Parse(time.RFC3339, pod.GetAnnotations()[annoEvictionCheckpoint])
+        if err != nil || time.Since(requested) > evictionWindow {
+                if req.DryRun != nil && *req.DryRun {
+                        writeResponse(w, review, nil)
+                        return
+                }
+                now := time.Now().UTC().Truncate(time.Second)
+                patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, annoEvictionCheckpoint, now.Format(time.RFC3339))
+                if _, err := pods.Patch(context.TODO(), name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+                        fmt.Printf("⚠️  Eviction of %s/%s allowed: cannot request checkpoint: %v\n", ns, name, err)
+                        writeResponse(w, review, nil)
+                        return
+                }
+                fmt.Printf("⏳ Holding eviction of %s/%s for a final checkpoint\n", ns, name)
+                writeRetryLater(w, review, fmt.Sprintf("pod %s is being checkpointed before eviction", name))
+                return
+        }
+
+        // 2) 현재 축출의 대기 시간 초과 또는 모든 백업이 요청 이후 체크포인트를 끝내면 허용
+        if time.Since(requested) > evictionCheckpointTimeout {
+                fmt.Printf("⚠️  Eviction of %s/%s allowed: no checkpoint within %s\n", ns, name, evictionCheckpointTimeout)
+                writeResponse(w, review, nil)
+                return
+        }
+        for i := range backups {
+                done, failed := checkpointAfter(&backups[i], requested)
+                if failed {
+                        fmt.Printf("⚠️  Eviction of %s/%s allowed: checkpoint %s failed\n", ns, name, backups[i].GetName())
+                        writeResponse(w, review, nil)
+                        return
+                }
+                if !done {
+                        writeRetryLater(w, review, fmt.Sprintf("pod %s is being checkpointed before eviction (CheckpointBackup %s)", name, backups[i].GetName()))
+                        return
+                }
+        }
+
+        // 3) 교체 Pod가 기존 CheckpointRestore 경로로 복원되도록 최종 체크포인트를 등록
+        if req.DryRun == nil || !*req.DryRun {
+                if err := ensureEvictionRestore(dc, pod, backups, requested); err != nil {
+                        fmt.Printf("⚠️  CheckpointRestore for evicted pod %s/%s not created: %v\n", ns, name, err)
+                }
+        }
+        fmt.Printf("✅ Eviction of %s/%s allowed: final checkpoint pushed\n", ns, name)
+        writeResponse(w, review, nil)
+}
+
+// ensureEvictionRestore creates the CheckpointRestore <pod>-eviction from the images the backups
+// built after the eviction was requested, pinned by digest, replacing the one of an earlier
+// eviction. The replacement pod takes it by name (StatefulSets) or by generateName (ReplicaSets).
+// It is owned by the first backup, so it is garbage collected with the backup.
+func ensureEvictionRestore(dc dynamic.Interface, pod *unstructured.Unstructured, backups []unstructured.Unstructured, requested time.Time) error {
+        var containers []interface{}
+        for i := range backups {
+                images, _, _ := unstructured.NestedSlice(backups[i].Object, "status", "builtImages")
+                latest := map[string]string{}
+                var order []string
+                // builtImages는 오래된 순이므로 컨테이너별 마지막 이미지를 사용
+                for _, it := range images {
+                        m, ok := it.(map[string]interface{})
+                        if !ok {
+                                continue
+                        }
+                        cname, _ := m["containerName"].(string)
+                        image, _ := m["imageName"].(string)
+                        digest, _ := m["digest"].(string)
+                        pushed, _ := m["pushed"].(bool)
+                        built, _ := m["buildTime"].(string)
+                        bt, err := time.Parse(time.RFC3339, built)
+                        if cname == "" || image == "" || digest == "" || !pushed || err != nil || bt.Before(requested) {
+                                continue
+                        }
+                        if _, seen := latest[cname]; !seen {
+                                order = append(order, cname)
+                        }
+                        // 태그는 다음 체크포인트가 덮어쓰므로 digest로 고정
+                        latest[cname] = imageRepository(image) + "@" + digest
+                }
+                for _, cname := range order {
+                        containers = append(containers, map[string]interface{}{"name": cname, "image": latest[cname]})
+                }
+        }
+        if len(containers) == 0 {
+                return fmt.Errorf("no image pushed after %s", requested.Format(time.RFC3339))
+        }
+
+        name := pod.GetName() + "-eviction"
+        ri := dc.Resource(schema.GroupVersionResource{Group: crGroup, Version: crVersion, Resource: crResource}).Namespace(pod.GetNamespace())
+        // 이전 축출의 CheckpointRestore는 이미 claim되었을 수 있으므로 새로 만듦
+        if err := ri.Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+                return err
+        }
+        spec := map[string]interface{}{
+                "backupRef":  map[string]interface{}{"name": backups[0].GetName()},
+                "podName":    pod.GetName(),
+                "containers": containers,
+        }
+        if gen := pod.GetGenerateName(); gen != "" {
+                spec["podGenerateName"] = gen
+        }
+        restore := &unstructured.Unstructured{Object: map[string]interface{}{
+                "apiVersion": crGroup + "/" + crVersion,
+                "kind":       "CheckpointRestore",
+                "metadata": map[string]interface{}{
+                        "name":        name,
+                        "namespace":   pod.GetNamespace(),
+                        "annotations": map[string]interface{}{annoEvictionCheckpoint: pod.GetName()},
+                        "ownerReferences": []interface{}{map[string]interface{}{
+                                "apiVersion": crGroup + "/" + crVersion,
+                                "kind":       "CheckpointBackup",
+                                "name":       backups[0].GetName(),
+                                "uid":        string(backups[0].GetUID()),
+                        }},
+                },
+                "spec": spec,
+        }}
+        _, err := ri.Create(context.TODO(), restore, metav1.CreateOptions{})
+        return err
+}
+
+// imageRepository strips the tag and digest from an image reference
+func imageRepository(image string) string {
+        if i := strings.Index(image, "@"); i >= 0 {
+                image = image[:i]
+        }
+        if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+                image = image[:i]
+        }
+        return image
+}
+
+// protectingBackups returns the scheduled, not suspended CheckpointBackups of a pod
+func protectingBackups(dc dynamic.Interface, ns, podName string) []unstructured.Unstructured {
+        list, err := dc.Resource(backupGVR).Namespace(ns).List(context.TODO(), metav1.ListOptions{LabelSelector: "target-pod=" + podName})
+        if err != nil {
+                fmt.Printf("⚠️  List checkpointbackups in %s failed: %v\n", ns, err)
+                return nil
+        }
+        var out []unstructured.Unstructured
+        for _, b := range list.Items {
+                ref, _, _ := unstructured.NestedString(b.Object, "spec", "podRef", "name")
+                schedule, _, _ := unstructured.NestedString(b.Object, "spec", "schedule")
+                suspended, _, _ := unstructured.NestedBool(b.Object, "spec", "suspend")
+                if ref != podName || schedule == "" || schedule == "immediately" || suspended {
+                        continue
+                }
+                out = append(out, b)
+        }
+        return out
+}
+
+// checkpointAfter reports whether the backup's last run started after t and completed, or failed
+func checkpointAfter(b *unstructured.Unstructured, t time.Time) (done, failed bool) {
+        start, _, _ := unstructured.NestedString(b.Object, "status", "progress", "startTime")
+        completion, _, _ := unstructured.NestedString(b.Object, "status", "progress", "completionTime")
+        phase, _, _ := unstructured.NestedString(b.Object, "status", "phase")
+        started, err := time.Parse(time.RFC3339, start)
+        if err != nil || started.Before(t) || completion == "" {
+                return false, false
+        }
+        switch phase {
+        case "Completed", "CompletedPodDeleted":
+                return true, false
+        case "Failed", "CompletedWithError":
+                return false, true
+        }
+        return false, false
+}
+
+// writeRetryLater denies a request with 429 Too Many Requests, which clients such as kubectl drain retry
+func writeRetryLater(w http.ResponseWriter, ar admissionv1.AdmissionReview, reason string) {
+        resp := admissionv1.AdmissionReview{
+                TypeMeta: metav1.TypeMeta{
+                        APIVersion: "admission.k8s.io/v1",
+                        Kind:       "AdmissionReview",
+                },
+                Response: &admissionv1.AdmissionResponse{
+                        UID:     ar.Request.UID,
+                        Allowed: false,
+                        Result: &metav1.Status{
+                                Status:  metav1.StatusFailure,
+                                Reason:  metav1.StatusReasonTooManyRequests,
+                                Code:    http.StatusTooManyRequests,
+                                Message: reason,
+                                Details: &metav1.StatusDetails{RetryAfterSeconds: evictionRetrySeconds},
+                        },
+                },
+        }
+        w.Header().Set("Content-Type", "application/json")
+        _ = json.NewEncoder(w).Encode(resp)
+}
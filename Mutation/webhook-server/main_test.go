@@ -0,0 +1,380 @@
+// Copyright 2025 Jeong Seungjun
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+        "bytes"
+        "context"
+        "encoding/json"
+        "net/http"
+        "net/http/httptest"
+        "strings"
+        "testing"
+        "time"
+
+        admissionv1 "k8s.io/api/admission/v1"
+        apierrors "k8s.io/apimachinery/pkg/api/errors"
+        metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+        "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+        "k8s.io/apimachinery/pkg/runtime"
+        "k8s.io/apimachinery/pkg/runtime/schema"
+        "k8s.io/apimachinery/pkg/types"
+        "k8s.io/client-go/dynamic"
+        dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var (
+        podGVR     = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+        restoreGVR = schema.GroupVersionResource{Group: crGroup, Version: crVersion, Resource: crResource}
+)
+
+func newFakeClient(objs ...runtime.Object) dynamic.Interface {
+        return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+                podGVR:     "PodList",
+                backupGVR:  "CheckpointBackupList",
+                restoreGVR: "CheckpointRestoreList",
+        }, objs...)
+}
+
+func newPod(name, requested string) *unstructured.Unstructured {
+        pod := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Pod"}}
+        pod.SetNamespace("app")
+        pod.SetName(name)
+        pod.SetUID("pod-uid")
+        if requested != "" {
+                pod.SetAnnotations(map[string]string{annoEvictionCheckpoint: requested})
+        }
+        return pod
+}
+
+// newBackup returns a scheduled CheckpointBackup of the pod whose last run started at start
+func newBackup(name, podName string, start time.Time, phase string, images ...map[string]interface{}) *unstructured.Unstructured {
+        b := &unstructured.Unstructured{Object: map[string]interface{}{
+                "apiVersion": crGroup + "/" + crVersion,
+                "kind":       "CheckpointBackup",
+                "spec": map[string]interface{}{
+                        "schedule": "*/5 * * * *",
+                        "podRef":   map[string]interface{}{"name": podName, "namespace": "app"},
+                },
+        }}
+        b.SetNamespace("app")
+        b.SetName(name)
+        b.SetUID(types.UID(name + "-uid"))
+        b.SetLabels(map[string]string{"target-pod": podName})
+        if !start.IsZero() {
+                status := map[string]interface{}{
+                        "phase": phase,
+                        "progress": map[string]interface{}{
+                                "startTime":      start.UTC().Format(time.RFC3339),
+                                "completionTime": start.Add(10 * time.Second).UTC().Format(time.RFC3339),
+                        },
+                }
+                var built []interface{}
+                for _, img := range images {
+                        built = append(built, img)
+                }
+                if built != nil {
+                        status["builtImages"] = built
+                }
+                b.Object["status"] = status
+        }
+        return b
+}
+
+func builtImage(container, image, digest string, built time.Time, pushed bool) map[string]interface{} {
+        return map[string]interface{}{
+                "containerName": container,
+                "imageName":     image,
+                "digest":        digest,
+                "buildTime":     built.UTC().Format(time.RFC3339),
+                "pushed":        pushed,
+        }
+}
+
+func TestCheckpointAfter(t *testing.T) {
+        requested := time.Now().UTC().Truncate(time.Second)
+        tests := []struct {
+                name       string
+                backup     *unstructured.Unstructured
+                wantDone   bool
+                wantFailed bool
+        }{
+                {name: "never ran", backup: newBackup("b", "web-0", time.Time{}, "")},
+                {name: "run started before the request", backup: newBackup("b", "web-0", requested.Add(-time.Minute), "Completed")},
+                {name: "completed after the request", backup: newBackup("b", "web-0", requested, "Completed"), wantDone: true},
+                {name: "pod deleted after the checkpoint", backup: newBackup("b", "web-0", requested.Add(time.Second), "CompletedPodDeleted"), wantDone: true},
+                {name: "failed after the request", backup: newBackup("b", "web-0", requested.Add(time.Second), "Failed"), wantFailed: true},
+                {name: "completed with error", backup: newBackup("b", "web-0", requested.Add(time.Second), "CompletedWithError"), wantFailed: true},
+                {name: "still running", backup: newBackup("b", "web-0", requested.Add(time.Second), "Running")},
+                {
+                        name: "no completion time",
+                        backup: func() *unstructured.Unstructured {
+                                b := newBackup("b", "web-0", requested.Add(time.Second), "Completed")
+                                unstructured.RemoveNestedField(b.Object, "status", "progress", "completionTime")
+                                return b
+                        }(),
+                },
+        }
+        for _, tt := range tests {
+                t.Run(tt.name, func(t *testing.T) {
+                        done, failed := checkpointAfter(tt.backup, requested)
+                        if done != tt.wantDone || failed != tt.wantFailed {
+                                t.Errorf("checkpointAfter = (%v, %v), want (%v, %v)", done, failed, tt.wantDone, tt.wantFailed)
+                        }
+                })
+        }
+}
+
+func TestImageRepository(t *testing.T) {
+        for image, want := range map[string]string{
+                "registry:5000/ckpt/web:latest":        "registry:5000/ckpt/web",
+                "registry:5000/ckpt/web":               "registry:5000/ckpt/web",
+                "ckpt/web@sha256:abc":                  "ckpt/web",
+                "registry:5000/ckpt/web:v1@sha256:abc": "registry:5000/ckpt/web",
+                "web":                                  "web",
+        } {
+                if got := imageRepository(image); got != want {
+                        t.Errorf("imageRepository(%q) = %q, want %q", image, got, want)
+                }
+        }
+}
+
+func TestEnsureEvictionRestore(t *testing.T) {
+        ctx := context.Background()
+        requested := time.Now().UTC().Truncate(time.Second)
+        after := requested.Add(30 * time.Second)
+        longName := "web-" + strings.Repeat("a", 70) + "-0"
+
+        backups := []unstructured.Unstructured{
+                *newBackup("web-0-app", longName, requested, "Completed",
+                        builtImage("app", "registry:5000/ckpt/app:latest", "sha256:old", requested.Add(-time.Hour), true),
+                        builtImage("app", "registry:5000/ckpt/app:latest", "sha256:new", after, true),
+                        builtImage("sidecar", "registry:5000/ckpt/sidecar:latest", "sha256:unpushed", after, false),
+                        builtImage("sidecar", "registry:5000/ckpt/sidecar:latest", "", after, true)),
+                *newBackup("web-0-log", longName, requested, "Completed",
+                        builtImage("log", "registry:5000/ckpt/log:latest", "sha256:log", after, true)),
+        }
+        pod := newPod(longName, requested.Format(time.RFC3339))
+        pod.SetGenerateName("web-")
+
+        // 이전 축출에서 만든 Restore는 교체됨
+        old := &unstructured.Unstructured{Object: map[string]interface{}{
+                "apiVersion": crGroup + "/" + crVersion,
+                "kind":       "CheckpointRestore",
+                "spec":       map[string]interface{}{"podName": longName},
+        }}
+        old.SetNamespace("app")
+        old.SetName(longName + "-eviction")
+        dc := newFakeClient(old)
+
+        if err := ensureEvictionRestore(dc, pod, backups, requested); err != nil {
+                t.Fatalf("ensureEvictionRestore: %v", err)
+        }
+        restore, err := dc.Resource(restoreGVR).Namespace("app").Get(ctx, longName+"-eviction", metav1.GetOptions{})
+        if err != nil {
+                t.Fatal(err)
+        }
+        containers, _, _ := unstructured.NestedSlice(restore.Object, "spec", "containers")
+        want := []interface{}{
+                map[string]interface{}{"name": "app", "image": "registry:5000/ckpt/app@sha256:new"},
+                map[string]interface{}{"name": "log", "image": "registry:5000/ckpt/log@sha256:log"},
+        }
+        if !equalJSON(containers, want) {
+                t.Errorf("containers = %v, want %v", containers, want)
+        }
+        if got, _, _ := unstructured.NestedString(restore.Object, "spec", "podGenerateName"); got != "web-" {
+                t.Errorf("podGenerateName = %q, want web-", got)
+        }
+        if got, _, _ := unstructured.NestedString(restore.Object, "spec", "backupRef", "name"); got != "web-0-app" {
+                t.Errorf("backupRef = %q, want web-0-app", got)
+        }
+        if got := restore.GetAnnotations()[annoEvictionCheckpoint]; got != longName {
+                t.Errorf("annotation %s = %q, want the pod name", annoEvictionCheckpoint, got)
+        }
+        if len(restore.GetLabels()) != 0 {
+                t.Errorf("labels = %v, want none (pod names may exceed 63 characters)", restore.GetLabels())
+        }
+        owners := restore.GetOwnerReferences()
+        if len(owners) != 1 || owners[0].Kind != "CheckpointBackup" || owners[0].Name != "web-0-app" || owners[0].UID != "web-0-app-uid" {
+                t.Errorf("ownerReferences = %+v, want the first CheckpointBackup", owners)
+        }
+
+        // 요청 이후 푸시된 이미지가 없으면 만들지 않음
+        stale := []unstructured.Unstructured{*newBackup("web-1-app", "web-1", requested, "Completed",
+                builtImage("app", "registry:5000/ckpt/app:latest", "sha256:old", requested.Add(-time.Minute), true))}
+        if err := ensureEvictionRestore(dc, newPod("web-1", ""), stale, requested); err == nil {
+                t.Error("ensureEvictionRestore succeeded without an image pushed after the request")
+        }
+        if _, err := dc.Resource(restoreGVR).Namespace("app").Get(ctx, "web-1-eviction", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+                t.Errorf("restore web-1-eviction: error = %v, want NotFound", err)
+        }
+}
+
+func TestHandleEviction(t *testing.T) {
+        ctx := context.Background()
+        now := time.Now().UTC().Truncate(time.Second)
+        recent := now.Add(-time.Minute)
+        image := builtImage("app", "registry:5000/ckpt/app:latest", "sha256:new", now, true)
+
+        tests := []struct {
+                name        string
+                subResource string
+                dryRun      bool
+                requested   time.Time // 0 = 어노테이션 없음
+                backups     []runtime.Object
+
+                wantAllowed bool
+                wantStamped bool // Pod 어노테이션이 새 요청 시각으로 기록됨
+                wantRestore bool
+        }{
+                {
+                        name:        "not an eviction",
+                        subResource: "status",
+                        backups:     []runtime.Object{newBackup("web-0-app", "web-0", time.Time{}, "")},
+                        wantAllowed: true,
+                },
+                {
+                        name:        "pod without a scheduled backup",
+                        wantAllowed: true,
+                },
+                {
+                        name:        "first request marks the pod",
+                        backups:     []runtime.Object{newBackup("web-0-app", "web-0", time.Time{}, "")},
+                        wantStamped: true,
+                },
+                {
+                        name:        "dry run does not mark the pod",
+                        dryRun:      true,
+                        backups:     []runtime.Object{newBackup("web-0-app", "web-0", time.Time{}, "")},
+                        wantAllowed: true,
+                },
+                {
+                        name:        "mark of an earlier eviction is renewed",
+                        requested:   now.Add(-evictionWindow - time.Minute),
+                        backups:     []runtime.Object{newBackup("web-0-app", "web-0", now.Add(-evictionWindow), "Completed", image)},
+                        wantStamped: true,
+                },
+                {
+                        name:      "checkpoint still running",
+                        requested: recent,
+                        backups:   []runtime.Object{newBackup("web-0-app", "web-0", recent.Add(time.Second), "Running")},
+                },
+                {
+                        name:        "failed checkpoint lets the eviction through",
+                        requested:   recent,
+                        backups:     []runtime.Object{newBackup("web-0-app", "web-0", recent.Add(time.Second), "Failed")},
+                        wantAllowed: true,
+                },
+                {
+                        name:        "checkpoint timeout lets the eviction through",
+                        requested:   now.Add(-evictionCheckpointTimeout - time.Second),
+                        backups:     []runtime.Object{newBackup("web-0-app", "web-0", time.Time{}, "")},
+                        wantAllowed: true,
+                },
+                {
+                        name:      "waits for every backup of the pod",
+                        requested: recent,
+                        backups: []runtime.Object{
+                                newBackup("web-0-app", "web-0", recent.Add(time.Second), "Completed", image),
+                                newBackup("web-0-log", "web-0", recent.Add(-time.Hour), "Completed"),
+                        },
+                },
+                {
+                        name:        "pushed checkpoint lets the eviction through and registers the restore",
+                        requested:   recent,
+                        backups:     []runtime.Object{newBackup("web-0-app", "web-0", recent.Add(time.Second), "Completed", image)},
+                        wantAllowed: true,
+                        wantRestore: true,
+                },
+                {
+                        name:        "suspended backup does not hold the eviction",
+                        backups:     []runtime.Object{suspended(newBackup("web-0-app", "web-0", time.Time{}, ""))},
+                        wantAllowed: true,
+                },
+        }
+        for _, tt := range tests {
+                t.Run(tt.name, func(t *testing.T) {
+                        requested := ""
+                        if !tt.requested.IsZero() {
+                                requested = tt.requested.Format(time.RFC3339)
+                        }
+                        kubeClient = newFakeClient(append([]runtime.Object{newPod("web-0", requested)}, tt.backups...)...)
+                        t.Cleanup(func() { kubeClient = nil })
+
+                        subResource := tt.subResource
+                        if subResource == "" {
+                                subResource = "eviction"
+                        }
+                        review := admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+                                UID:         "req-1",
+                                Namespace:   "app",
+                                Name:        "web-0",
+                                Operation:   admissionv1.Create,
+                                SubResource: subResource,
+                                DryRun:      &tt.dryRun,
+                        }}
+                        body, _ := json.Marshal(review)
+                        rec := httptest.NewRecorder()
+                        handleEviction(rec, httptest.NewRequest(http.MethodPost, "/validate-eviction", bytes.NewReader(body)))
+
+                        var resp admissionv1.AdmissionReview
+                        if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Response == nil {
+                                t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+                        }
+                        if resp.Response.UID != "req-1" {
+                                t.Errorf("response UID = %q, want req-1", resp.Response.UID)
+                        }
+                        if resp.Response.Allowed != tt.wantAllowed {
+                                t.Fatalf("allowed = %v, want %v (%+v)", resp.Response.Allowed, tt.wantAllowed, resp.Response.Result)
+                        }
+                        if !tt.wantAllowed && (resp.Response.Result == nil || resp.Response.Result.Code != http.StatusTooManyRequests) {
+                                t.Errorf("denied with %+v, want 429", resp.Response.Result)
+                        }
+
+                        pod, err := kubeClient.Resource(podGVR).Namespace("app").Get(ctx, "web-0", metav1.GetOptions{})
+                        if err != nil {
+                                t.Fatal(err)
+                        }
+                        got := pod.GetAnnotations()[annoEvictionCheckpoint]
+                        if tt.wantStamped {
+                                stamped, err := time.Parse(time.RFC3339, got)
+                                if err != nil || time.Since(stamped) > time.Minute {
+                                        t.Errorf("pod annotation = %q, want the current time", got)
+                                }
+                        } else if got != requested {
+                                t.Errorf("pod annotation = %q, want %q unchanged", got, requested)
+                        }
+
+                        _, err = kubeClient.Resource(restoreGVR).Namespace("app").Get(ctx, "web-0-eviction", metav1.GetOptions{})
+                        if tt.wantRestore && err != nil {
+                                t.Errorf("restore web-0-eviction: %v", err)
+                        }
+                        if !tt.wantRestore && !apierrors.IsNotFound(err) {
+                                t.Errorf("restore web-0-eviction: error = %v, want NotFound", err)
+                        }
+                })
+        }
+}
+
+func suspended(b *unstructured.Unstructured) *unstructured.Unstructured {
+        _ = unstructured.SetNestedField(b.Object, true, "spec", "suspend")
+        return b
+}
+
+func equalJSON(a, b interface{}) bool {
+        ja, _ := json.Marshal(a)
+        jb, _ := json.Marshal(b)
+        return bytes.Equal(ja, jb)
+}
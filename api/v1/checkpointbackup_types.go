@@ -23,12 +23,70 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// ConcurrencyPolicy decides what happens when a scheduled checkpoint is due while the previous
+// run of the backup is still in progress. Runs of one backup never overlap.
+// +kubebuilder:validation:Enum=Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// ForbidConcurrent keeps the run in progress; the due run starts when it ends, if still
+	// within startingDeadlineSeconds
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+
+	// ReplaceConcurrent cancels the run in progress and starts the due run
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// CheckpointTriggerType is an event that starts a checkpoint outside of the schedule
+// +kubebuilder:validation:Enum=NodeTaint;NodeCordon;PodAnnotation;ClusterNotReady;Eviction
+type CheckpointTriggerType string
+
+const (
+	// TriggerNodeTaint fires when the pod's node gets a NoSchedule or NoExecute taint
+	TriggerNodeTaint CheckpointTriggerType = "NodeTaint"
+
+	// TriggerNodeCordon fires when the pod's node is cordoned, e.g. at the start of a drain
+	TriggerNodeCordon CheckpointTriggerType = "NodeCordon"
+
+	// TriggerPodAnnotation fires when the migration.dcnlab.com/checkpoint-request annotation of
+	// the pod is set or changes
+	TriggerPodAnnotation CheckpointTriggerType = "PodAnnotation"
+
+	// TriggerClusterNotReady fires when Karmada reports the member cluster NotReady
+	TriggerClusterNotReady CheckpointTriggerType = "ClusterNotReady"
+
+	// TriggerEviction fires when the eviction webhook holds an eviction of the pod. Every
+	// scheduled backup has it; it does not need to be declared.
+	TriggerEviction CheckpointTriggerType = "Eviction"
+)
+
+// CheckpointTrigger declares an event that starts a checkpoint of the pod
+type CheckpointTrigger struct {
+	// Type of the event
+	// +required
+	Type CheckpointTriggerType `json:"type"`
+
+	// TaintKeys limits NodeTaint to taints with these keys. Any NoSchedule or NoExecute taint
+	// fires the trigger when empty.
+	// +optional
+	TaintKeys []string `json:"taintKeys,omitempty"`
+}
+
 // CheckpointBackupSpec defines the desired state of CheckpointBackup
 type CheckpointBackupSpec struct {
 	// Schedule specifies the backup schedule in cron format or "immediately" for one-time execution
 	// +required
 	Schedule string `json:"schedule"`
 
+	// TimeZone of the schedule, an IANA name such as "Asia/Seoul". Defaults to UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// Suspend stops new scheduled runs; a run in progress completes. Runs missed while
+	// suspended are caught up on resume within startingDeadlineSeconds.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
 	// StopPod specifies whether to delete the pod after checkpointing (default: false)
 	// When true, the pod will be deleted after successful checkpoint creation and no further schedules will be processed
 	// +optional
@@ -50,6 +108,36 @@ type CheckpointBackupSpec struct {
 	// Containers specifies the container configurations for checkpoints
 	// +optional
 	Containers []Container `json:"containers,omitempty"`
+
+	// CheckpointTimeout bounds one checkpoint run of the pod: the dump, image build and push of
+	// every container. Defaults to the checkpoint agent's --checkpoint-timeout.
+	// +optional
+	CheckpointTimeout *metav1.Duration `json:"checkpointTimeout,omitempty"`
+
+	// Priority orders the backup in the node's checkpoint queue when more checkpoints are due
+	// than the agent runs at once: higher values run first. Backups of equal priority are
+	// served in turn across namespaces.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// StartingDeadlineSeconds is how late a scheduled run may still start, e.g. after the
+	// checkpoint agent was restarted. Older missed runs are skipped. When unset the most
+	// recent missed run is always caught up.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// ConcurrencyPolicy applies when a scheduled run is due while the previous one is in progress
+	// +kubebuilder:default=Forbid
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Triggers start a checkpoint when an event happens, in addition to the schedule, so the
+	// pod is checkpointed before it is evicted. Runs overlap as set by concurrencyPolicy.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Triggers []CheckpointTrigger `json:"triggers,omitempty"`
 }
 
 // CheckpointBackupStatus defines the observed state of CheckpointBackup.
@@ -62,6 +150,14 @@ type CheckpointBackupStatus struct {
 	// +optional
 	LastCheckpointTime *metav1.Time `json:"lastCheckpointTime,omitempty"`
 
+	// LastScheduleTime is the scheduled time of the last run the agent started
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is when the next scheduled run is due
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
 	// Message provides additional information about the current state
 	// +optional
 	Message string `json:"message,omitempty"`
@@ -74,13 +170,71 @@ type CheckpointBackupStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
-	// BuiltImages contains the list of checkpoint images that were successfully built
+	// BuiltImages contains the checkpoint images that were successfully built, oldest first.
+	// Every push is kept with its digest so older checkpoints can still be restored.
 	// +optional
 	BuiltImages []BuiltImage `json:"builtImages,omitempty"`
 
 	// CheckpointFiles contains the paths to checkpoint files that have been created
 	// +optional
 	CheckpointFiles []CheckpointFile `json:"checkpointFiles,omitempty"`
+
+	// Progress reports the checkpoint run in progress, or the last one
+	// +optional
+	Progress *CheckpointProgress `json:"progress,omitempty"`
+
+	// Triggers records the event each trigger last started a checkpoint for
+	// +optional
+	Triggers []CheckpointTriggerStatus `json:"triggers,omitempty"`
+}
+
+// CheckpointTriggerStatus records the event that last fired a trigger
+type CheckpointTriggerStatus struct {
+	// Type of the trigger
+	// +required
+	Type CheckpointTriggerType `json:"type"`
+
+	// Event identifies the event, e.g. the matching taints of the node or the annotation value.
+	// The trigger fires again when it changes.
+	// +required
+	Event string `json:"event"`
+
+	// Time is when the checkpoint for the event was started
+	// +required
+	Time metav1.Time `json:"time"`
+}
+
+// CheckpointProgress reports how far a checkpoint run got
+type CheckpointProgress struct {
+	// StartTime is when the run started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Deadline is when the run is cancelled if it has not completed; it moves later by the
+	// time the run waits for the node's worker pool
+	// +optional
+	Deadline *metav1.Time `json:"deadline,omitempty"`
+
+	// CompletionTime is when the run ended, successfully or not
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// TotalContainers is the number of containers the run checkpoints
+	// +optional
+	TotalContainers int32 `json:"totalContainers,omitempty"`
+
+	// CompletedContainers is the number of containers checkpointed so far
+	// +optional
+	CompletedContainers int32 `json:"completedContainers,omitempty"`
+
+	// CurrentContainer is the container being checkpointed
+	// +optional
+	CurrentContainer string `json:"currentContainer,omitempty"`
+
+	// Queued is the step the run is waiting for a free slot of the node's worker pool for:
+	// Checkpoint, Build or Push
+	// +optional
+	Queued string `json:"queued,omitempty"`
 }
 
 // CheckpointFile represents a checkpoint file that has been created
@@ -115,6 +269,11 @@ type BuiltImage struct {
 	// Pushed indicates whether the image was pushed to a registry
 	// +optional
 	Pushed bool `json:"pushed,omitempty"`
+
+	// Digest is the manifest digest of the pushed image; the tag in ImageName is
+	// overwritten by the next checkpoint, the digest is not
+	// +optional
+	Digest string `json:"digest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
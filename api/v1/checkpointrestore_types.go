@@ -34,15 +34,55 @@ type CheckpointRestoreSpec struct {
 	// +required
 	PodName string `json:"podName"`
 
+	// PodGenerateName matches pods by generateName when pod names are not stable
+	// Each matching pod claims a different CheckpointRestore, so replicas restore
+	// from distinct checkpoints regardless of their names
+	// +optional
+	PodGenerateName string `json:"podGenerateName,omitempty"`
+
+	// PodSelector lists labels a new pod must carry to take this checkpoint
+	// Matching on the workload's labels keeps restores working when pod names change
+	// +optional
+	PodSelector map[string]string `json:"podSelector,omitempty"`
+
+	// PodMapping specifies how a new pod is matched to this checkpoint
+	// When not set, pods are matched by PodName and then PodGenerateName
+	// +optional
+	PodMapping *PodMapping `json:"podMapping,omitempty"`
+
+	// PodOrdinal is the ordinal of the checkpointed pod, used by the Ordinal strategy
+	// +optional
+	PodOrdinal *int32 `json:"podOrdinal,omitempty"`
+
+	// PodLabelValue is the value of PodMapping.LabelKey on the checkpointed pod, used by the Label strategy
+	// +optional
+	PodLabelValue string `json:"podLabelValue,omitempty"`
+
 	// Containers specifies the container configurations for restore
 	// +optional
 	Containers []Container `json:"containers,omitempty"`
+
+	// TransferredVolumes makes the restore webhook reject a pod until every PersistentVolumeClaim
+	// it mounts was transferred from the source cluster and marked ready
+	// +optional
+	TransferredVolumes bool `json:"transferredVolumes,omitempty"`
 }
 
 // CheckpointRestoreStatus defines the observed state of CheckpointRestore.
 type CheckpointRestoreStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ClaimedBy is the UID of the pod admission request that took this checkpoint
+	// +optional
+	ClaimedBy string `json:"claimedBy,omitempty"`
+
+	// RestoredPodName is the name of the pod restored from this checkpoint
+	// Pods created with generateName have no name at admission; they carry the
+	// migration.dcnlab.com/checkpoint-restore label instead
+	// +optional
+	RestoredPodName string `json:"restoredPodName,omitempty"`
+
+	// ClaimTime is when the checkpoint was taken by a pod
+	// +optional
+	ClaimTime *metav1.Time `json:"claimTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MigrationRunPhase is the step a MigrationRun is in
+type MigrationRunPhase string
+
+const (
+	// MigrationRunPending validates the run and records the current placement
+	MigrationRunPending MigrationRunPhase = "Pending"
+
+	// MigrationRunSuspending suspends dispatching of the workload on Karmada
+	MigrationRunSuspending MigrationRunPhase = "Suspending"
+
+	// MigrationRunTransferringVolumes copies the data of the pods' volumes on the source clusters
+	MigrationRunTransferringVolumes MigrationRunPhase = "TransferringVolumes"
+
+	// MigrationRunCheckpointing takes a final checkpoint of every pod and waits for the push
+	MigrationRunCheckpointing MigrationRunPhase = "Checkpointing"
+
+	// MigrationRunRetargeting moves the PropagationPolicy placement to the destination cluster
+	MigrationRunRetargeting MigrationRunPhase = "Retargeting"
+
+	// MigrationRunRestoring waits for the CheckpointRestores to reach the destination cluster
+	MigrationRunRestoring MigrationRunPhase = "Restoring"
+
+	// MigrationRunResuming resumes dispatching so the workload starts on the destination cluster
+	MigrationRunResuming MigrationRunPhase = "Resuming"
+
+	// MigrationRunSucceeded means the workload was moved to the destination cluster
+	MigrationRunSucceeded MigrationRunPhase = "Succeeded"
+
+	// MigrationRunFailed means a step failed and the original placement was restored
+	MigrationRunFailed MigrationRunPhase = "Failed"
+
+	// MigrationRunCancelled means the run was cancelled and the original placement was restored
+	MigrationRunCancelled MigrationRunPhase = "Cancelled"
+)
+
+// MigrationRunSpec defines the desired state of MigrationRun
+type MigrationRunSpec struct {
+	// StatefulMigrationName is the StatefulMigration, in the same namespace, whose workload is migrated
+	// +required
+	StatefulMigrationName string `json:"statefulMigrationName"`
+
+	// DestinationCluster is the member cluster the workload is moved to
+	// +required
+	DestinationCluster string `json:"destinationCluster"`
+
+	// Timeout is how long the run may take before it fails and rolls back (default: 30m)
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Cancel stops a run that has not finished yet and restores the original placement
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
+}
+
+// MigrationRunCheckpoint describes the final checkpoint of a single pod
+type MigrationRunCheckpoint struct {
+	// Name of the CheckpointBackup created for the final checkpoint
+	// +required
+	Name string `json:"name"`
+
+	// Cluster the pod runs on
+	// +required
+	Cluster string `json:"cluster"`
+
+	// PodName is the name of the checkpointed pod
+	// +required
+	PodName string `json:"podName"`
+
+	// Phase of the CheckpointBackup reported by the checkpoint agent
+	// +optional
+	Phase string `json:"phase,omitempty"`
+}
+
+// MigrationRunVolume describes the transfer of one PersistentVolumeClaim
+type MigrationRunVolume struct {
+	// ClaimName of the PersistentVolumeClaim, the same on the source and destination clusters
+	// +required
+	ClaimName string `json:"claimName"`
+
+	// Cluster the claim is copied from
+	// +required
+	Cluster string `json:"cluster"`
+
+	// PodName is the pod that mounts the claim
+	// +required
+	PodName string `json:"podName"`
+
+	// Phase of the transfer: Copying, Copied, Restoring, Ready, or CleanedUp once the snapshots
+	// of a succeeded run were deleted
+	// +optional
+	Phase string `json:"phase,omitempty"`
+}
+
+// MigrationRunStatus defines the observed state of MigrationRun.
+type MigrationRunStatus struct {
+	// Phase is the step the run is in
+	// +optional
+	Phase MigrationRunPhase `json:"phase,omitempty"`
+
+	// Message provides additional information about the current step
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ResourceBinding is the Karmada ResourceBinding of the workload
+	// +optional
+	ResourceBinding string `json:"resourceBinding,omitempty"`
+
+	// PropagationPolicy is the Karmada PropagationPolicy (namespace/name) that places the workload
+	// +optional
+	PropagationPolicy string `json:"propagationPolicy,omitempty"`
+
+	// SourceClusters are the clusters the workload ran on when the run started
+	// +optional
+	SourceClusters []string `json:"sourceClusters,omitempty"`
+
+	// Volumes reports the transfer of each PersistentVolumeClaim when spec.volumeTransfer is set
+	// on the StatefulMigration
+	// +optional
+	Volumes []MigrationRunVolume `json:"volumes,omitempty"`
+
+	// Checkpoints reports the final checkpoint of each pod
+	// +optional
+	Checkpoints []MigrationRunCheckpoint `json:"checkpoints,omitempty"`
+
+	// StartTime is when the run started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the run succeeded, failed or was cancelled
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// RolledBack is true when the original placement was restored after a failure or cancel
+	// +optional
+	RolledBack bool `json:"rolledBack,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="StatefulMigration",type=string,JSONPath=`.spec.statefulMigrationName`
+// +kubebuilder:printcolumn:name="Destination",type=string,JSONPath=`.spec.destinationCluster`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// MigrationRun is the Schema for the migrationruns API
+type MigrationRun struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of MigrationRun
+	// +required
+	Spec MigrationRunSpec `json:"spec"`
+
+	// status defines the observed state of MigrationRun
+	// +optional
+	Status MigrationRunStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// MigrationRunList contains a list of MigrationRun
+type MigrationRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MigrationRun `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MigrationRun{}, &MigrationRunList{})
+}
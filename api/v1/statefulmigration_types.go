@@ -52,6 +52,15 @@ type PodRef struct {
 	// Name of the referenced pod
 	// +required
 	Name string `json:"name"`
+
+	// GenerateName of the referenced pod, used to match replacement pods
+	// whose names are not stable (e.g. pods of a Deployment's ReplicaSet)
+	// +optional
+	GenerateName string `json:"generateName,omitempty"`
+
+	// Labels of the referenced pod at backup time, used to map restored pods to checkpoints
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // BackupRef defines a reference to a backup
@@ -98,15 +107,231 @@ type Container struct {
 	Image string `json:"image"`
 }
 
+// PodResolutionStrategy defines how the pods of a workload are discovered
+// +kubebuilder:validation:Enum=SelectorPath;OwnerReference;LabelSelector
+type PodResolutionStrategy string
+
+const (
+	// PodResolutionSelectorPath reads a pod selector from a field of the workload object
+	PodResolutionSelectorPath PodResolutionStrategy = "SelectorPath"
+
+	// PodResolutionOwnerReference selects pods whose owner reference chain leads to the workload
+	PodResolutionOwnerReference PodResolutionStrategy = "OwnerReference"
+
+	// PodResolutionLabelSelector selects pods with the label selector given in the spec
+	PodResolutionLabelSelector PodResolutionStrategy = "LabelSelector"
+)
+
+// PodResolution defines how to find the pods of a workload whose kind is not natively supported
+type PodResolution struct {
+	// Strategy specifies how pods are resolved for the referenced workload
+	// +required
+	Strategy PodResolutionStrategy `json:"strategy"`
+
+	// SelectorPath is the dot-separated path of the pod selector inside the workload object,
+	// e.g. "spec.selector" or "status.selector" (default: spec.selector)
+	// The field may hold a LabelSelector, a map of labels or a selector string
+	// +optional
+	SelectorPath string `json:"selectorPath,omitempty"`
+
+	// LabelSelector selects the pods of the workload when the LabelSelector strategy is used
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// MaxOwnerDepth limits how many owner references are followed from a pod
+	// when the OwnerReference strategy is used (default: 3)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxOwnerDepth *int32 `json:"maxOwnerDepth,omitempty"`
+}
+
+// PodMappingStrategy defines how restored pods are matched to checkpoints
+// +kubebuilder:validation:Enum=Ordinal;Label;RoundRobin
+type PodMappingStrategy string
+
+const (
+	// PodMappingOrdinal matches the pod with the same ordinal as the checkpointed pod
+	PodMappingOrdinal PodMappingStrategy = "Ordinal"
+
+	// PodMappingLabel matches the pod with the same value of a stable label
+	PodMappingLabel PodMappingStrategy = "Label"
+
+	// PodMappingRoundRobin gives each new pod the next checkpoint nobody has claimed yet
+	PodMappingRoundRobin PodMappingStrategy = "RoundRobin"
+)
+
+// PodMapping defines how the pods created at the destination are matched to checkpoints
+type PodMapping struct {
+	// Strategy specifies how pods are matched to checkpoints
+	// +required
+	Strategy PodMappingStrategy `json:"strategy"`
+
+	// LabelKey is the pod label whose value identifies a replica when the Label strategy is used
+	// +optional
+	LabelKey string `json:"labelKey,omitempty"`
+}
+
+// RestoreRollbackPolicy defines what happens to the workload when a restore fails
+// +kubebuilder:validation:Enum=None;ColdStart
+type RestoreRollbackPolicy string
+
+const (
+	// RestoreRollbackNone leaves the ResourceBinding suspended for manual intervention
+	RestoreRollbackNone RestoreRollbackPolicy = "None"
+
+	// RestoreRollbackColdStart removes the CheckpointRestores and resumes dispatching,
+	// so the workload starts from its original images
+	RestoreRollbackColdStart RestoreRollbackPolicy = "ColdStart"
+)
+
+// RestoreSourceType selects which checkpoint of each pod is restored
+// +kubebuilder:validation:Enum=Latest;PointInTime;Generation
+type RestoreSourceType string
+
+const (
+	// RestoreSourceLatest restores the newest pushed checkpoint of each pod
+	RestoreSourceLatest RestoreSourceType = "Latest"
+
+	// RestoreSourcePointInTime restores the newest checkpoint of each pod taken at or before a time
+	RestoreSourcePointInTime RestoreSourceType = "PointInTime"
+
+	// RestoreSourceGeneration restores the checkpoints labeled with a checkpoint generation
+	RestoreSourceGeneration RestoreSourceType = "Generation"
+)
+
+// RestoreSource selects the checkpoints a restore is built from
+type RestoreSource struct {
+	// Type of the restore source (default: Latest)
+	// +kubebuilder:validation:Enum=Latest;PointInTime;Generation
+	// +optional
+	Type RestoreSourceType `json:"type,omitempty"`
+
+	// Time is the point in time to restore when the PointInTime type is used
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
+
+	// Generation is the value of the migration.dcnlab.com/checkpoint-generation label to restore
+	// when the Generation type is used
+	// +optional
+	Generation string `json:"generation,omitempty"`
+}
+
+// RestorePolicy defines the deadline and failure handling of restores
+type RestorePolicy struct {
+	// Timeout is how long a restore may take before it is marked failed (default: 20m)
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Rollback specifies what happens to the workload when the restore fails (default: None)
+	// +kubebuilder:validation:Enum=None;ColdStart
+	// +optional
+	Rollback RestoreRollbackPolicy `json:"rollback,omitempty"`
+
+	// AutoResume clears spec.suspension.dispatching of the ResourceBinding once every
+	// CheckpointRestore is bound to the destination clusters and the restore webhook is
+	// ready there (default: false, dispatching is resumed by hand)
+	// +optional
+	AutoResume bool `json:"autoResume,omitempty"`
+
+	// Source selects which checkpoints are restored (default: the newest pushed checkpoint of each pod).
+	// A final checkpoint taken by a MigrationRun or on eviction takes precedence.
+	// +optional
+	Source *RestoreSource `json:"source,omitempty"`
+}
+
+// VolumeTransferMethod defines how PersistentVolumeClaim data is moved to the destination cluster
+// +kubebuilder:validation:Enum=VolumeSnapshot;Copier
+type VolumeTransferMethod string
+
+const (
+	// VolumeTransferSnapshot takes a CSI VolumeSnapshot on the source cluster and imports its
+	// snapshot handle on the destination; both clusters must use the same CSI driver and backend
+	VolumeTransferSnapshot VolumeTransferMethod = "VolumeSnapshot"
+
+	// VolumeTransferCopier backs the volume up with a restic copier pod to a repository both
+	// clusters can reach and restores it into a new claim on the destination
+	VolumeTransferCopier VolumeTransferMethod = "Copier"
+)
+
+// VolumeTransferPolicy specifies how the PersistentVolumeClaims of the pods are moved with a MigrationRun
+type VolumeTransferPolicy struct {
+	// Method used to transfer the volume data
+	// +kubebuilder:validation:Enum=VolumeSnapshot;Copier
+	// +required
+	Method VolumeTransferMethod `json:"method"`
+
+	// VolumeSnapshotClassName is the VolumeSnapshotClass used on the source and destination clusters
+	// (VolumeSnapshot method)
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+
+	// StorageClassName of the claims created on the destination (default: the source claim's class)
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// RepositorySecretRef names a secret in the workload namespace of both clusters holding the
+	// restic environment: RESTIC_REPOSITORY, RESTIC_PASSWORD and the backend credentials (Copier method)
+	// +optional
+	RepositorySecretRef *SecretRef `json:"repositorySecretRef,omitempty"`
+
+	// CopierImage is the restic image of the copier pods (default: restic/restic:0.17.3)
+	// +optional
+	CopierImage string `json:"copierImage,omitempty"`
+}
+
+// FailoverPolicy specifies how the migration reacts when Karmada evicts the workload from a source cluster
+type FailoverPolicy struct {
+	// Enabled suspends dispatching of the ResourceBinding when Karmada starts a graceful eviction,
+	// takes a final checkpoint on the evicted cluster if it is still reachable and restores
+	// the workload on its new clusters from that checkpoint
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// CheckpointTimeout is how long to wait for the final checkpoint before falling back to
+	// the newest pushed checkpoints (default: 5m)
+	// +optional
+	CheckpointTimeout *metav1.Duration `json:"checkpointTimeout,omitempty"`
+}
+
+// SourceClusterDiscovery defines how the source clusters of a StatefulMigration are determined
+type SourceClusterDiscovery string
+
+const (
+	// SourceClusterDiscoveryStatic uses spec.sourceClusters as is
+	SourceClusterDiscoveryStatic SourceClusterDiscovery = "Static"
+
+	// SourceClusterDiscoveryResourceBinding derives source clusters from the workload's ResourceBinding placement
+	SourceClusterDiscoveryResourceBinding SourceClusterDiscovery = "ResourceBinding"
+)
+
 // StatefulMigrationSpec defines the desired state of StatefulMigration
+// +kubebuilder:validation:XValidation:rule="!has(self.sourceClusterDiscovery) || self.sourceClusterDiscovery != 'ResourceBinding' || !has(self.sourceClusters) || size(self.sourceClusters) == 0",message="sourceClusters must be empty when sourceClusterDiscovery is ResourceBinding"
 type StatefulMigrationSpec struct {
 	// ResourceRef specifies the workload to migrate
 	// +required
 	ResourceRef ResourceRef `json:"resourceRef"`
 
+	// PodResolution specifies how to find the pods of the referenced workload
+	// Required for kinds other than StatefulSet, Deployment and Pod; optional override for those
+	// +optional
+	PodResolution *PodResolution `json:"podResolution,omitempty"`
+
+	// PodMapping specifies how restored pods are matched to checkpoints
+	// Defaults to Ordinal for StatefulSets, exact pod name for Pods and RoundRobin otherwise
+	// +optional
+	PodMapping *PodMapping `json:"podMapping,omitempty"`
+
 	// SourceClusters specifies which clusters to back up from
-	// +required
-	SourceClusters []string `json:"sourceClusters"`
+	// Required unless SourceClusterDiscovery is ResourceBinding, and must be empty then
+	// +optional
+	SourceClusters []string `json:"sourceClusters,omitempty"`
+
+	// SourceClusterDiscovery specifies how source clusters are determined (default: Static)
+	// Static uses SourceClusters; ResourceBinding follows the clusters the workload's
+	// Karmada ResourceBinding is scheduled to, so backups move with the workload
+	// +kubebuilder:validation:Enum=Static;ResourceBinding
+	// +optional
+	SourceClusterDiscovery SourceClusterDiscovery `json:"sourceClusterDiscovery,omitempty"`
 
 	// Registry specifies the registry configuration for storing checkpoints
 	// +required
@@ -115,12 +340,137 @@ type StatefulMigrationSpec struct {
 	// Schedule specifies the backup schedule in cron format
 	// +required
 	Schedule string `json:"schedule"`
+
+	// TimeZone of the schedule, an IANA name such as "Asia/Seoul". Defaults to UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// Suspend pauses the scheduled checkpoints of every CheckpointBackup of the migration on all
+	// member clusters; the backups and their images are kept
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// CheckpointTimeout bounds one checkpoint run of each pod and is copied to its
+	// CheckpointBackups. Defaults to the checkpoint agent's --checkpoint-timeout.
+	// +optional
+	CheckpointTimeout *metav1.Duration `json:"checkpointTimeout,omitempty"`
+
+	// Priority of its CheckpointBackups in the checkpoint queue of their nodes: higher values
+	// run first
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// StartingDeadlineSeconds is how late a scheduled checkpoint may still start, copied to
+	// the CheckpointBackups of the migration
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// ConcurrencyPolicy of the CheckpointBackups of the migration (default: Forbid)
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Triggers start a checkpoint of every pod of the migration when an event happens, in
+	// addition to the schedule; copied to its CheckpointBackups
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Triggers []CheckpointTrigger `json:"triggers,omitempty"`
+
+	// RestorePolicy specifies the restore deadline and what to do when it is missed
+	// +optional
+	RestorePolicy *RestorePolicy `json:"restorePolicy,omitempty"`
+
+	// Failover specifies how Karmada cluster failover and eviction are handled
+	// +optional
+	Failover *FailoverPolicy `json:"failover,omitempty"`
+
+	// VolumeTransfer moves the data of the pods' PersistentVolumeClaims to the destination cluster
+	// before the final checkpoint of a MigrationRun
+	// +optional
+	VolumeTransfer *VolumeTransferPolicy `json:"volumeTransfer,omitempty"`
+}
+
+// ClusterBackupStatus describes the backup state of a single source cluster
+type ClusterBackupStatus struct {
+	// Name of the source cluster
+	// +required
+	Name string `json:"name"`
+
+	// Phase is Ready when every pod on the cluster has a CheckpointBackup, Failed otherwise
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Message provides additional information about the last reconcile of this cluster
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// BackupCount is the number of CheckpointBackups managed for this cluster
+	// +optional
+	BackupCount int32 `json:"backupCount,omitempty"`
+
+	// LastReconcileTime is when this cluster was last reconciled
+	// +optional
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+}
+
+// RestoreStatus describes the last restore of the workload
+type RestoreStatus struct {
+	// Phase of the restore: Working, Succeeded or Failed
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Reason explains why the restore failed
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// ResourceBinding is the name of the suspended ResourceBinding being restored
+	// +optional
+	ResourceBinding string `json:"resourceBinding,omitempty"`
+
+	// Generation is the checkpoint generation the restore uses: a final checkpoint taken on
+	// eviction or by a MigrationRun, latest for the newest pushed checkpoint of each pod, or
+	// "before <time>" for a point-in-time restore
+	// +optional
+	Generation string `json:"generation,omitempty"`
+
+	// CheckpointTime is when the oldest of the restored checkpoints was built
+	// +optional
+	CheckpointTime *metav1.Time `json:"checkpointTime,omitempty"`
+
+	// StartTime is when the restore started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the restore succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// RolledBack is true when the workload was resumed with its original images after a failure
+	// +optional
+	RolledBack bool `json:"rolledBack,omitempty"`
 }
 
 // StatefulMigrationStatus defines the observed state of StatefulMigration.
 type StatefulMigrationStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ObservedGeneration reflects the generation of the most recently observed StatefulMigration
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SourceClusters reports the backup state of each source cluster
+	// +optional
+	SourceClusters []ClusterBackupStatus `json:"sourceClusters,omitempty"`
+
+	// Restore reports the state of the last restore
+	// +optional
+	Restore *RestoreStatus `json:"restore,omitempty"`
+
+	// Conditions represent the latest available observations of the StatefulMigration's state.
+	// RestoreReady explains which pods have no complete checkpoint to restore from.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -119,12 +119,22 @@ func (in *CheckpointBackupList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CheckpointBackupSpec) DeepCopyInto(out *CheckpointBackupSpec) {
 	*out = *in
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
 	if in.StopPod != nil {
 		in, out := &in.StopPod, &out.StopPod
 		*out = new(bool)
 		**out = **in
 	}
-	out.PodRef = in.PodRef
+	in.PodRef.DeepCopyInto(&out.PodRef)
 	out.ResourceRef = in.ResourceRef
 	if in.Registry != nil {
 		in, out := &in.Registry, &out.Registry
@@ -136,6 +146,23 @@ func (in *CheckpointBackupSpec) DeepCopyInto(out *CheckpointBackupSpec) {
 		*out = make([]Container, len(*in))
 		copy(*out, *in)
 	}
+	if in.CheckpointTimeout != nil {
+		in, out := &in.CheckpointTimeout, &out.CheckpointTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]CheckpointTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointBackupSpec.
@@ -155,6 +182,14 @@ func (in *CheckpointBackupStatus) DeepCopyInto(out *CheckpointBackupStatus) {
 		in, out := &in.LastCheckpointTime, &out.LastCheckpointTime
 		*out = (*in).DeepCopy()
 	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -176,6 +211,18 @@ func (in *CheckpointBackupStatus) DeepCopyInto(out *CheckpointBackupStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(CheckpointProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]CheckpointTriggerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointBackupStatus.
@@ -207,13 +254,40 @@ func (in *CheckpointFile) DeepCopy() *CheckpointFile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckpointProgress) DeepCopyInto(out *CheckpointProgress) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointProgress.
+func (in *CheckpointProgress) DeepCopy() *CheckpointProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckpointProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CheckpointRestore) DeepCopyInto(out *CheckpointRestore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointRestore.
@@ -270,6 +344,23 @@ func (in *CheckpointRestoreList) DeepCopyObject() runtime.Object {
 func (in *CheckpointRestoreSpec) DeepCopyInto(out *CheckpointRestoreSpec) {
 	*out = *in
 	out.BackupRef = in.BackupRef
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodMapping != nil {
+		in, out := &in.PodMapping, &out.PodMapping
+		*out = new(PodMapping)
+		**out = **in
+	}
+	if in.PodOrdinal != nil {
+		in, out := &in.PodOrdinal, &out.PodOrdinal
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Containers != nil {
 		in, out := &in.Containers, &out.Containers
 		*out = make([]Container, len(*in))
@@ -290,6 +381,10 @@ func (in *CheckpointRestoreSpec) DeepCopy() *CheckpointRestoreSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CheckpointRestoreStatus) DeepCopyInto(out *CheckpointRestoreStatus) {
 	*out = *in
+	if in.ClaimTime != nil {
+		in, out := &in.ClaimTime, &out.ClaimTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointRestoreStatus.
@@ -302,6 +397,61 @@ func (in *CheckpointRestoreStatus) DeepCopy() *CheckpointRestoreStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckpointTrigger) DeepCopyInto(out *CheckpointTrigger) {
+	*out = *in
+	if in.TaintKeys != nil {
+		in, out := &in.TaintKeys, &out.TaintKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointTrigger.
+func (in *CheckpointTrigger) DeepCopy() *CheckpointTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckpointTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckpointTriggerStatus) DeepCopyInto(out *CheckpointTriggerStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointTriggerStatus.
+func (in *CheckpointTriggerStatus) DeepCopy() *CheckpointTriggerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckpointTriggerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterBackupStatus) DeepCopyInto(out *ClusterBackupStatus) {
+	*out = *in
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupStatus.
+func (in *ClusterBackupStatus) DeepCopy() *ClusterBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Container) DeepCopyInto(out *Container) {
 	*out = *in
@@ -317,9 +467,198 @@ func (in *Container) DeepCopy() *Container {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverPolicy) DeepCopyInto(out *FailoverPolicy) {
+	*out = *in
+	if in.CheckpointTimeout != nil {
+		in, out := &in.CheckpointTimeout, &out.CheckpointTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverPolicy.
+func (in *FailoverPolicy) DeepCopy() *FailoverPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationRun) DeepCopyInto(out *MigrationRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationRun.
+func (in *MigrationRun) DeepCopy() *MigrationRun {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigrationRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationRunCheckpoint) DeepCopyInto(out *MigrationRunCheckpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationRunCheckpoint.
+func (in *MigrationRunCheckpoint) DeepCopy() *MigrationRunCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationRunCheckpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationRunList) DeepCopyInto(out *MigrationRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MigrationRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationRunList.
+func (in *MigrationRunList) DeepCopy() *MigrationRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigrationRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationRunSpec) DeepCopyInto(out *MigrationRunSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationRunSpec.
+func (in *MigrationRunSpec) DeepCopy() *MigrationRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationRunStatus) DeepCopyInto(out *MigrationRunStatus) {
+	*out = *in
+	if in.SourceClusters != nil {
+		in, out := &in.SourceClusters, &out.SourceClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]MigrationRunVolume, len(*in))
+		copy(*out, *in)
+	}
+	if in.Checkpoints != nil {
+		in, out := &in.Checkpoints, &out.Checkpoints
+		*out = make([]MigrationRunCheckpoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationRunStatus.
+func (in *MigrationRunStatus) DeepCopy() *MigrationRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationRunVolume) DeepCopyInto(out *MigrationRunVolume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationRunVolume.
+func (in *MigrationRunVolume) DeepCopy() *MigrationRunVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationRunVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodMapping) DeepCopyInto(out *PodMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMapping.
+func (in *PodMapping) DeepCopy() *PodMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(PodMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodRef) DeepCopyInto(out *PodRef) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodRef.
@@ -332,6 +671,31 @@ func (in *PodRef) DeepCopy() *PodRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodResolution) DeepCopyInto(out *PodResolution) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxOwnerDepth != nil {
+		in, out := &in.MaxOwnerDepth, &out.MaxOwnerDepth
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodResolution.
+func (in *PodResolution) DeepCopy() *PodResolution {
+	if in == nil {
+		return nil
+	}
+	out := new(PodResolution)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Registry) DeepCopyInto(out *Registry) {
 	*out = *in
@@ -367,6 +731,77 @@ func (in *ResourceRef) DeepCopy() *ResourceRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestorePolicy) DeepCopyInto(out *RestorePolicy) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(RestoreSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestorePolicy.
+func (in *RestorePolicy) DeepCopy() *RestorePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RestorePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreSource) DeepCopyInto(out *RestoreSource) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreSource.
+func (in *RestoreSource) DeepCopy() *RestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreStatus) DeepCopyInto(out *RestoreStatus) {
+	*out = *in
+	if in.CheckpointTime != nil {
+		in, out := &in.CheckpointTime, &out.CheckpointTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreStatus.
+func (in *RestoreStatus) DeepCopy() *RestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretRef) DeepCopyInto(out *SecretRef) {
 	*out = *in
@@ -388,7 +823,7 @@ func (in *StatefulMigration) DeepCopyInto(out *StatefulMigration) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulMigration.
@@ -445,12 +880,64 @@ func (in *StatefulMigrationList) DeepCopyObject() runtime.Object {
 func (in *StatefulMigrationSpec) DeepCopyInto(out *StatefulMigrationSpec) {
 	*out = *in
 	out.ResourceRef = in.ResourceRef
+	if in.PodResolution != nil {
+		in, out := &in.PodResolution, &out.PodResolution
+		*out = new(PodResolution)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMapping != nil {
+		in, out := &in.PodMapping, &out.PodMapping
+		*out = new(PodMapping)
+		**out = **in
+	}
 	if in.SourceClusters != nil {
 		in, out := &in.SourceClusters, &out.SourceClusters
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	in.Registry.DeepCopyInto(&out.Registry)
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CheckpointTimeout != nil {
+		in, out := &in.CheckpointTimeout, &out.CheckpointTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]CheckpointTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RestorePolicy != nil {
+		in, out := &in.RestorePolicy, &out.RestorePolicy
+		*out = new(RestorePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(FailoverPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeTransfer != nil {
+		in, out := &in.VolumeTransfer, &out.VolumeTransfer
+		*out = new(VolumeTransferPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulMigrationSpec.
@@ -466,6 +953,25 @@ func (in *StatefulMigrationSpec) DeepCopy() *StatefulMigrationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatefulMigrationStatus) DeepCopyInto(out *StatefulMigrationStatus) {
 	*out = *in
+	if in.SourceClusters != nil {
+		in, out := &in.SourceClusters, &out.SourceClusters
+		*out = make([]ClusterBackupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(RestoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulMigrationStatus.
@@ -477,3 +983,28 @@ func (in *StatefulMigrationStatus) DeepCopy() *StatefulMigrationStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeTransferPolicy) DeepCopyInto(out *VolumeTransferPolicy) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.RepositorySecretRef != nil {
+		in, out := &in.RepositorySecretRef, &out.RepositorySecretRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeTransferPolicy.
+func (in *VolumeTransferPolicy) DeepCopy() *VolumeTransferPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeTransferPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -47,9 +47,9 @@ declare -A CONTROLLERS=(
 )
 
 declare -A CONTROLLER_FLAGS=(
-    ["checkpoint"]="--enable-checkpoint-backup-controller=true --enable-migration-backup-controller=false --enable-migration-restore-controller=false"
-    ["migration"]="--enable-checkpoint-backup-controller=false --enable-migration-backup-controller=true --enable-migration-restore-controller=false"
-    ["restore"]="--enable-checkpoint-backup-controller=false --enable-migration-backup-controller=false --enable-migration-restore-controller=true"
+    ["checkpoint"]="--enable-checkpoint-backup-controller=true --enable-migration-backup-controller=false --enable-migration-restore-controller=false --enable-migration-run-controller=false --enable-migration-failover-controller=false --enable-cluster-trigger-controller=false"
+    ["migration"]="--enable-checkpoint-backup-controller=false --enable-migration-backup-controller=true --enable-migration-restore-controller=false --enable-migration-run-controller=false --enable-migration-failover-controller=false --enable-cluster-trigger-controller=false"
+    ["restore"]="--enable-checkpoint-backup-controller=false --enable-migration-backup-controller=false --enable-migration-restore-controller=true --enable-migration-run-controller=true --enable-migration-failover-controller=true --enable-cluster-trigger-controller=true"
 )
 
 declare -A CONTROLLER_DESCRIPTIONS=(
@@ -202,6 +202,7 @@ ARG TARGETARCH
 # Install buildah and required dependencies
 RUN apk add --no-cache \\
     buildah \\
+    containerd-ctr \\
     fuse-overlayfs \\
     shadow \\
     ca-certificates \\
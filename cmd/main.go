@@ -21,6 +21,7 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -30,6 +31,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -72,6 +74,15 @@ func main() {
 	var enableCheckpointBackupController bool
 	var enableMigrationBackupController bool
 	var enableMigrationRestoreController bool
+	var enableMigrationRunController bool
+	var enableMigrationFailoverController bool
+	var enableClusterTriggerController bool
+	var garbageCollectInterval time.Duration
+	var checkpointerOpts controller.CheckpointerOptions
+	var checkpointTimeout time.Duration
+	var checkpointMaxConcurrentReconciles int
+	var checkpointPoolOpts controller.CheckpointPoolOptions
+	var checkpointBackupsByNodeLabel bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -87,6 +98,50 @@ func main() {
 		"Enable the MigrationBackup controller (runs on Karmada control plane).")
 	flag.BoolVar(&enableMigrationRestoreController, "enable-migration-restore-controller", true,
 		"Enable the MigrationRestore controller (runs on Karmada control plane).")
+	flag.BoolVar(&enableMigrationRunController, "enable-migration-run-controller", false,
+		"Enable the MigrationRun controller (runs on Karmada control plane, needs the MigrationRestore controller).")
+	flag.BoolVar(&enableMigrationFailoverController, "enable-migration-failover-controller", false,
+		"Enable the MigrationFailover controller (runs on Karmada control plane, needs the MigrationRestore controller).")
+	flag.BoolVar(&enableClusterTriggerController, "enable-cluster-trigger-controller", false,
+		"Enable the controller requesting checkpoints for the ClusterNotReady trigger (runs on Karmada control plane).")
+	flag.DurationVar(&garbageCollectInterval, "garbage-collect-interval", controller.DefaultGarbageCollectInterval,
+		"How often orphaned CheckpointBackups, CheckpointRestores and PropagationPolicies are collected "+
+			"on Karmada and member clusters (runs with the MigrationBackup controller).")
+	flag.DurationVar(&checkpointTimeout, "checkpoint-timeout", controller.DefaultCheckpointTimeout,
+		"Deadline of one checkpoint run (checkpoint, image build and push) of the CheckpointBackup controller "+
+			"when the CheckpointBackup does not set spec.checkpointTimeout.")
+	flag.IntVar(&checkpointMaxConcurrentReconciles, "checkpoint-max-concurrent-reconciles", 4,
+		"How many CheckpointBackups the CheckpointBackup controller reconciles in parallel.")
+	flag.BoolVar(&checkpointBackupsByNodeLabel, "checkpoint-backups-by-node-label", true,
+		"Only watch CheckpointBackups labelled "+controller.LabelTargetNode+" with this node (CheckpointBackup controller). "+
+			"Disable it for CheckpointBackups created without the label.")
+	flag.IntVar(&checkpointPoolOpts.MaxConcurrentCheckpoints, "max-concurrent-checkpoints", controller.DefaultMaxConcurrentCheckpoints,
+		"How many containers the CheckpointBackup controller checkpoints at once on its node (0 = no limit).")
+	flag.IntVar(&checkpointPoolOpts.MaxConcurrentBuilds, "max-concurrent-image-builds", controller.DefaultMaxConcurrentBuilds,
+		"How many checkpoint images the CheckpointBackup controller builds at once on its node (0 = no limit).")
+	flag.IntVar(&checkpointPoolOpts.MaxConcurrentPushes, "max-concurrent-image-pushes", controller.DefaultMaxConcurrentPushes,
+		"How many checkpoint images the CheckpointBackup controller pushes at once from its node (0 = no limit).")
+	flag.StringVar(&checkpointerOpts.Backend, "checkpoint-backend", controller.CheckpointBackendKubelet,
+		"How the CheckpointBackup controller checkpoints containers: kubelet, cri or containerd. "+
+			"The node label "+controller.LabelCheckpointBackend+" overrides it per node.")
+	flag.StringVar(&checkpointerOpts.RuntimeEndpoint, "cri-runtime-endpoint", controller.DefaultCRIRuntimeEndpoint,
+		"CRI socket called by the cri checkpoint backend.")
+	flag.StringVar(&checkpointerOpts.ContainerdAddress, "containerd-address", controller.DefaultContainerdAddress,
+		"containerd socket used by the containerd checkpoint backend.")
+	flag.StringVar(&checkpointerOpts.ContainerdNamespace, "containerd-namespace", controller.DefaultContainerdNamespace,
+		"containerd namespace of the Kubernetes containers.")
+	flag.StringVar(&checkpointerOpts.Kubelet.CAFile, "kubelet-ca-file", "",
+		"PEM bundle used to verify the kubelet serving certificate (CheckpointBackup controller). "+
+			"Defaults to the node's kubelet serving certificate and the cluster CA.")
+	flag.StringVar(&checkpointerOpts.Kubelet.ServingCertFile, "kubelet-serving-cert-file", controller.DefaultKubeletServingCertFile,
+		"The node's self-signed kubelet serving certificate, trusted when --kubelet-ca-file is not set.")
+	flag.StringVar(&checkpointerOpts.Kubelet.CertFile, "kubelet-client-cert-file", "",
+		"Client certificate for kubelet authentication; replaces the service account token.")
+	flag.StringVar(&checkpointerOpts.Kubelet.KeyFile, "kubelet-client-key-file", "", "Key of --kubelet-client-cert-file.")
+	flag.StringVar(&checkpointerOpts.Kubelet.TokenFile, "kubelet-token-file", "",
+		"Bearer token file for kubelet authentication, reloaded on rotation. Defaults to the service account token.")
+	flag.BoolVar(&checkpointerOpts.Kubelet.InsecureSkipVerify, "kubelet-insecure-skip-tls-verify", false,
+		"Do not verify the kubelet serving certificate. Only for testing.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
 	flag.StringVar(&webhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
 	flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
@@ -193,8 +248,15 @@ func main() {
 		})
 	}
 
+	// 노드 에이전트는 자기 노드의 Pod와 CheckpointBackup만 캐시
+	var cacheOpts cache.Options
+	if nodeName := os.Getenv("NODE_NAME"); enableCheckpointBackupController && nodeName != "" {
+		cacheOpts.ByObject = controller.NodeCacheOptions(nodeName, checkpointBackupsByNodeLabel)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  cacheOpts,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
@@ -221,8 +283,12 @@ func main() {
 	if enableCheckpointBackupController {
 		setupLog.Info("Setting up CheckpointBackup controller")
 		if err := (&controller.CheckpointBackupReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
+			Client:                  mgr.GetClient(),
+			Scheme:                  mgr.GetScheme(),
+			CheckpointerOptions:     checkpointerOpts,
+			CheckpointTimeout:       checkpointTimeout,
+			MaxConcurrentReconciles: checkpointMaxConcurrentReconciles,
+			Pool:                    checkpointPoolOpts,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CheckpointBackup")
 			os.Exit(1)
@@ -238,6 +304,15 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "MigrationBackup")
 			os.Exit(1)
 		}
+
+		setupLog.Info("Setting up migration garbage collector", "interval", garbageCollectInterval)
+		if err := mgr.Add(&controller.MigrationGarbageCollector{
+			Client:   mgr.GetClient(),
+			Interval: garbageCollectInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to add migration garbage collector")
+			os.Exit(1)
+		}
 	}
 
 	if enableMigrationRestoreController {
@@ -260,8 +335,64 @@ func main() {
 		}
 	}
 
+	if enableMigrationRunController {
+		setupLog.Info("Setting up MigrationRun controller")
+
+		karmadaClient, err := controller.NewKarmadaClient()
+		if err != nil {
+			setupLog.Error(err, "unable to create Karmada client for MigrationRun controller")
+			os.Exit(1)
+		}
+
+		if err := (&controller.MigrationRunReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			KarmadaClient: karmadaClient,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MigrationRun")
+			os.Exit(1)
+		}
+	}
+
+	if enableMigrationFailoverController {
+		setupLog.Info("Setting up MigrationFailover controller")
+
+		karmadaClient, err := controller.NewKarmadaClient()
+		if err != nil {
+			setupLog.Error(err, "unable to create Karmada client for MigrationFailover controller")
+			os.Exit(1)
+		}
+
+		if err := (&controller.MigrationFailoverReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			KarmadaClient: karmadaClient,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MigrationFailover")
+			os.Exit(1)
+		}
+	}
+
+	if enableClusterTriggerController {
+		setupLog.Info("Setting up ClusterTrigger controller")
+
+		karmadaClient, err := controller.NewKarmadaClient()
+		if err != nil {
+			setupLog.Error(err, "unable to create Karmada client for ClusterTrigger controller")
+			os.Exit(1)
+		}
+
+		if err := (&controller.ClusterTriggerReconciler{
+			KarmadaClient: karmadaClient,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterTrigger")
+			os.Exit(1)
+		}
+	}
+
 	// Ensure at least one controller is enabled
-	if !enableCheckpointBackupController && !enableMigrationBackupController && !enableMigrationRestoreController {
+	if !enableCheckpointBackupController && !enableMigrationBackupController && !enableMigrationRestoreController &&
+		!enableMigrationRunController && !enableMigrationFailoverController && !enableClusterTriggerController {
 		setupLog.Error(nil, "At least one controller must be enabled")
 		os.Exit(1)
 	}
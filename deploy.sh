@@ -368,6 +368,10 @@ deploy_migration_controller() {
         missing_crds+=("checkpointrestores.migration.dcnlab.com")
     fi
     
+    if ! execute_kubectl "$MGMT_KUBECONFIG" get crd migrationruns.migration.dcnlab.com >/dev/null 2>&1; then
+        missing_crds+=("migrationruns.migration.dcnlab.com")
+    fi
+    
     if [[ ${#missing_crds[@]} -gt 0 ]]; then
         print_warning "Missing CRDs: ${missing_crds[*]}"
         print_step "Installing CRDs..."
@@ -449,6 +453,10 @@ deploy_restore_controller() {
         missing_crds+=("checkpointrestores.migration.dcnlab.com")
     fi
     
+    if ! execute_kubectl "$MGMT_KUBECONFIG" get crd migrationruns.migration.dcnlab.com >/dev/null 2>&1; then
+        missing_crds+=("migrationruns.migration.dcnlab.com")
+    fi
+    
     if [[ ${#missing_crds[@]} -gt 0 ]]; then
         print_warning "Missing CRDs: ${missing_crds[*]}"
         print_step "Installing CRDs..."
@@ -560,6 +568,46 @@ rules:
   - update
   - patch
   - delete
+# MigrationRun, MigrationFailover and ClusterTrigger controllers run in this deployment
+- apiGroups:
+  - migration.dcnlab.com
+  resources:
+  - migrationruns
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
+- apiGroups:
+  - migration.dcnlab.com
+  resources:
+  - migrationruns/status
+  verbs:
+  - get
+  - update
+  - patch
+- apiGroups:
+  - migration.dcnlab.com
+  resources:
+  - migrationruns/finalizers
+  verbs:
+  - update
+- apiGroups:
+  - cluster.karmada.io
+  resources:
+  - clusters
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - ""
+  resources:
+  - events
+  verbs:
+  - create
+  - patch
 - apiGroups:
   - work.karmada.io
   resources:
@@ -830,6 +878,11 @@ spec:
         - --enable-checkpoint-backup-controller=true
         - --enable-migration-backup-controller=false
         - --enable-migration-restore-controller=false
+        - --enable-migration-run-controller=false
+        - --enable-migration-failover-controller=false
+        - --enable-cluster-trigger-controller=false
+        - --metrics-bind-address=:8080
+        - --metrics-secure=false
         env:
         - name: NODE_NAME
           valueFrom:
@@ -883,6 +936,9 @@ spec:
         - name: kubelet-checkpoints
           mountPath: /var/lib/kubelet/checkpoints
           readOnly: false
+        - name: kubelet-pki
+          mountPath: /var/lib/kubelet/pki
+          readOnly: true
         - name: buildah-storage
           mountPath: /var/lib/containers
           readOnly: false
@@ -903,6 +959,10 @@ spec:
         hostPath:
           path: /var/lib/kubelet/checkpoints
           type: DirectoryOrCreate
+      - name: kubelet-pki
+        hostPath:
+          path: /var/lib/kubelet/pki
+          type: DirectoryOrCreate
       - name: buildah-storage
         hostPath:
           path: /var/lib/containers
@@ -0,0 +1,48 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// LabelTargetNode on a CheckpointBackup names the node of its pod. The MigrationBackup
+// controller sets it, and the checkpoint agent of that node is the only one that sees the backup.
+const LabelTargetNode = "migration.dcnlab.com/target-node"
+
+// NodeCacheOptions restrict the checkpoint agent's cache to its node: the Node itself, pods
+// scheduled to the node, and, when byLabel is set, CheckpointBackups labelled with
+// LabelTargetNode for the node. Without byLabel every CheckpointBackup is watched, for backups
+// created without the label.
+func NodeCacheOptions(nodeName string, byLabel bool) map[client.Object]cache.ByObject {
+	byObject := map[client.Object]cache.ByObject{
+		&corev1.Pod{}:  {Field: fields.OneTermEqualSelector("spec.nodeName", nodeName)},
+		&corev1.Node{}: {Field: fields.OneTermEqualSelector("metadata.name", nodeName)},
+	}
+	if byLabel {
+		byObject[&migrationv1.CheckpointBackup{}] = cache.ByObject{
+			Label: labels.SelectorFromSet(labels.Set{LabelTargetNode: nodeName}),
+		}
+	}
+	return byObject
+}
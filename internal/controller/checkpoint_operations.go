@@ -0,0 +1,245 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// DefaultCheckpointTimeout bounds a checkpoint run when neither the CheckpointBackup nor the
+// agent configures a timeout
+const DefaultCheckpointTimeout = 10 * time.Minute
+
+// checkpointOperations tracks the checkpoint runs executing in the background, at most one
+// per CheckpointBackup, so reconciles and cron jobs return immediately
+type checkpointOperations struct {
+	mu  sync.Mutex
+	ops map[string]*checkpointOperation
+}
+
+// checkpointOperation is one checkpoint run in the background
+type checkpointOperation struct {
+	uid    types.UID
+	cancel context.CancelCauseFunc
+}
+
+// start runs fn in a goroutine with the timeout unless a run for key is already in progress.
+// A run left over from a deleted backup of the same name is cancelled first. The timeout is a
+// runBudget: time queued for the worker pool does not count. context.Cause of the run's context
+// is context.DeadlineExceeded once the budget is spent and context.Canceled when it is cancelled.
+func (o *checkpointOperations) start(key string, uid types.UID, timeout time.Duration, fn func(ctx context.Context)) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.ops == nil {
+		o.ops = map[string]*checkpointOperation{}
+	}
+	if op, running := o.ops[key]; running {
+		if op.uid == uid {
+			return false
+		}
+		op.cancel(context.Canceled)
+	}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	budget := newRunBudget(timeout, func() { cancel(context.DeadlineExceeded) })
+	ctx = context.WithValue(ctx, runBudgetKey{}, budget)
+	op := &checkpointOperation{uid: uid, cancel: cancel}
+	o.ops[key] = op
+	go func() {
+		defer func() {
+			budget.stop()
+			cancel(context.Canceled)
+			o.mu.Lock()
+			if o.ops[key] == op {
+				delete(o.ops, key)
+			}
+			o.mu.Unlock()
+		}()
+		fn(ctx)
+	}()
+	return true
+}
+
+// cancel stops the run for key; it reports whether one was in progress
+func (o *checkpointOperations) cancel(key string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	op, running := o.ops[key]
+	if running {
+		op.cancel(context.Canceled)
+		delete(o.ops, key)
+	}
+	return running
+}
+
+// running reports whether a run for key is in progress
+func (o *checkpointOperations) running(key string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, running := o.ops[key]
+	return running
+}
+
+// runBudget is the working time left to a checkpoint run. Its clock stops while the run waits
+// for a slot of the node's worker pool, so a busy node delays runs instead of failing them.
+type runBudget struct {
+	mu   sync.Mutex
+	left time.Duration
+	// 시계가 다시 돌기 시작한 시각, 멈춰 있으면 zero
+	resumed time.Time
+	timer   *time.Timer
+	expire  func()
+}
+
+type runBudgetKey struct{}
+
+// newRunBudget starts the clock of a budget of timeout; expire is called when it is spent
+func newRunBudget(timeout time.Duration, expire func()) *runBudget {
+	b := &runBudget{left: timeout, expire: expire}
+	b.resume()
+	return b
+}
+
+// runBudgetFrom returns the budget of the run ctx belongs to, nil outside a run
+func runBudgetFrom(ctx context.Context) *runBudget {
+	b, _ := ctx.Value(runBudgetKey{}).(*runBudget)
+	return b
+}
+
+// pause stops the clock
+func (b *runBudget) pause() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.resumed.IsZero() {
+		return
+	}
+	b.timer.Stop()
+	b.left -= time.Since(b.resumed)
+	b.resumed = time.Time{}
+}
+
+// resume restarts the clock
+func (b *runBudget) resume() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.resumed.IsZero() {
+		return
+	}
+	b.resumed = time.Now()
+	b.timer = time.AfterFunc(max(b.left, 0), b.expire)
+}
+
+// deadline returns when the budget is spent if the clock keeps running from now
+func (b *runBudget) deadline() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.resumed.IsZero() {
+		return time.Now().Add(b.left)
+	}
+	return b.resumed.Add(b.left)
+}
+
+// stop stops the clock for good once the run ended
+func (b *runBudget) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+}
+
+// startCheckpoint runs performCheckpoint for the backup in the background with its timeout.
+// It returns false when a run of the backup is already in progress.
+func (r *CheckpointBackupReconciler) startCheckpoint(backup *migrationv1.CheckpointBackup, trigger string) bool {
+	key := types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}.String()
+	timeout := r.checkpointTimeout(backup)
+	snapshot := backup.DeepCopy()
+	return r.operations.start(key, backup.UID, timeout, func(ctx context.Context) {
+		log := logf.Log.WithName("checkpointbackup").WithValues("backup", key, "trigger", trigger)
+		ctx = logf.IntoContext(ctx, log)
+		err := r.performCheckpoint(ctx, snapshot)
+		recordCheckpointRun(ctx, err, snapshot.Status.Phase)
+		if errors.Is(context.Cause(ctx), context.Canceled) {
+			log.Info("Checkpoint cancelled")
+			return
+		}
+		// 원래 ctx는 만료되었을 수 있으므로 상태 기록은 새 context로
+		if errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+			msg := fmt.Sprintf("Checkpoint did not complete within %s: %v", timeout, err)
+			if updateErr := r.updatePhase(context.Background(), snapshot, PhaseFailed, msg); updateErr != nil {
+				log.Error(updateErr, "Failed to update phase to Failed")
+			}
+		}
+		if err := r.updateProgress(context.Background(), snapshot, func(p *migrationv1.CheckpointProgress) {
+			now := metav1.Now()
+			p.CompletionTime = &now
+			p.CurrentContainer = ""
+		}); err != nil {
+			log.Error(err, "Failed to record checkpoint completion")
+		}
+		if err != nil {
+			log.Error(err, "Failed to perform checkpoint")
+		}
+	})
+}
+
+// updateProgress applies mutate to status.progress of the backup
+func (r *CheckpointBackupReconciler) updateProgress(ctx context.Context, backup *migrationv1.CheckpointBackup, mutate func(*migrationv1.CheckpointProgress)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest migrationv1.CheckpointBackup
+		if err := r.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}, &latest); err != nil {
+			return err
+		}
+		if latest.Status.Progress == nil {
+			latest.Status.Progress = &migrationv1.CheckpointProgress{}
+		}
+		mutate(latest.Status.Progress)
+		if err := r.Status().Update(ctx, &latest); err != nil {
+			return err
+		}
+		backup.Status = latest.Status
+		return nil
+	})
+}
+
+// cancelCheckpoint stops the run of the backup, e.g. when it is deleted
+func (r *CheckpointBackupReconciler) cancelCheckpoint(key string) {
+	if r.operations.cancel(key) {
+		logf.Log.WithName("checkpointbackup").Info("Cancelled checkpoint in progress", "backup", key)
+	}
+}
+
+// checkpointTimeout returns the timeout of one checkpoint run of the backup
+func (r *CheckpointBackupReconciler) checkpointTimeout(backup *migrationv1.CheckpointBackup) time.Duration {
+	if t := backup.Spec.CheckpointTimeout; t != nil && t.Duration > 0 {
+		return t.Duration
+	}
+	if r.CheckpointTimeout > 0 {
+		return r.CheckpointTimeout
+	}
+	return DefaultCheckpointTimeout
+}
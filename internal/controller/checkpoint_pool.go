@@ -0,0 +1,237 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+const (
+	// Steps of a checkpoint run limited by the worker pool, reported in status.progress.queued
+	StepCheckpoint = "Checkpoint"
+	StepBuild      = "Build"
+	StepPush       = "Push"
+
+	DefaultMaxConcurrentCheckpoints = 2
+	DefaultMaxConcurrentBuilds      = 2
+	DefaultMaxConcurrentPushes      = 4
+)
+
+// CheckpointPoolOptions limit how many containers the agent checkpoints, builds images for and
+// pushes at the same time on its node. Zero or less means no limit.
+type CheckpointPoolOptions struct {
+	MaxConcurrentCheckpoints int
+	MaxConcurrentBuilds      int
+	MaxConcurrentPushes      int
+}
+
+// checkpointPool is the node's worker pool: one bounded queue per step, so a burst of backups
+// sharing a schedule does not freeze every pod or saturate the disk and network at once
+type checkpointPool struct {
+	checkpoints *stepQueue
+	builds      *stepQueue
+	pushes      *stepQueue
+}
+
+func newCheckpointPool(opts CheckpointPoolOptions) *checkpointPool {
+	return &checkpointPool{
+		checkpoints: newStepQueue(opts.MaxConcurrentCheckpoints),
+		builds:      newStepQueue(opts.MaxConcurrentBuilds),
+		pushes:      newStepQueue(opts.MaxConcurrentPushes),
+	}
+}
+
+// queue returns the queue of step
+func (p *checkpointPool) queue(step string) *stepQueue {
+	switch step {
+	case StepCheckpoint:
+		return p.checkpoints
+	case StepBuild:
+		return p.builds
+	}
+	return p.pushes
+}
+
+// stepQueue hands out at most limit slots. Waiters are served by priority; among equal
+// priorities the namespace served least recently goes first, then the oldest waiter.
+type stepQueue struct {
+	mu      sync.Mutex
+	limit   int
+	active  int
+	seq     uint64
+	waiters []*stepWaiter
+	// 네임스페이스별 마지막으로 슬롯을 받은 순번 (공정 큐잉)
+	lastServed map[string]uint64
+}
+
+type stepWaiter struct {
+	namespace string
+	priority  int32
+	seq       uint64
+	granted   bool
+	ready     chan struct{}
+}
+
+func newStepQueue(limit int) *stepQueue {
+	return &stepQueue{limit: limit, lastServed: map[string]uint64{}}
+}
+
+// acquire blocks until a slot is free or ctx is done and returns the function releasing the
+// slot. queued is called once when the caller has to wait.
+func (q *stepQueue) acquire(ctx context.Context, namespace string, priority int32, queued func()) (func(), error) {
+	q.mu.Lock()
+	if q.limit <= 0 || (q.active < q.limit && len(q.waiters) == 0) {
+		q.grantLocked(namespace)
+		q.mu.Unlock()
+		return q.release, nil
+	}
+	q.seq++
+	w := &stepWaiter{namespace: namespace, priority: priority, seq: q.seq, ready: make(chan struct{})}
+	q.waiters = append(q.waiters, w)
+	q.mu.Unlock()
+
+	if queued != nil {
+		queued()
+	}
+
+	select {
+	case <-w.ready:
+		return q.release, nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if w.granted {
+			// 취소와 동시에 슬롯을 받았으면 다음 대기자에게 넘김
+			q.active--
+			q.dispatchLocked()
+		} else {
+			q.removeLocked(w)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// release returns a slot and hands it to the next waiter
+func (q *stepQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.limit <= 0 {
+		return
+	}
+	q.active--
+	q.dispatchLocked()
+}
+
+func (q *stepQueue) grantLocked(namespace string) {
+	q.active++
+	q.seq++
+	q.lastServed[namespace] = q.seq
+}
+
+func (q *stepQueue) dispatchLocked() {
+	for q.active < q.limit && len(q.waiters) > 0 {
+		next := q.waiters[0]
+		for _, w := range q.waiters[1:] {
+			if q.before(w, next) {
+				next = w
+			}
+		}
+		q.removeLocked(next)
+		q.grantLocked(next.namespace)
+		next.granted = true
+		close(next.ready)
+	}
+}
+
+// before reports whether a is served before b
+func (q *stepQueue) before(a, b *stepWaiter) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	if la, lb := q.lastServed[a.namespace], q.lastServed[b.namespace]; la != lb {
+		return la < lb
+	}
+	return a.seq < b.seq
+}
+
+func (q *stepQueue) removeLocked(w *stepWaiter) {
+	for i, x := range q.waiters {
+		if x == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// acquireStep waits for a slot of the node's worker pool for one step of the backup's run and
+// reports the wait in status.progress.queued. The run's timeout does not run while it waits.
+// The step runs with the returned context, whose deadline is the time left to the run, so the
+// kubelet and CRI timeouts are not shortened by the wait; release frees the slot.
+func (r *CheckpointBackupReconciler) acquireStep(ctx context.Context, backup *migrationv1.CheckpointBackup, step string) (context.Context, func(), error) {
+	budget := runBudgetFrom(ctx)
+	if budget != nil {
+		budget.pause()
+	}
+	release := func() {}
+	if r.pool != nil {
+		log := logf.FromContext(ctx)
+		waited := false
+		var err error
+		release, err = r.pool.queue(step).acquire(ctx, backup.Namespace, backup.Spec.Priority, func() {
+			waited = true
+			log.Info("Waiting for a free slot of the node's worker pool", "step", step, "priority", backup.Spec.Priority)
+			if err := r.updateProgress(ctx, backup, func(p *migrationv1.CheckpointProgress) { p.Queued = step }); err != nil {
+				log.Error(err, "Failed to record checkpoint progress")
+			}
+		})
+		if err != nil {
+			if budget != nil {
+				budget.resume()
+			}
+			return nil, nil, err
+		}
+		if waited {
+			if err := r.updateProgress(ctx, backup, func(p *migrationv1.CheckpointProgress) {
+				p.Queued = ""
+				if budget != nil {
+					p.Deadline = &metav1.Time{Time: budget.deadline()}
+				}
+			}); err != nil {
+				log.Error(err, "Failed to record checkpoint progress")
+			}
+		}
+	}
+	if budget == nil {
+		return ctx, release, nil
+	}
+	budget.resume()
+	stepCtx, cancel := context.WithDeadline(ctx, budget.deadline())
+	return stepCtx, func() {
+		// 스텝이 마감에 걸렸으면 타이머보다 먼저 run을 만료 처리 (Timeout으로 기록되도록)
+		if stepCtx.Err() == context.DeadlineExceeded {
+			budget.expire()
+		}
+		cancel()
+		release()
+	}, nil
+}
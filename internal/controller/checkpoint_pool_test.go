@@ -0,0 +1,226 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type queueWaiter struct {
+	name      string
+	namespace string
+	priority  int32
+}
+
+// serveOrder holds the only slot of a queue for holder, queues waiters one by one and returns
+// the order in which they are granted the slot once it is released
+func serveOrder(t *testing.T, holder string, waiters []queueWaiter) []string {
+	t.Helper()
+	q := newStepQueue(1)
+	release, err := q.acquire(context.Background(), holder, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	for _, w := range waiters {
+		queued := make(chan struct{})
+		wg.Add(1)
+		go func(w queueWaiter) {
+			defer wg.Done()
+			rel, err := q.acquire(context.Background(), w.namespace, w.priority, func() { close(queued) })
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			order = append(order, w.name)
+			mu.Unlock()
+			rel()
+		}(w)
+		// 다음 대기자는 앞선 대기자가 큐에 들어간 뒤에 추가 (순번 고정)
+		select {
+		case <-queued:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s was not queued", w.name)
+		}
+	}
+	release()
+	wg.Wait()
+	return order
+}
+
+func TestStepQueueOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		holder  string
+		waiters []queueWaiter
+		want    []string
+	}{
+		{
+			name:   "higher priority first",
+			holder: "a",
+			waiters: []queueWaiter{
+				{name: "low", namespace: "a", priority: 0},
+				{name: "high", namespace: "a", priority: 10},
+				{name: "mid", namespace: "b", priority: 5},
+			},
+			want: []string{"high", "mid", "low"},
+		},
+		{
+			name:   "oldest waiter first within a namespace",
+			holder: "a",
+			waiters: []queueWaiter{
+				{name: "first", namespace: "a"},
+				{name: "second", namespace: "a"},
+				{name: "third", namespace: "a"},
+			},
+			want: []string{"first", "second", "third"},
+		},
+		{
+			name:   "namespaces take turns",
+			holder: "a",
+			waiters: []queueWaiter{
+				{name: "a1", namespace: "a"},
+				{name: "a2", namespace: "a"},
+				{name: "a3", namespace: "a"},
+				{name: "b1", namespace: "b"},
+				{name: "b2", namespace: "b"},
+			},
+			want: []string{"b1", "a1", "b2", "a2", "a3"},
+		},
+		{
+			name:   "priority wins over fairness",
+			holder: "b",
+			waiters: []queueWaiter{
+				{name: "a1", namespace: "a"},
+				{name: "b1", namespace: "b", priority: 1},
+				{name: "b2", namespace: "b", priority: 1},
+			},
+			want: []string{"b1", "b2", "a1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serveOrder(t, tt.holder, tt.waiters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("served %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStepQueueLimit(t *testing.T) {
+	q := newStepQueue(2)
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		rel, err := q.acquire(context.Background(), "a", 0, func() { t.Errorf("slot %d should be free", i) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases = append(releases, rel)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.acquire(ctx, "a", 0, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("third acquire returned %v, want a deadline error", err)
+	}
+	releases[0]()
+	rel, err := q.acquire(context.Background(), "a", 0, func() { t.Error("released slot should be free") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel()
+	releases[1]()
+}
+
+func TestStepQueueUnlimited(t *testing.T) {
+	q := newStepQueue(0)
+	for i := 0; i < 10; i++ {
+		if _, err := q.acquire(context.Background(), "a", 0, func() { t.Fatal("unlimited queue should not block") }); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStepQueueCancelledWaiter(t *testing.T) {
+	q := newStepQueue(1)
+	release, err := q.acquire(context.Background(), "a", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 취소된 대기자는 우선순위가 높아도 슬롯을 받지 않음
+	ctx, cancel := context.WithCancel(context.Background())
+	queued := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := q.acquire(ctx, "b", 10, func() { close(queued) })
+		done <- err
+	}()
+	<-queued
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled acquire returned %v", err)
+	}
+
+	granted := make(chan func())
+	go func() {
+		rel, err := q.acquire(context.Background(), "c", 0, nil)
+		if err != nil {
+			t.Error(err)
+		}
+		granted <- rel
+	}()
+	release()
+	select {
+	case rel := <-granted:
+		rel()
+	case <-time.After(5 * time.Second):
+		t.Fatal("slot of the cancelled waiter was not handed on")
+	}
+}
+
+func TestRunBudgetPause(t *testing.T) {
+	expired := make(chan struct{})
+	budget := newRunBudget(100*time.Millisecond, func() { close(expired) })
+	defer budget.stop()
+
+	budget.pause()
+	time.Sleep(200 * time.Millisecond)
+	select {
+	case <-expired:
+		t.Fatal("budget expired while paused")
+	default:
+	}
+	if left := time.Until(budget.deadline()); left <= 0 || left > 100*time.Millisecond {
+		t.Errorf("time left after a pause = %s, want up to 100ms", left)
+	}
+
+	budget.resume()
+	select {
+	case <-expired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("budget did not expire after it was resumed")
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+	// 에이전트 이미지에 tzdata가 없어도 spec.timeZone을 해석할 수 있도록 내장
+	_ "time/tzdata"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// maxMissedSchedules stops counting missed runs of a schedule that fires far more often than
+// the agent was down for, like the CronJob controller does
+const maxMissedSchedules = 100
+
+// reconcileSchedule starts the scheduled run that is due, if any, and requeues the backup for the
+// next one. The schedule is kept in status.lastScheduleTime and status.nextScheduleTime, so runs
+// missed while the agent was down are caught up after a restart within startingDeadlineSeconds.
+func (r *CheckpointBackupReconciler) reconcileSchedule(ctx context.Context, backup *migrationv1.CheckpointBackup) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	sched, err := cron.ParseStandard(backup.Spec.Schedule)
+	if err != nil {
+		if updateErr := r.updatePhase(ctx, backup, PhaseFailed, fmt.Sprintf("Invalid schedule %q: %v", backup.Spec.Schedule, err)); updateErr != nil {
+			log.Error(updateErr, "Failed to update phase to Failed")
+		}
+		// 스케줄이 수정되면 다시 reconcile됨
+		return ctrl.Result{}, nil
+	}
+	loc, err := scheduleLocation(backup.Spec.TimeZone)
+	if err != nil {
+		if updateErr := r.updatePhase(ctx, backup, PhaseFailed, err.Error()); updateErr != nil {
+			log.Error(updateErr, "Failed to update phase to Failed")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Suspended: no new runs; lastScheduleTime is kept so missed runs are caught up on resume
+	if backup.Spec.Suspend != nil && *backup.Spec.Suspend {
+		log.Info("Schedule suspended", "backup", backup.Name)
+		return ctrl.Result{}, r.clearNextSchedule(ctx, backup)
+	}
+
+	// Events declared in spec.triggers start a run outside of the schedule
+	triggered, err := r.reconcileTriggers(ctx, backup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 스케줄은 spec.timeZone 기준으로 계산
+	now := time.Now().In(loc)
+	backupKey := types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}.String()
+
+	// The first reconcile runs a checkpoint right away, as the schedule did before any run
+	due, missed := time.Time{}, 0
+	trigger := "schedule"
+	if backup.Status.LastScheduleTime == nil && backup.Status.LastCheckpointTime == nil {
+		due, trigger = now, "initial"
+	} else {
+		due, missed = mostRecentSchedule(sched, r.earliestSchedule(backup, now), now)
+	}
+	next := sched.Next(now)
+
+	if !due.IsZero() && triggered {
+		// 트리거로 시작한 실행이 이번 스케줄 실행을 대신함
+		if err := r.updateSchedule(ctx, backup, &due, next); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+	if !due.IsZero() {
+		if missed > 1 {
+			log.Info("Missed scheduled checkpoints, catching up the most recent one", "missed", missed, "scheduleTime", due)
+		}
+		start := true
+		if r.operations.running(backupKey) {
+			switch backup.Spec.ConcurrencyPolicy {
+			case migrationv1.ReplaceConcurrent:
+				log.Info("Replacing checkpoint in progress with the scheduled run", "scheduleTime", due)
+				r.cancelCheckpoint(backupKey)
+			default:
+				// 진행 중인 실행이 끝나면 상태 업데이트로 다시 reconcile됨
+				log.Info("Previous checkpoint still in progress, delaying scheduled run", "scheduleTime", due)
+				start = false
+			}
+		}
+		if start {
+			// 재시작 후 같은 실행이 반복되지 않도록 시작 전에 기록
+			if err := r.updateSchedule(ctx, backup, &due, next); err != nil {
+				return ctrl.Result{}, err
+			}
+			if r.startCheckpoint(backup, trigger) {
+				log.Info("Scheduled checkpoint started", "scheduleTime", due, "timeout", r.checkpointTimeout(backup))
+			}
+			return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+		}
+	}
+
+	if err := r.updateSchedule(ctx, backup, nil, next); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+}
+
+// earliestSchedule returns the time after which missed runs of the backup are considered: its
+// last scheduled run or creation, but no earlier than startingDeadlineSeconds ago. Backups
+// checkpointed by an agent that did not record lastScheduleTime yet count from their last
+// checkpoint, so an upgrade does not catch up runs they already had.
+func (r *CheckpointBackupReconciler) earliestSchedule(backup *migrationv1.CheckpointBackup, now time.Time) time.Time {
+	earliest := backup.CreationTimestamp.Time
+	switch {
+	case backup.Status.LastScheduleTime != nil:
+		earliest = backup.Status.LastScheduleTime.Time
+	case backup.Status.LastCheckpointTime != nil:
+		earliest = backup.Status.LastCheckpointTime.Time
+	}
+	if d := backup.Spec.StartingDeadlineSeconds; d != nil {
+		if deadline := now.Add(-time.Duration(*d) * time.Second); deadline.After(earliest) {
+			earliest = deadline
+		}
+	}
+	return earliest.In(now.Location())
+}
+
+// scheduleLocation returns the time zone of a schedule, UTC when timeZone is unset
+func scheduleLocation(timeZone *string) (*time.Location, error) {
+	if timeZone == nil || *timeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(*timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", *timeZone, err)
+	}
+	return loc, nil
+}
+
+// mostRecentSchedule returns the latest time the schedule fired after earliest and up to now,
+// and how many times it fired, counting up to maxMissedSchedules
+func mostRecentSchedule(sched cron.Schedule, earliest, now time.Time) (time.Time, int) {
+	var mostRecent time.Time
+	missed := 0
+	// 불가능한 스케줄(예: 2월 30일)은 Next가 zero time을 반환
+	for t := sched.Next(earliest); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+		mostRecent = t
+		missed++
+		if missed >= maxMissedSchedules {
+			// 나머지는 세지 않고 now 이전의 마지막 실행 시각만 찾음
+			return latestScheduleBefore(sched, mostRecent, now), missed
+		}
+	}
+	return mostRecent, missed
+}
+
+// latestScheduleBefore returns the last time the schedule fired after `after` and up to now, or
+// after itself when it did not fire since. It looks back from now over windows twice as large
+// each time, so only the fire times of the last window are walked.
+func latestScheduleBefore(sched cron.Schedule, after, now time.Time) time.Time {
+	for window := time.Minute; ; window *= 2 {
+		from := now.Add(-window)
+		if !from.After(after) {
+			from = after
+		}
+		var last time.Time
+		for t := sched.Next(from); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+			last = t
+		}
+		if !last.IsZero() {
+			return last
+		}
+		if from.Equal(after) {
+			return after
+		}
+	}
+}
+
+// updateSchedule records the scheduled time of the run being started, if any, and the next one
+func (r *CheckpointBackupReconciler) updateSchedule(ctx context.Context, backup *migrationv1.CheckpointBackup, last *time.Time, next time.Time) error {
+	nextTime := metav1.NewTime(next)
+	if last == nil && backup.Status.NextScheduleTime.Equal(&nextTime) {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest migrationv1.CheckpointBackup
+		if err := r.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}, &latest); err != nil {
+			return err
+		}
+		if last != nil {
+			lastTime := metav1.NewTime(*last)
+			latest.Status.LastScheduleTime = &lastTime
+		}
+		latest.Status.NextScheduleTime = &nextTime
+		if err := r.Status().Update(ctx, &latest); err != nil {
+			return err
+		}
+		backup.Status = latest.Status
+		return nil
+	})
+}
+
+// clearNextSchedule removes status.nextScheduleTime of a suspended backup
+func (r *CheckpointBackupReconciler) clearNextSchedule(ctx context.Context, backup *migrationv1.CheckpointBackup) error {
+	if backup.Status.NextScheduleTime == nil {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest migrationv1.CheckpointBackup
+		if err := r.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}, &latest); err != nil {
+			return err
+		}
+		latest.Status.NextScheduleTime = nil
+		if err := r.Status().Update(ctx, &latest); err != nil {
+			return err
+		}
+		backup.Status = latest.Status
+		return nil
+	})
+}
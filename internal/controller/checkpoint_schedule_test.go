@@ -0,0 +1,153 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+func TestMostRecentSchedule(t *testing.T) {
+	// 2025-06-01 is a Sunday
+	now := time.Date(2025, 6, 1, 12, 17, 30, 0, time.UTC)
+	tests := []struct {
+		name       string
+		schedule   string
+		earliest   time.Time
+		want       time.Time
+		wantMissed int
+	}{
+		{
+			name:     "not due yet",
+			schedule: "*/5 * * * *",
+			earliest: time.Date(2025, 6, 1, 12, 15, 0, 0, time.UTC),
+		},
+		{
+			name:       "one run due",
+			schedule:   "*/5 * * * *",
+			earliest:   time.Date(2025, 6, 1, 12, 10, 0, 0, time.UTC),
+			want:       time.Date(2025, 6, 1, 12, 15, 0, 0, time.UTC),
+			wantMissed: 1,
+		},
+		{
+			name:       "several missed runs",
+			schedule:   "*/5 * * * *",
+			earliest:   time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+			want:       time.Date(2025, 6, 1, 12, 15, 0, 0, time.UTC),
+			wantMissed: 3,
+		},
+		{
+			name:       "capped count still returns the latest run",
+			schedule:   "* * * * *",
+			earliest:   now.Add(-1000 * time.Minute),
+			want:       time.Date(2025, 6, 1, 12, 17, 0, 0, time.UTC),
+			wantMissed: maxMissedSchedules,
+		},
+		{
+			name:       "capped count on an irregular schedule",
+			schedule:   "0 9 * * 1-5",
+			earliest:   now.AddDate(-3, 0, 0),
+			want:       time.Date(2025, 5, 30, 9, 0, 0, 0, time.UTC),
+			wantMissed: maxMissedSchedules,
+		},
+		{
+			name:     "schedule that never fires",
+			schedule: "0 0 30 2 *",
+			earliest: now.AddDate(-1, 0, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := cron.ParseStandard(tt.schedule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, missed := mostRecentSchedule(sched, tt.earliest, now)
+			if !got.Equal(tt.want) {
+				t.Errorf("most recent = %v, want %v", got, tt.want)
+			}
+			if missed != tt.wantMissed {
+				t.Errorf("missed = %d, want %d", missed, tt.wantMissed)
+			}
+		})
+	}
+}
+
+func TestEarliestSchedule(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	created := metav1.NewTime(now.Add(-24 * time.Hour))
+	lastSchedule := metav1.NewTime(now.Add(-2 * time.Hour))
+	lastCheckpoint := metav1.NewTime(now.Add(-3 * time.Hour))
+	tests := []struct {
+		name     string
+		status   migrationv1.CheckpointBackupStatus
+		deadline *int64
+		want     time.Time
+	}{
+		{
+			name: "never scheduled counts from creation",
+			want: created.Time,
+		},
+		{
+			name:   "last scheduled run",
+			status: migrationv1.CheckpointBackupStatus{LastScheduleTime: &lastSchedule, LastCheckpointTime: &lastCheckpoint},
+			want:   lastSchedule.Time,
+		},
+		{
+			name:   "checkpointed before lastScheduleTime was recorded",
+			status: migrationv1.CheckpointBackupStatus{LastCheckpointTime: &lastCheckpoint},
+			want:   lastCheckpoint.Time,
+		},
+		{
+			name:     "starting deadline after the last run",
+			status:   migrationv1.CheckpointBackupStatus{LastScheduleTime: &lastSchedule},
+			deadline: ptr.To[int64](600),
+			want:     now.Add(-10 * time.Minute),
+		},
+		{
+			name:     "starting deadline before the last run",
+			status:   migrationv1.CheckpointBackupStatus{LastScheduleTime: &lastSchedule},
+			deadline: ptr.To[int64](4 * 3600),
+			want:     lastSchedule.Time,
+		},
+		{
+			name:     "starting deadline on an upgraded backup",
+			status:   migrationv1.CheckpointBackupStatus{LastCheckpointTime: &lastCheckpoint},
+			deadline: ptr.To[int64](3600),
+			want:     now.Add(-time.Hour),
+		},
+	}
+	r := &CheckpointBackupReconciler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backup := &migrationv1.CheckpointBackup{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+				Spec:       migrationv1.CheckpointBackupSpec{StartingDeadlineSeconds: tt.deadline},
+				Status:     tt.status,
+			}
+			if got := r.earliestSchedule(backup, now); !got.Equal(tt.want) {
+				t.Errorf("earliest = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
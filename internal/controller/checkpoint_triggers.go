@@ -0,0 +1,364 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// AnnoCheckpointRequest requests a checkpoint; every new value starts one. It is read from the
+// pod for the PodAnnotation trigger and from the CheckpointBackup for the ClusterNotReady
+// trigger, where the ClusterTrigger controller sets it.
+const AnnoCheckpointRequest = "migration.dcnlab.com/checkpoint-request"
+
+// ClusterNotReadyTriggerMaxDelay is how long after a cluster became NotReady its checkpoint
+// request may still start a checkpoint on the member
+const ClusterNotReadyTriggerMaxDelay = 5 * time.Minute
+
+// clusterNotReadyRequestPrefix starts the checkpoint request of the ClusterNotReady trigger,
+// followed by the time the cluster became NotReady
+const clusterNotReadyRequestPrefix = "NotReady@"
+
+// AnnoEvictionCheckpoint is set on a pod by the eviction webhook to the time its eviction was
+// first requested; every scheduled CheckpointBackup of the pod takes a checkpoint for it
+const AnnoEvictionCheckpoint = "migration.dcnlab.com/eviction-checkpoint"
+
+// reconcileTriggers starts a checkpoint when a trigger of the backup has a new event and records
+// the event in status.triggers. It reports whether a run was started.
+func (r *CheckpointBackupReconciler) reconcileTriggers(ctx context.Context, backup *migrationv1.CheckpointBackup) (bool, error) {
+	logger := logf.FromContext(ctx)
+
+	events, err := r.triggerEvents(ctx, backup)
+	if err != nil {
+		return false, err
+	}
+
+	// 이벤트가 사라진 트리거는 기록을 지워 같은 이벤트가 다시 오면 다시 실행
+	recorded := map[migrationv1.CheckpointTriggerType]migrationv1.CheckpointTriggerStatus{}
+	for _, t := range backup.Status.Triggers {
+		recorded[t.Type] = t
+	}
+	now := metav1.Now()
+	var statuses []migrationv1.CheckpointTriggerStatus
+	var fired []string
+	for _, t := range triggerTypes(backup) {
+		ev := events[t]
+		if ev == "" {
+			continue
+		}
+		if prev, ok := recorded[t]; ok && prev.Event == ev {
+			statuses = append(statuses, prev)
+			continue
+		}
+		statuses = append(statuses, migrationv1.CheckpointTriggerStatus{Type: t, Event: ev, Time: now})
+		fired = append(fired, fmt.Sprintf("%s (%s)", t, ev))
+	}
+	if len(fired) == 0 && len(statuses) == len(backup.Status.Triggers) {
+		return false, nil
+	}
+
+	backupKey := types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}.String()
+	if len(fired) > 0 && r.operations.running(backupKey) {
+		if backup.Spec.ConcurrencyPolicy != migrationv1.ReplaceConcurrent {
+			// 진행 중인 실행이 끝나면 상태 업데이트로 다시 reconcile됨
+			logger.Info("Previous checkpoint still in progress, delaying triggered run", "events", fired)
+			return false, nil
+		}
+		logger.Info("Replacing checkpoint in progress with the triggered run", "events", fired)
+		r.cancelCheckpoint(backupKey)
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest migrationv1.CheckpointBackup
+		if err := r.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}, &latest); err != nil {
+			return err
+		}
+		latest.Status.Triggers = statuses
+		if err := r.Status().Update(ctx, &latest); err != nil {
+			return err
+		}
+		backup.Status = latest.Status
+		return nil
+	}); err != nil {
+		return false, fmt.Errorf("failed to record checkpoint triggers: %w", err)
+	}
+
+	if len(fired) == 0 {
+		return false, nil
+	}
+	logger.Info("Checkpoint triggered", "events", fired)
+	r.startCheckpoint(backup, "trigger")
+	return true, nil
+}
+
+// triggerTypes returns the triggers of the backup: the declared ones and the implicit Eviction
+func triggerTypes(backup *migrationv1.CheckpointBackup) []migrationv1.CheckpointTriggerType {
+	out := []migrationv1.CheckpointTriggerType{migrationv1.TriggerEviction}
+	for _, t := range backup.Spec.Triggers {
+		if t.Type != migrationv1.TriggerEviction {
+			out = append(out, t.Type)
+		}
+	}
+	return out
+}
+
+// triggerEvents returns the current event of each trigger of the backup; a trigger without an
+// event is absent
+func (r *CheckpointBackupReconciler) triggerEvents(ctx context.Context, backup *migrationv1.CheckpointBackup) (map[migrationv1.CheckpointTriggerType]string, error) {
+	events := map[migrationv1.CheckpointTriggerType]string{}
+
+	// 모든 스케줄 백업은 축출 웹훅의 요청에 반응
+	var pod corev1.Pod
+	if err := r.Get(ctx, types.NamespacedName{Name: backup.Spec.PodRef.Name, Namespace: backup.Spec.PodRef.Namespace}, &pod); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get pod: %w", err)
+		}
+	}
+	events[migrationv1.TriggerEviction] = pod.Annotations[AnnoEvictionCheckpoint]
+
+	var node *corev1.Node
+	getNode := func() (*corev1.Node, error) {
+		if node == nil {
+			node = &corev1.Node{}
+			if err := r.Get(ctx, types.NamespacedName{Name: r.NodeName}, node); err != nil {
+				return nil, fmt.Errorf("failed to get node %s: %w", r.NodeName, err)
+			}
+		}
+		return node, nil
+	}
+
+	for _, t := range backup.Spec.Triggers {
+		switch t.Type {
+		case migrationv1.TriggerNodeTaint:
+			n, err := getNode()
+			if err != nil {
+				return nil, err
+			}
+			var taints []string
+			for _, taint := range n.Spec.Taints {
+				if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+					continue
+				}
+				if len(t.TaintKeys) > 0 && !slices.Contains(t.TaintKeys, taint.Key) {
+					continue
+				}
+				taints = append(taints, taint.ToString())
+			}
+			sort.Strings(taints)
+			events[t.Type] = strings.Join(taints, ",")
+
+		case migrationv1.TriggerNodeCordon:
+			n, err := getNode()
+			if err != nil {
+				return nil, err
+			}
+			if n.Spec.Unschedulable {
+				events[t.Type] = "cordoned"
+			}
+
+		case migrationv1.TriggerPodAnnotation:
+			events[t.Type] = pod.Annotations[AnnoCheckpointRequest]
+
+		case migrationv1.TriggerClusterNotReady:
+			events[t.Type] = clusterNotReadyEvent(backup.Annotations[AnnoCheckpointRequest], time.Now())
+		}
+	}
+	return events, nil
+}
+
+// backupsForNode enqueues the CheckpointBackups with node triggers when the agent's node is
+// tainted or cordoned
+func (r *CheckpointBackupReconciler) backupsForNode(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetName() != r.NodeName {
+		return nil
+	}
+	return r.backupsWithTrigger(ctx, "", "", migrationv1.TriggerNodeTaint, migrationv1.TriggerNodeCordon)
+}
+
+// backupsForPod enqueues the CheckpointBackups of the pod; all of them react to the eviction
+// webhook, those with a PodAnnotation trigger also to the checkpoint request annotation
+func (r *CheckpointBackupReconciler) backupsForPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.backupsWithTrigger(ctx, obj.GetNamespace(), obj.GetName())
+}
+
+// backupsWithTrigger lists the cached CheckpointBackups, of one pod when podName is set, that
+// declare one of the trigger types, or all of them when no type is given
+func (r *CheckpointBackupReconciler) backupsWithTrigger(ctx context.Context, namespace, podName string, triggerTypes ...migrationv1.CheckpointTriggerType) []reconcile.Request {
+	var backups migrationv1.CheckpointBackupList
+	if err := r.List(ctx, &backups); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list CheckpointBackups for trigger")
+		return nil
+	}
+	var out []reconcile.Request
+	for _, b := range backups.Items {
+		if podName != "" && (b.Spec.PodRef.Name != podName || b.Spec.PodRef.Namespace != namespace) {
+			continue
+		}
+		if len(triggerTypes) == 0 {
+			out = append(out, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&b)})
+			continue
+		}
+		for _, t := range b.Spec.Triggers {
+			if slices.Contains(triggerTypes, t.Type) {
+				out = append(out, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&b)})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// nodeTriggerChanged passes node updates that change its taints or cordon
+func nodeTriggerChanged(e event.UpdateEvent) bool {
+	oldNode, ok1 := e.ObjectOld.(*corev1.Node)
+	newNode, ok2 := e.ObjectNew.(*corev1.Node)
+	if !ok1 || !ok2 {
+		return false
+	}
+	return oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable ||
+		fmt.Sprintf("%v", oldNode.Spec.Taints) != fmt.Sprintf("%v", newNode.Spec.Taints)
+}
+
+// podTriggerChanged passes pod updates that change the checkpoint request or eviction annotation
+func podTriggerChanged(e event.UpdateEvent) bool {
+	oldAnno, newAnno := e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()
+	return oldAnno[AnnoCheckpointRequest] != newAnno[AnnoCheckpointRequest] ||
+		oldAnno[AnnoEvictionCheckpoint] != newAnno[AnnoEvictionCheckpoint]
+}
+
+// ClusterTriggerReconciler requests a checkpoint of the CheckpointBackups on a member cluster with
+// a ClusterNotReady trigger when Karmada reports the cluster NotReady. The request is an
+// annotation on the backup on Karmada, "NotReady@<transition time>", propagated to the member
+// as long as it is reachable. A member that was unreachable only receives it once it is back;
+// the checkpoint agent ignores requests older than ClusterNotReadyTriggerMaxDelay.
+type ClusterTriggerReconciler struct {
+	// Karmada control-plane client (CheckpointBackup 어노테이션)
+	KarmadaClient *KarmadaClient
+
+	// Karmada control-plane cache holding the Clusters
+	clusters client.Reader
+}
+
+// +kubebuilder:rbac:groups=cluster.karmada.io,resources=clusters,verbs=get;list;watch
+
+func (r *ClusterTriggerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	c := newClusterU()
+	if err := r.clusters.Get(ctx, req.NamespacedName, c); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if clusterReadyStatusU(c) == string(metav1.ConditionTrue) {
+		return ctrl.Result{}, nil
+	}
+	request := clusterNotReadyRequestPrefix + clusterReadyTransitionU(c)
+
+	var backups migrationv1.CheckpointBackupList
+	if err := r.KarmadaClient.List(ctx, &backups, client.MatchingLabels{"target-cluster": c.GetName()}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("list CheckpointBackups of cluster %s: %w", c.GetName(), err)
+	}
+	for i := range backups.Items {
+		b := &backups.Items[i]
+		if b.Annotations[AnnoCheckpointRequest] == request ||
+			!slices.ContainsFunc(b.Spec.Triggers, func(t migrationv1.CheckpointTrigger) bool { return t.Type == migrationv1.TriggerClusterNotReady }) {
+			continue
+		}
+		patch := client.MergeFrom(b.DeepCopy())
+		if b.Annotations == nil {
+			b.Annotations = map[string]string{}
+		}
+		b.Annotations[AnnoCheckpointRequest] = request
+		if err := r.KarmadaClient.Patch(ctx, b, patch); err != nil {
+			return ctrl.Result{}, fmt.Errorf("request checkpoint of %s/%s: %w", b.Namespace, b.Name, err)
+		}
+		logf.FromContext(ctx).Info("Requested checkpoint for NotReady cluster", "cluster", c.GetName(), "backup", b.Name)
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager watches the Ready condition of the Clusters through a cache on the Karmada control plane
+func (r *ClusterTriggerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.KarmadaClient == nil {
+		return fmt.Errorf("Karmada client not initialized")
+	}
+	karmadaCluster, err := cluster.New(r.KarmadaClient.RESTConfig(), func(o *cluster.Options) {
+		o.Scheme = r.KarmadaClient.Scheme()
+		o.Client.Cache = &client.CacheOptions{Unstructured: true}
+	})
+	if err != nil {
+		return fmt.Errorf("create Karmada cluster cache: %w", err)
+	}
+	if err := mgr.Add(karmadaCluster); err != nil {
+		return err
+	}
+	r.clusters = karmadaCluster.GetClient()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("checkpointtrigger").
+		WatchesRawSource(source.Kind(karmadaCluster.GetCache(), newClusterU(),
+			&handler.TypedEnqueueRequestForObject[*unstructured.Unstructured]{}, clusterConditionPredicate())).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 1}).
+		Complete(r)
+}
+
+// clusterNotReadyEvent returns the event of a ClusterNotReady request, or "" when the request
+// reached the member more than ClusterNotReadyTriggerMaxDelay after the cluster became NotReady:
+// the member was unreachable then, and a checkpoint now no longer helps the failover.
+func clusterNotReadyEvent(request string, now time.Time) string {
+	ts, ok := strings.CutPrefix(request, clusterNotReadyRequestPrefix)
+	if !ok {
+		return request
+	}
+	if since, err := time.Parse(time.RFC3339, ts); err == nil && now.Sub(since) > ClusterNotReadyTriggerMaxDelay {
+		return ""
+	}
+	return request
+}
+
+// clusterReadyTransitionU returns the last transition time of the Ready condition of a Cluster
+func clusterReadyTransitionU(c *unstructured.Unstructured) string {
+	conds, _, _ := unstructured.NestedSlice(c.Object, "status", "conditions")
+	for _, it := range conds {
+		if m, ok := it.(map[string]interface{}); ok && m["type"] == clusterv1alpha1.ClusterConditionReady {
+			t, _ := m["lastTransitionTime"].(string)
+			return t
+		}
+	}
+	return ""
+}
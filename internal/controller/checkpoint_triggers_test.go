@@ -0,0 +1,45 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClusterNotReadyEvent(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		request string
+		want    string
+	}{
+		{name: "no request", request: "", want: ""},
+		{name: "fresh request", request: "NotReady@2025-06-01T11:58:00Z", want: "NotReady@2025-06-01T11:58:00Z"},
+		{name: "request at the limit", request: "NotReady@2025-06-01T11:55:00Z", want: "NotReady@2025-06-01T11:55:00Z"},
+		{name: "request delivered after the cluster came back", request: "NotReady@2025-06-01T11:30:00Z", want: ""},
+		{name: "request without a transition time", request: "NotReady@", want: "NotReady@"},
+		{name: "request set by hand", request: "manual-1", want: "manual-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clusterNotReadyEvent(tt.request, now); got != tt.want {
+				t.Errorf("clusterNotReadyEvent(%q) = %q, want %q", tt.request, got, tt.want)
+			}
+		})
+	}
+}
@@ -18,27 +18,27 @@ package controller
 
 import (
 	"context"
-	"crypto/tls"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/robfig/cron/v3"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 )
@@ -48,6 +48,9 @@ const (
 	CheckpointBasePath        = "/var/lib/kubelet/checkpoints"
 	ServiceAccountPath        = "/var/run/secrets/kubernetes.io/serviceaccount"
 
+	// MaxBuiltImageHistory is how many pushed images are kept per container in status.builtImages
+	MaxBuiltImageHistory = 10
+
 	// Phase constants
 	PhaseCheckpointing       = "Checkpointing"
 	PhaseCheckpointed        = "Checkpointed"
@@ -70,19 +73,23 @@ type CheckpointResponse struct {
 // CheckpointBackupReconciler reconciles a CheckpointBackup object
 type CheckpointBackupReconciler struct {
 	client.Client
-	Scheme         *runtime.Scheme
-	NodeName       string
-	KubeletClient  *KubeletClient
-	RegistryClient *RegistryClient
-	Scheduler      *cron.Cron
-	scheduledJobs  map[string]cron.EntryID // Track scheduled jobs
-}
-
-// KubeletClient handles communication with kubelet API
-type KubeletClient struct {
-	httpClient *http.Client
-	token      string
-	kubeletURL string
+	Scheme       *runtime.Scheme
+	NodeName     string
+	Checkpointer Checkpointer
+	// CheckpointerOptions select and configure the Checkpointer created in SetupWithManager
+	CheckpointerOptions CheckpointerOptions
+	RegistryClient      *RegistryClient
+	// CheckpointTimeout bounds a checkpoint run of backups without spec.checkpointTimeout
+	CheckpointTimeout time.Duration
+	// MaxConcurrentReconciles is the number of CheckpointBackups reconciled in parallel
+	MaxConcurrentReconciles int
+	// Pool limits the checkpoints, image builds and pushes running at once on the node
+	Pool CheckpointPoolOptions
+
+	// 백그라운드 체크포인트 실행 추적, 클라이언트 초기화 보호
+	operations checkpointOperations
+	pool       *checkpointPool
+	clientsMu  sync.Mutex
 }
 
 // RegistryClient handles container registry operations
@@ -98,6 +105,7 @@ type RegistryClient struct {
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
 // +kubebuilder:rbac:groups="",resources=pods/checkpoint,verbs=patch;create;update;proxy
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *CheckpointBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -108,13 +116,8 @@ func (r *CheckpointBackupReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	if err := r.Get(ctx, req.NamespacedName, &checkpointBackup); err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("CheckpointBackup resource not found. Ignoring since object must be deleted")
-			// Clean up any scheduled job
-			if r.scheduledJobs != nil {
-				if entryID, exists := r.scheduledJobs[req.NamespacedName.String()]; exists {
-					r.Scheduler.Remove(entryID)
-					delete(r.scheduledJobs, req.NamespacedName.String())
-				}
-			}
+			// Stop a checkpoint in progress
+			r.cancelCheckpoint(req.NamespacedName.String())
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "Failed to get CheckpointBackup")
@@ -163,14 +166,13 @@ func (r *CheckpointBackupReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	return r.reconcileNormal(ctx, &checkpointBackup)
 }
 
-// initializeClients initializes the kubelet and registry clients
+// initializeClients initializes the registry client and the scheduler
 func (r *CheckpointBackupReconciler) initializeClients(ctx context.Context, backup *migrationv1.CheckpointBackup) error {
-	if r.KubeletClient == nil {
-		kubeletClient, err := NewKubeletClient()
-		if err != nil {
-			return fmt.Errorf("failed to create kubelet client: %w", err)
-		}
-		r.KubeletClient = kubeletClient
+	r.clientsMu.Lock()
+	defer r.clientsMu.Unlock()
+
+	if r.Checkpointer == nil {
+		return fmt.Errorf("checkpointer not initialized")
 	}
 
 	if r.RegistryClient == nil && backup.Spec.Registry != nil {
@@ -181,44 +183,9 @@ func (r *CheckpointBackupReconciler) initializeClients(ctx context.Context, back
 		r.RegistryClient = registryClient
 	}
 
-	if r.Scheduler == nil {
-		r.Scheduler = cron.New()
-		r.Scheduler.Start()
-		r.scheduledJobs = make(map[string]cron.EntryID)
-	}
-
 	return nil
 }
 
-// NewKubeletClient creates a new kubelet client
-func NewKubeletClient() (*KubeletClient, error) {
-	// Read service account token
-	tokenBytes, err := os.ReadFile(filepath.Join(ServiceAccountPath, "token"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to read service account token: %w", err)
-	}
-
-	// Get node IP from environment or use localhost
-	kubeletURL := "https://localhost:10250"
-	if nodeIP := os.Getenv("NODE_IP"); nodeIP != "" {
-		kubeletURL = fmt.Sprintf("https://%s:10250", nodeIP)
-	}
-
-	// Create HTTP client with TLS config (skip verification for kubelet)
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-		Timeout: 300 * time.Second,
-	}
-
-	return &KubeletClient{
-		httpClient: httpClient,
-		token:      string(tokenBytes),
-		kubeletURL: kubeletURL,
-	}, nil
-}
-
 // NewRegistryClient creates a new registry client using the registry configuration from CheckpointBackup
 func (r *CheckpointBackupReconciler) NewRegistryClient(ctx context.Context, registryConfig migrationv1.Registry) (*RegistryClient, error) {
 	// Determine secret name and namespace
@@ -265,7 +232,8 @@ func (r *CheckpointBackupReconciler) NewRegistryClient(ctx context.Context, regi
 	}, nil
 }
 
-// isPodOnThisNode checks if the pod referenced in CheckpointBackup is on this node
+// isPodOnThisNode checks if the pod referenced in CheckpointBackup is on this node. The agent's
+// pod cache only holds pods of its node, so pods elsewhere are not found.
 func (r *CheckpointBackupReconciler) isPodOnThisNode(ctx context.Context, backup *migrationv1.CheckpointBackup) (bool, error) {
 	var pod corev1.Pod
 	if err := r.Get(ctx, types.NamespacedName{
@@ -403,8 +371,10 @@ func (r *CheckpointBackupReconciler) recordCheckpointFile(ctx context.Context, b
 	return fmt.Errorf("failed to record checkpoint file after %d retries", maxRetries)
 }
 
-// recordBuiltImage adds the built image information to the backup status with retry on conflict
-func (r *CheckpointBackupReconciler) recordBuiltImage(ctx context.Context, backup *migrationv1.CheckpointBackup, containerName, imageName string, pushed bool) error {
+// recordBuiltImage adds the built image information to the backup status with retry on conflict.
+// Each push is kept with its digest (up to MaxBuiltImageHistory per container), since the next
+// checkpoint overwrites the tag.
+func (r *CheckpointBackupReconciler) recordBuiltImage(ctx context.Context, backup *migrationv1.CheckpointBackup, containerName, imageName, digest string, pushed bool) error {
 	// Use retry logic to handle conflicts
 	maxRetries := 3
 	for i := 0; i < maxRetries; i++ {
@@ -420,7 +390,7 @@ func (r *CheckpointBackupReconciler) recordBuiltImage(ctx context.Context, backu
 		// Check if this image is already recorded (avoid duplicates)
 		alreadyRecorded := false
 		for _, builtImage := range latestBackup.Status.BuiltImages {
-			if builtImage.ContainerName == containerName && builtImage.ImageName == imageName {
+			if builtImage.ContainerName == containerName && builtImage.ImageName == imageName && builtImage.Digest == digest {
 				// Image already recorded, no need to add again
 				alreadyRecorded = true
 				break
@@ -438,9 +408,10 @@ func (r *CheckpointBackupReconciler) recordBuiltImage(ctx context.Context, backu
 			ImageName:     imageName,
 			BuildTime:     &now,
 			Pushed:        pushed,
+			Digest:        digest,
 		}
 
-		latestBackup.Status.BuiltImages = append(latestBackup.Status.BuiltImages, newBuiltImage)
+		latestBackup.Status.BuiltImages = trimBuiltImages(append(latestBackup.Status.BuiltImages, newBuiltImage), containerName)
 
 		// Update the status
 		if err := r.Status().Update(ctx, &latestBackup); err != nil {
@@ -460,6 +431,25 @@ func (r *CheckpointBackupReconciler) recordBuiltImage(ctx context.Context, backu
 	return fmt.Errorf("failed to record built image after %d retries", maxRetries)
 }
 
+// trimBuiltImages drops the oldest images of the container beyond MaxBuiltImageHistory
+func trimBuiltImages(images []migrationv1.BuiltImage, containerName string) []migrationv1.BuiltImage {
+	count := 0
+	for _, img := range images {
+		if img.ContainerName == containerName {
+			count++
+		}
+	}
+	out := make([]migrationv1.BuiltImage, 0, len(images))
+	for _, img := range images {
+		if img.ContainerName == containerName && count > MaxBuiltImageHistory {
+			count--
+			continue
+		}
+		out = append(out, img)
+	}
+	return out
+}
+
 // reconcileNormal handles the normal reconciliation logic
 func (r *CheckpointBackupReconciler) reconcileNormal(ctx context.Context, backup *migrationv1.CheckpointBackup) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
@@ -484,11 +474,17 @@ func (r *CheckpointBackupReconciler) reconcileNormal(ctx context.Context, backup
 					"backup", backup.Name,
 					"phase", backup.Status.Phase,
 					"message", backup.Status.Message)
-			} else {
+			} else if r.operations.running(backupKey) {
 				// In progress (Checkpointing, ImageBuilding, etc.)
 				log.Info("Immediate checkpoint already in progress",
 					"backup", backup.Name,
 					"phase", backup.Status.Phase)
+			} else {
+				// 진행 중 상태인데 실행 중인 작업이 없음: 에이전트 재시작으로 중단된 실행을 이어서 수행
+				log.Info("Resuming interrupted immediate checkpoint",
+					"backup", backup.Name,
+					"phase", backup.Status.Phase)
+				r.startCheckpoint(backup, "resume")
 			}
 			return ctrl.Result{}, nil
 		}
@@ -496,62 +492,28 @@ func (r *CheckpointBackupReconciler) reconcileNormal(ctx context.Context, backup
 		// No phase set yet - this is the first time, proceed with checkpoint
 		log.Info("Starting immediate checkpoint for the first time", "backup", backup.Name)
 
-		// Perform immediate checkpoint
-		if err := r.performCheckpoint(ctx, backup); err != nil {
-			log.Error(err, "Failed to perform immediate checkpoint")
-			return ctrl.Result{}, err
+		// Run the checkpoint in the background; progress is reported in status
+		if r.startCheckpoint(backup, "immediately") {
+			log.Info("Immediate checkpoint started", "backup", backup.Name, "timeout", r.checkpointTimeout(backup))
 		}
-
-		log.Info("Immediate checkpoint completed", "backup", backup.Name)
 		return ctrl.Result{}, nil
 	}
 
-	// Handle regular cron schedule
-	// Remove existing job if schedule changed
-	if entryID, exists := r.scheduledJobs[backupKey]; exists {
-		r.Scheduler.Remove(entryID)
-		delete(r.scheduledJobs, backupKey)
-	}
-
-	// Add new scheduled job
-	entryID, err := r.Scheduler.AddFunc(backup.Spec.Schedule, func() {
-		if err := r.performCheckpoint(context.Background(), backup); err != nil {
-			log.Error(err, "Failed to perform checkpoint", "backup", backup.Name)
-		}
-	})
-	if err != nil {
-		log.Error(err, "Failed to schedule checkpoint job")
-		return ctrl.Result{}, err
-	}
-
-	r.scheduledJobs[backupKey] = entryID
-	log.Info("Scheduled checkpoint job", "backup", backup.Name, "schedule", backup.Spec.Schedule)
-
-	// Also perform immediate checkpoint on first reconcile
-	if backup.Status.LastCheckpointTime == nil {
-		if err := r.performCheckpoint(ctx, backup); err != nil {
-			log.Error(err, "Failed to perform initial checkpoint")
-			return ctrl.Result{}, err
-		}
-	}
-
-	return ctrl.Result{RequeueAfter: time.Hour}, nil
+	// Handle regular cron schedule: the due run is started and the backup requeued for the next one
+	return r.reconcileSchedule(ctx, backup)
 }
 
 // reconcileDelete handles the deletion logic
 func (r *CheckpointBackupReconciler) reconcileDelete(ctx context.Context, backup *migrationv1.CheckpointBackup) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	// Remove scheduled job
 	backupKey := types.NamespacedName{
 		Name:      backup.Name,
 		Namespace: backup.Namespace,
 	}.String()
 
-	if entryID, exists := r.scheduledJobs[backupKey]; exists {
-		r.Scheduler.Remove(entryID)
-		delete(r.scheduledJobs, backupKey)
-	}
+	// Stop a checkpoint in progress; its kubelet call and image build are cancelled
+	r.cancelCheckpoint(backupKey)
 
 	// Remove finalizer
 	controllerutil.RemoveFinalizer(backup, CheckpointBackupFinalizer)
@@ -568,28 +530,17 @@ func (r *CheckpointBackupReconciler) reconcileDelete(ctx context.Context, backup
 func (r *CheckpointBackupReconciler) performCheckpoint(ctx context.Context, backup *migrationv1.CheckpointBackup) error {
 	log := logf.FromContext(ctx)
 
-	// Check if already completed or in terminal state to avoid re-running
-	if backup.Status.Phase == PhaseCompleted ||
+	// An immediate checkpoint runs once; a scheduled one runs again after completing
+	if backup.Spec.Schedule == "immediately" && (backup.Status.Phase == PhaseCompleted ||
 		backup.Status.Phase == PhaseCompletedPodDeleted ||
-		backup.Status.Phase == PhaseCompletedWithError {
+		backup.Status.Phase == PhaseCompletedWithError) {
 		log.Info("Checkpoint already in terminal state, skipping",
 			"backup", backup.Name,
 			"phase", backup.Status.Phase)
 		return nil
 	}
 
-	// Check if already in progress (shouldn't happen, but defensive check)
-	if backup.Status.Phase == PhaseCheckpointing ||
-		backup.Status.Phase == PhaseCheckpointed ||
-		backup.Status.Phase == PhaseImageBuilding ||
-		backup.Status.Phase == PhaseImageBuilt ||
-		backup.Status.Phase == PhaseImagePushing ||
-		backup.Status.Phase == PhaseImagePushed {
-		log.Info("Checkpoint already in progress, skipping duplicate",
-			"backup", backup.Name,
-			"phase", backup.Status.Phase)
-		return nil
-	}
+	// Runs of the same backup never overlap: startCheckpoint tracks them in r.operations
 
 	log.Info("Starting checkpoint operation", "backup", backup.Name, "pod", backup.Spec.PodRef.Name)
 
@@ -621,13 +572,39 @@ func (r *CheckpointBackupReconciler) performCheckpoint(ctx context.Context, back
 			"containerCount", len(containersToProcess))
 	}
 
+	// Report the run in status.progress
+	if err := r.updateProgress(ctx, backup, func(p *migrationv1.CheckpointProgress) {
+		now := metav1.Now()
+		*p = migrationv1.CheckpointProgress{StartTime: &now, TotalContainers: int32(len(containersToProcess))}
+		if budget := runBudgetFrom(ctx); budget != nil {
+			p.Deadline = &metav1.Time{Time: budget.deadline()}
+		}
+	}); err != nil {
+		log.Error(err, "Failed to record checkpoint progress")
+	}
+
 	// Process each container
-	for _, container := range containersToProcess {
+	for i, container := range containersToProcess {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.updateProgress(ctx, backup, func(p *migrationv1.CheckpointProgress) {
+			p.CompletedContainers = int32(i)
+			p.CurrentContainer = container.Name
+		}); err != nil {
+			log.Error(err, "Failed to record checkpoint progress")
+		}
 		if err := r.checkpointContainer(ctx, backup, &pod, container); err != nil {
 			log.Error(err, "Failed to checkpoint container", "container", container.Name)
 			return err
 		}
 	}
+	if err := r.updateProgress(ctx, backup, func(p *migrationv1.CheckpointProgress) {
+		p.CompletedContainers = int32(len(containersToProcess))
+		p.CurrentContainer = ""
+	}); err != nil {
+		log.Error(err, "Failed to record checkpoint progress")
+	}
 
 	// Update status: Completed
 	now := metav1.Now()
@@ -651,19 +628,7 @@ func (r *CheckpointBackupReconciler) performCheckpoint(ctx context.Context, back
 			return err
 		}
 
-		// Remove any scheduled jobs since pod is deleted and no further checkpoints are needed
-		backupKey := types.NamespacedName{
-			Name:      backup.Name,
-			Namespace: backup.Namespace,
-		}.String()
-
-		if entryID, exists := r.scheduledJobs[backupKey]; exists {
-			r.Scheduler.Remove(entryID)
-			delete(r.scheduledJobs, backupKey)
-			log.Info("Removed scheduled job after pod deletion", "backup", backup.Name)
-		}
-
-		// Update status to reflect pod deletion
+		// Update status to reflect pod deletion; no further scheduled runs are started
 		if err := r.updatePhase(ctx, backup, PhaseCompletedPodDeleted, "Checkpoint completed and pod deleted successfully"); err != nil {
 			log.Error(err, "Failed to update backup status after pod deletion")
 			return err
@@ -722,20 +687,29 @@ func (r *CheckpointBackupReconciler) checkpointContainer(ctx context.Context, ba
 	}
 
 	// If checkpoint doesn't exist or file is missing, create it
+	created := false
 	if checkpointPath == "" {
 		// Update status: Checkpointing
 		if err := r.updatePhase(ctx, backup, PhaseCheckpointing, fmt.Sprintf("Creating checkpoint for container %s", container.Name)); err != nil {
 			log.Error(err, "Failed to update phase to Checkpointing")
 		}
 
-		// Step 1: Call kubelet checkpoint API
-		checkpointPath, err = r.KubeletClient.CreateCheckpoint(backup.Spec.PodRef.Namespace, backup.Spec.PodRef.Name, container.Name)
+		// Step 1: Checkpoint through the node's backend (kubelet API, CRI or containerd)
+		stepCtx, release, err := r.acquireStep(ctx, backup, StepCheckpoint)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		checkpointPath, err = r.Checkpointer.Checkpoint(stepCtx, pod, container.Name)
+		observeStep(StepCheckpoint, start)
+		release()
 		if err != nil {
 			if updateErr := r.updatePhase(ctx, backup, PhaseFailed, fmt.Sprintf("Failed to create checkpoint: %v", err)); updateErr != nil {
 				log.Error(updateErr, "Failed to update phase to Failed")
 			}
-			return fmt.Errorf("failed to create checkpoint via kubelet API: %w", err)
+			return &checkpointStepError{step: StepCheckpoint, err: fmt.Errorf("failed to create checkpoint via %s backend: %w", r.Checkpointer.Backend(), err)}
 		}
+		created = true
 
 		// Record the checkpoint file in status
 		if err := r.recordCheckpointFile(ctx, backup, container.Name, checkpointPath); err != nil {
@@ -763,6 +737,9 @@ func (r *CheckpointBackupReconciler) checkpointContainer(ctx context.Context, ba
 	} else {
 		log.Info("Checkpoint file found as expected", "path", checkpointPath)
 	}
+	if created {
+		observeCheckpointArchive(ctx, filepath.Join(CheckpointBasePath, checkpointPath))
+	}
 
 	// Step 2: Get the original container image
 	var baseImage string
@@ -794,11 +771,19 @@ func (r *CheckpointBackupReconciler) checkpointContainer(ctx context.Context, ba
 	}
 
 	// Step 4: Build checkpoint image using buildah
-	if err := r.buildCheckpointImage(checkpointPath, imageName, baseImage, container.Name); err != nil {
+	stepCtx, release, err := r.acquireStep(ctx, backup, StepBuild)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	err = r.buildCheckpointImage(stepCtx, checkpointPath, imageName, baseImage, container.Name)
+	observeStep(StepBuild, start)
+	release()
+	if err != nil {
 		if updateErr := r.updatePhase(ctx, backup, PhaseFailed, fmt.Sprintf("Failed to build image: %v", err)); updateErr != nil {
 			log.Error(updateErr, "Failed to update phase to Failed")
 		}
-		return fmt.Errorf("failed to build checkpoint image: %w", err)
+		return &checkpointStepError{step: StepBuild, err: fmt.Errorf("failed to build checkpoint image: %w", err)}
 	}
 
 	// Update status: Image built
@@ -808,22 +793,31 @@ func (r *CheckpointBackupReconciler) checkpointContainer(ctx context.Context, ba
 
 	// Step 5: Push image to registry (only if registry is configured)
 	pushed := false
+	digest := ""
 	if backup.Spec.Registry != nil && r.RegistryClient != nil {
 		// Update status: Pushing image
 		if err := r.updatePhase(ctx, backup, PhaseImagePushing, fmt.Sprintf("Pushing image %s to registry", imageName)); err != nil {
 			log.Error(err, "Failed to update phase to ImagePushing")
 		}
 
-		if err := r.RegistryClient.PushImage(imageName); err != nil {
+		stepCtx, release, err := r.acquireStep(ctx, backup, StepPush)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		digest, err = r.RegistryClient.PushImage(stepCtx, imageName)
+		observeStep(StepPush, start)
+		release()
+		if err != nil {
 			if updateErr := r.updatePhase(ctx, backup, PhaseFailed, fmt.Sprintf("Failed to push image: %v", err)); updateErr != nil {
 				log.Error(updateErr, "Failed to update phase to Failed")
 			}
-			return fmt.Errorf("failed to push checkpoint image: %w", err)
+			return &checkpointStepError{step: StepPush, err: fmt.Errorf("failed to push checkpoint image: %w", err)}
 		}
 		pushed = true
 
 		// Update status: Image pushed
-		if err := r.updatePhase(ctx, backup, PhaseImagePushed, fmt.Sprintf("Image pushed successfully: %s", imageName)); err != nil {
+		if err := r.updatePhase(ctx, backup, PhaseImagePushed, fmt.Sprintf("Image pushed successfully: %s@%s", imageName, digest)); err != nil {
 			log.Error(err, "Failed to update phase to ImagePushed")
 		}
 		log.Info("Successfully checkpointed and pushed container image", "container", container.Name, "image", imageName)
@@ -832,7 +826,7 @@ func (r *CheckpointBackupReconciler) checkpointContainer(ctx context.Context, ba
 	}
 
 	// Step 6: Record the built image in the backup status
-	if err := r.recordBuiltImage(ctx, backup, container.Name, imageName, pushed); err != nil {
+	if err := r.recordBuiltImage(ctx, backup, container.Name, imageName, digest, pushed); err != nil {
 		log.Error(err, "Failed to record built image", "container", container.Name, "image", imageName)
 		// Don't return error here as the checkpoint was successful
 	}
@@ -853,73 +847,6 @@ func (r *CheckpointBackupReconciler) checkpointContainer(ctx context.Context, ba
 	return nil
 }
 
-// CreateCheckpoint calls kubelet checkpoint API
-func (kc *KubeletClient) CreateCheckpoint(namespace, podName, containerName string) (string, error) {
-	url := fmt.Sprintf("%s/checkpoint/%s/%s/%s?timeout=300", kc.kubeletURL, namespace, podName, containerName)
-
-	req, err := http.NewRequest("POST", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+kc.token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := kc.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to call kubelet checkpoint API: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("kubelet checkpoint API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	// First, try to read the response body to see what we actually get
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read checkpoint response body: %w", err)
-	}
-
-	// Log the raw response for debugging
-	fmt.Printf("DEBUG: Kubelet checkpoint API response body: %s\n", string(body))
-
-	var checkpointResp CheckpointResponse
-	if err := json.Unmarshal(body, &checkpointResp); err != nil {
-		// If JSON parsing fails, fall back to file search by returning a placeholder
-		responseText := strings.TrimSpace(string(body))
-		fmt.Printf("DEBUG: Failed to parse JSON response from kubelet: %s\n", responseText)
-		return "unknown-checkpoint-file", nil
-	}
-
-	if len(checkpointResp.Items) == 0 {
-		// If JSON response doesn't contain any items, fall back to file search
-		fmt.Printf("DEBUG: JSON response has no checkpoint items, falling back to file search\n")
-		return "unknown-checkpoint-file", nil
-	}
-
-	// Use the first (and likely only) checkpoint path from the response
-	checkpointPath := checkpointResp.Items[0]
-	fmt.Printf("DEBUG: Successfully parsed JSON response, checkpoint path: %s\n", checkpointPath)
-
-	// Convert absolute path to relative path (remove the base path prefix)
-	if strings.HasPrefix(checkpointPath, CheckpointBasePath+"/") {
-		relativePath := strings.TrimPrefix(checkpointPath, CheckpointBasePath+"/")
-		fmt.Printf("DEBUG: Converted to relative path: %s\n", relativePath)
-		return relativePath, nil
-	} else if strings.HasPrefix(checkpointPath, "/var/lib/kubelet/checkpoints/") {
-		relativePath := strings.TrimPrefix(checkpointPath, "/var/lib/kubelet/checkpoints/")
-		fmt.Printf("DEBUG: Converted to relative path: %s\n", relativePath)
-		return relativePath, nil
-	}
-
-	// If path doesn't have expected prefix, just return the filename
-	relativePath := filepath.Base(checkpointPath)
-	fmt.Printf("DEBUG: Using filename only: %s\n", relativePath)
-	return relativePath, nil
-}
-
 // findCheckpointFile finds the most recent checkpoint file for a given pod and container
 func (r *CheckpointBackupReconciler) findCheckpointFile(namespace, podName, containerName, expectedPath string) (string, error) {
 	// First try the expected path
@@ -981,8 +908,8 @@ func (r *CheckpointBackupReconciler) findCheckpointFile(namespace, podName, cont
 }
 
 // buildCheckpointImage builds the checkpoint image using buildah
-func (r *CheckpointBackupReconciler) buildCheckpointImage(checkpointPath, imageName, baseImage, containerName string) error {
-	log := logf.FromContext(context.Background())
+func (r *CheckpointBackupReconciler) buildCheckpointImage(ctx context.Context, checkpointPath, imageName, baseImage, containerName string) error {
+	log := logf.FromContext(ctx)
 
 	// Verify the checkpoint file exists (should have been found by findCheckpointFile)
 	fullCheckpointPath := filepath.Join(CheckpointBasePath, checkpointPath)
@@ -993,7 +920,7 @@ func (r *CheckpointBackupReconciler) buildCheckpointImage(checkpointPath, imageN
 	log.Info("Building checkpoint image", "checkpointFile", fullCheckpointPath, "imageName", imageName, "baseImage", baseImage)
 
 	// Step 1: Create new container from scratch
-	cmd := exec.Command("buildah", "from", "scratch")
+	cmd := exec.CommandContext(ctx, "buildah", "from", "scratch")
 	out, err := cmd.Output()
 	if err != nil {
 		return fmt.Errorf("failed to create buildah container: %w", err)
@@ -1006,25 +933,25 @@ func (r *CheckpointBackupReconciler) buildCheckpointImage(checkpointPath, imageN
 	}()
 
 	// Step 2: Add checkpoint tar to root
-	if err := exec.Command("buildah", "add", newContainer, fullCheckpointPath, "/").Run(); err != nil {
+	if err := exec.CommandContext(ctx, "buildah", "add", newContainer, fullCheckpointPath, "/").Run(); err != nil {
 		return fmt.Errorf("failed to add checkpoint to container (%s): %w", fullCheckpointPath, err)
 	}
 
 	// Step 3: Add CRI-O checkpoint annotations
-	if err := exec.Command("buildah", "config",
+	if err := exec.CommandContext(ctx, "buildah", "config",
 		"--annotation=io.kubernetes.cri-o.annotations.checkpoint.name="+imageName,
 		newContainer).Run(); err != nil {
 		return fmt.Errorf("failed to add checkpoint name annotation: %w", err)
 	}
 
-	if err := exec.Command("buildah", "config",
+	if err := exec.CommandContext(ctx, "buildah", "config",
 		"--annotation=io.kubernetes.cri-o.annotations.checkpoint.rootfsImageName="+baseImage,
 		newContainer).Run(); err != nil {
 		return fmt.Errorf("failed to add rootfs image annotation: %w", err)
 	}
 
 	// Step 4: Commit and tag image
-	if err := exec.Command("buildah", "commit", newContainer, imageName).Run(); err != nil {
+	if err := exec.CommandContext(ctx, "buildah", "commit", newContainer, imageName).Run(); err != nil {
 		return fmt.Errorf("failed to commit image: %w", err)
 	}
 
@@ -1032,11 +959,11 @@ func (r *CheckpointBackupReconciler) buildCheckpointImage(checkpointPath, imageN
 	return nil
 }
 
-// PushImage pushes the image to the registry
-func (rc *RegistryClient) PushImage(imageName string) error {
+// PushImage pushes the image to the registry and returns the digest of the pushed manifest
+func (rc *RegistryClient) PushImage(ctx context.Context, imageName string) (string, error) {
 	// Login to registry
-	if err := rc.login(imageName); err != nil {
-		return fmt.Errorf("failed to login to registry: %w", err)
+	if err := rc.login(ctx, imageName); err != nil {
+		return "", fmt.Errorf("failed to login to registry: %w", err)
 	}
 
 	// Trim http:// or https:// prefix from registry URL
@@ -1047,17 +974,29 @@ func (rc *RegistryClient) PushImage(imageName string) error {
 	// Construct destination image: <registry>/<image-name>
 	destinationImage := registryURL + "/" + imageName
 
-	// Push image: buildah push <local-image> <destination-image>
-	cmd := exec.Command("buildah", "push", imageName, destinationImage)
+	// Digest of the pushed manifest is written to a temporary file
+	digestFile, err := os.CreateTemp("", "checkpoint-digest-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create digest file: %w", err)
+	}
+	digestFile.Close()
+	defer os.Remove(digestFile.Name())
+
+	// Push image: buildah push --digestfile <file> <local-image> <destination-image>
+	cmd := exec.CommandContext(ctx, "buildah", "push", "--digestfile", digestFile.Name(), imageName, destinationImage)
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to push image %s to %s: %w", imageName, destinationImage, err)
+		return "", fmt.Errorf("failed to push image %s to %s: %w", imageName, destinationImage, err)
 	}
 
-	return nil
+	digest, err := os.ReadFile(digestFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read digest of %s: %w", destinationImage, err)
+	}
+	return strings.TrimSpace(string(digest)), nil
 }
 
 // login performs registry authentication
-func (rc *RegistryClient) login(imageName string) error {
+func (rc *RegistryClient) login(ctx context.Context, imageName string) error {
 	// Use the registry URL from the secret (not extracted from image name)
 	// For Docker Hub, this should be "docker.io" or can be empty
 
@@ -1067,7 +1006,7 @@ func (rc *RegistryClient) login(imageName string) error {
 	registryURL = strings.TrimPrefix(registryURL, "https://")
 
 	// Login using buildah
-	cmd := exec.Command("buildah", "login", "-u", rc.username, "-p", rc.password, registryURL)
+	cmd := exec.CommandContext(ctx, "buildah", "login", "-u", rc.username, "-p", rc.password, registryURL)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to login to registry %s: %w", registryURL, err)
 	}
@@ -1083,8 +1022,36 @@ func (r *CheckpointBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return fmt.Errorf("NODE_NAME environment variable is required")
 	}
 
+	// 노드 라벨로 백엔드 선택, kubelet 주소/포트는 Node status.daemonEndpoints에서 (캐시 시작 전이므로 APIReader 사용)
+	if r.Checkpointer == nil {
+		checkpointer, err := NewCheckpointer(context.Background(), mgr.GetAPIReader(), r.NodeName, r.CheckpointerOptions)
+		if err != nil {
+			return fmt.Errorf("failed to create checkpointer: %w", err)
+		}
+		r.Checkpointer = checkpointer
+		mgr.GetLogger().Info("Using checkpoint backend", "backend", checkpointer.Backend(), "node", r.NodeName)
+	}
+
+	r.pool = newCheckpointPool(r.Pool)
+
+	// 노드의 체크포인트 디스크 사용량과 StatefulMigration별 마지막 성공 시각은 scrape 시 계산
+	if err := registerCollector(&checkpointAgentCollector{reader: mgr.GetClient(), nodeName: r.NodeName, basePath: CheckpointBasePath}); err != nil {
+		return fmt.Errorf("failed to register checkpoint metrics: %w", err)
+	}
+
+	// 체크포인트는 백그라운드에서 실행되므로 reconcile은 짧게 끝나고 병렬 처리가 의미 있음
+	maxConcurrent := r.MaxConcurrentReconciles
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	// 트리거: 노드 taint/cordon, Pod 어노테이션 변화
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&migrationv1.CheckpointBackup{}).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.backupsForNode),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: nodeTriggerChanged})).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.backupsForPod),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: podTriggerChanged})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrent}).
 		Named("checkpointbackup").
 		Complete(r)
 }
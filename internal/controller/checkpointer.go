@@ -0,0 +1,105 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LabelCheckpointBackend on a Node overrides --checkpoint-backend for the agent on that node
+	LabelCheckpointBackend = "migration.dcnlab.com/checkpoint-backend"
+
+	// Checkpoint backends
+	CheckpointBackendKubelet    = "kubelet"
+	CheckpointBackendCRI        = "cri"
+	CheckpointBackendContainerd = "containerd"
+
+	DefaultCRIRuntimeEndpoint  = "unix:///var/run/crio/crio.sock"
+	DefaultContainerdAddress   = "/run/containerd/containerd.sock"
+	DefaultContainerdNamespace = "k8s.io"
+)
+
+// Checkpointer takes a checkpoint of a running container and returns the path of the
+// checkpoint archive relative to CheckpointBasePath
+type Checkpointer interface {
+	Checkpoint(ctx context.Context, pod *corev1.Pod, containerName string) (string, error)
+	// Backend returns the name of the backend, e.g. "kubelet"
+	Backend() string
+}
+
+// CheckpointerOptions configure the checkpoint backends of the agent
+type CheckpointerOptions struct {
+	// Backend used when the Node has no LabelCheckpointBackend label
+	Backend string
+	// Kubelet configures the kubelet backend
+	Kubelet KubeletClientOptions
+	// RuntimeEndpoint is the CRI socket of the cri backend (CRI-O, or containerd 2.x)
+	RuntimeEndpoint string
+	// ContainerdAddress and ContainerdNamespace locate the containers of the containerd backend
+	ContainerdAddress   string
+	ContainerdNamespace string
+}
+
+// NewCheckpointer returns the backend selected for the node: its LabelCheckpointBackend label,
+// otherwise opts.Backend, otherwise the kubelet API
+func NewCheckpointer(ctx context.Context, reader client.Reader, nodeName string, opts CheckpointerOptions) (Checkpointer, error) {
+	var node corev1.Node
+	if err := reader.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	backend := node.Labels[LabelCheckpointBackend]
+	if backend == "" {
+		backend = opts.Backend
+	}
+	switch backend {
+	case "", CheckpointBackendKubelet:
+		return newKubeletClientForNode(&node, opts.Kubelet)
+	case CheckpointBackendCRI:
+		return NewCRICheckpointer(opts.RuntimeEndpoint)
+	case CheckpointBackendContainerd:
+		return NewContainerdCheckpointer(opts.ContainerdAddress, opts.ContainerdNamespace), nil
+	}
+	return nil, fmt.Errorf("unknown checkpoint backend %q on node %s", backend, nodeName)
+}
+
+// containerID returns the runtime ID of the container from the pod status
+func containerID(pod *corev1.Pod, containerName string) (string, error) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != containerName {
+			continue
+		}
+		// "<runtime>://<id>"
+		if _, id, found := strings.Cut(cs.ContainerID, "://"); found && id != "" {
+			return id, nil
+		}
+		return "", fmt.Errorf("container %s of pod %s/%s has no container ID yet", containerName, pod.Namespace, pod.Name)
+	}
+	return "", fmt.Errorf("container %s not found in pod %s/%s", containerName, pod.Namespace, pod.Name)
+}
+
+// checkpointArchiveName names an archive the way the kubelet does, so findCheckpointFile finds it
+func checkpointArchiveName(pod *corev1.Pod, containerName string) string {
+	return fmt.Sprintf("checkpoint-%s_%s-%s-%s.tar", pod.Namespace, pod.Name, containerName, time.Now().Format(time.RFC3339))
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ContainerdCheckpointer dumps a container with CRIU through the containerd runc shim
+// (`ctr tasks checkpoint`) for containerd releases without CRI checkpoint support, and packs
+// the CRIU image into an archive laid out like the kubelet's: checkpoint/, config.dump,
+// spec.dump, and rootfs-diff.tar with deleted.files for the changes of the writable layer.
+// The host needs CRIU installed for runc.
+type ContainerdCheckpointer struct {
+	address   string
+	namespace string
+}
+
+// NewContainerdCheckpointer returns a checkpointer using the containerd socket at address
+func NewContainerdCheckpointer(address, namespace string) *ContainerdCheckpointer {
+	if address == "" {
+		address = DefaultContainerdAddress
+	}
+	if namespace == "" {
+		namespace = DefaultContainerdNamespace
+	}
+	return &ContainerdCheckpointer{address: address, namespace: namespace}
+}
+
+// Backend implements Checkpointer
+func (c *ContainerdCheckpointer) Backend() string {
+	return CheckpointBackendContainerd
+}
+
+// Checkpoint dumps the container, leaving it running, and archives the dump in CheckpointBasePath
+func (c *ContainerdCheckpointer) Checkpoint(ctx context.Context, pod *corev1.Pod, containerName string) (string, error) {
+	id, err := containerID(pod, containerName)
+	if err != nil {
+		return "", err
+	}
+	name := checkpointArchiveName(pod, containerName)
+
+	// 1) CRIU 이미지: shim이 호스트 경로에 쓰므로 호스트와 같은 경로로 마운트된 디렉터리 사용
+	workDir := filepath.Join(CheckpointBasePath, strings.TrimSuffix(name, ".tar")+".d")
+	defer os.RemoveAll(workDir)
+	imageDir := filepath.Join(workDir, "checkpoint")
+	if err := os.MkdirAll(imageDir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	if _, err := c.ctr(ctx, "tasks", "checkpoint", "--image-path", imageDir, id); err != nil {
+		return "", err
+	}
+
+	checkpointedTime := time.Now().UTC()
+
+	// 2) 컨테이너 spec과 이미지 정보
+	out, err := c.ctr(ctx, "containers", "info", id)
+	if err != nil {
+		return "", err
+	}
+	var info struct {
+		Image   string `json:"Image"`
+		Runtime struct {
+			Name string `json:"Name"`
+		} `json:"Runtime"`
+		SnapshotKey string          `json:"SnapshotKey"`
+		Snapshotter string          `json:"Snapshotter"`
+		CreatedAt   time.Time       `json:"CreatedAt"`
+		Spec        json.RawMessage `json:"Spec"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return "", fmt.Errorf("failed to parse containerd container info: %w", err)
+	}
+	// 복원 시 런타임이 rootfs 이미지를 찾는 필드 (CRI-O/containerd CRI의 config.dump와 동일)
+	imageRef := info.Image
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == containerName && cs.ImageID != "" {
+			imageRef = cs.ImageID
+		}
+	}
+	config, err := json.Marshal(map[string]interface{}{
+		"id":               id,
+		"name":             containerName,
+		"rootfsImage":      info.Image,
+		"rootfsImageRef":   imageRef,
+		"rootfsImageName":  info.Image,
+		"runtime":          info.Runtime.Name,
+		"createdTime":      info.CreatedAt.UTC(),
+		"checkpointedTime": checkpointedTime,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "config.dump"), config, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write config.dump: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "spec.dump"), info.Spec, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write spec.dump: %w", err)
+	}
+
+	// 3) 쓰기 레이어 변경분: 없으면 복원된 컨테이너가 이미지의 초기 파일시스템으로 시작
+	if info.SnapshotKey == "" {
+		return "", fmt.Errorf("container %s has no rootfs snapshot", id)
+	}
+	if err := c.rootfsDiff(ctx, info.Snapshotter, info.SnapshotKey, workDir); err != nil {
+		return "", err
+	}
+
+	// 4) 아카이브
+	if err := tarDirectory(workDir, filepath.Join(CheckpointBasePath, name)); err != nil {
+		return "", fmt.Errorf("failed to archive checkpoint: %w", err)
+	}
+	return name, nil
+}
+
+// rootfsDiff writes the changes of the container's writable layer to rootfs-diff.tar and the paths
+// it removed to deleted.files. containerd's diff service returns an OCI layer; its whiteouts are
+// turned into deleted.files, the layout CRI-O and the containerd CRI plugin restore from.
+func (c *ContainerdCheckpointer) rootfsDiff(ctx context.Context, snapshotter, key, workDir string) error {
+	args := []string{"--address", c.address, "--namespace", c.namespace, "snapshots"}
+	if snapshotter != "" {
+		args = append(args, "--snapshotter", snapshotter)
+	}
+	args = append(args, "diff", "--media-type", "application/vnd.oci.image.layer.v1.tar", key)
+	cmd := exec.CommandContext(ctx, "ctr", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ctr snapshots diff failed: %w", err)
+	}
+	deleted, convErr := convertLayerDiff(stdout, filepath.Join(workDir, "rootfs-diff.tar"))
+	if convErr != nil {
+		// 남은 출력을 비워 ctr가 파이프에서 멈추지 않게 함
+		_, _ = io.Copy(io.Discard, stdout)
+	}
+	if err := cmd.Wait(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("ctr snapshots diff failed: %s", msg)
+	}
+	if convErr != nil {
+		return fmt.Errorf("failed to write rootfs-diff.tar: %w", convErr)
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(deleted)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "deleted.files"), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write deleted.files: %w", err)
+	}
+	return nil
+}
+
+// convertLayerDiff copies an uncompressed OCI layer to dest without its whiteout entries and
+// returns the absolute paths the whiteouts remove. Runtimes remove deleted.files after extracting
+// the diff, so opaque directory markers are dropped: the lower entries they hide reappear on restore.
+func convertLayerDiff(r io.Reader, dest string) ([]string, error) {
+	f, err := os.Create(dest)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(f)
+	var deleted []string
+	copyErr := func() error {
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			name := path.Clean("/" + hdr.Name)
+			dir, base := path.Split(name)
+			switch {
+			case base == ".wh..wh..opq":
+				continue
+			case strings.HasPrefix(base, ".wh."):
+				deleted = append(deleted, path.Join(dir, strings.TrimPrefix(base, ".wh.")))
+				continue
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				return err
+			}
+		}
+	}()
+	if err := tw.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	if err := f.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	if copyErr != nil {
+		os.Remove(dest)
+		return nil, copyErr
+	}
+	return deleted, nil
+}
+
+// ctr runs the containerd CLI against the configured socket and namespace
+func (c *ContainerdCheckpointer) ctr(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "ctr", append([]string{"--address", c.address, "--namespace", c.namespace}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		msg := err.Error()
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			msg = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("ctr %s failed: %s", strings.Join(args[:2], " "), msg)
+	}
+	return out, nil
+}
+
+// tarDirectory writes the regular files and directories under dir to an uncompressed archive at dest
+func tarDirectory(dir, dest string) error {
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(f)
+	walkErr := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		if !fi.Mode().IsRegular() && !fi.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err := tw.Close(); err != nil && walkErr == nil {
+		walkErr = err
+	}
+	if err := f.Close(); err != nil && walkErr == nil {
+		walkErr = err
+	}
+	if walkErr != nil {
+		os.Remove(dest)
+	}
+	return walkErr
+}
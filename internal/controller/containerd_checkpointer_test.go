@@ -0,0 +1,103 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConvertLayerDiff(t *testing.T) {
+	var layer bytes.Buffer
+	tw := tar.NewWriter(&layer)
+	for _, e := range []struct {
+		name string
+		dir  bool
+		body string
+	}{
+		{name: "etc/", dir: true},
+		{name: "etc/app.conf", body: "port=8080"},
+		{name: "etc/.wh.old.conf"},
+		{name: "var/cache/.wh..wh..opq"},
+		{name: "var/cache/index", body: "1"},
+		{name: ".wh.tmp"},
+	} {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Typeflag: tar.TypeReg, Size: int64(len(e.body))}
+		if e.dir {
+			hdr.Typeflag, hdr.Mode = tar.TypeDir, 0o755
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "rootfs-diff.tar")
+	deleted, err := convertLayerDiff(&layer, dest)
+	if err != nil {
+		t.Fatalf("convertLayerDiff: %v", err)
+	}
+	if want := []string{"/etc/old.conf", "/tmp"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("deleted = %v, want %v", deleted, want)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got := map[string]string{}
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[hdr.Name] = string(body)
+	}
+	want := map[string]string{"etc/": "", "etc/app.conf": "port=8080", "var/cache/index": "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rootfs-diff.tar entries = %v, want %v", got, want)
+	}
+}
+
+func TestConvertLayerDiffInvalidLayer(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "rootfs-diff.tar")
+	if _, err := convertLayerDiff(bytes.NewReader([]byte("not a tar archive, but long enough to fill a header block")), dest); err == nil {
+		t.Fatal("expected an error for an invalid layer")
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("partial archive left behind: %v", err)
+	}
+}
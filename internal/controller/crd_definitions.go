@@ -60,8 +60,22 @@ spec:
           spec:
             description: spec defines the desired state of CheckpointBackup
             properties:
+              checkpointTimeout:
+                description: |-
+                  CheckpointTimeout bounds one checkpoint run of the pod: the dump, image build and push of
+                  every container. Defaults to the checkpoint agent's --checkpoint-timeout.
+                type: string
+              concurrencyPolicy:
+                default: Forbid
+                description: ConcurrencyPolicy applies when a scheduled run is due
+                  while the previous one is in progress
+                enum:
+                - Forbid
+                - Replace
+                type: string
               containers:
-                description: Containers specifies the container configurations for checkpoints
+                description: Containers specifies the container configurations for
+                  checkpoints
                 items:
                   description: Container defines a container configuration for checkpoints
                   properties:
@@ -79,6 +93,17 @@ spec:
               podRef:
                 description: PodRef specifies the pod to checkpoint
                 properties:
+                  generateName:
+                    description: |-
+                      GenerateName of the referenced pod, used to match replacement pods
+                      whose names are not stable (e.g. pods of a Deployment's ReplicaSet)
+                    type: string
+                  labels:
+                    additionalProperties:
+                      type: string
+                    description: Labels of the referenced pod at backup time, used
+                      to map restored pods to checkpoints
+                    type: object
                   name:
                     description: Name of the referenced pod
                     type: string
@@ -88,8 +113,17 @@ spec:
                 required:
                 - name
                 type: object
+              priority:
+                description: |-
+                  Priority orders the backup in the node's checkpoint queue when more checkpoints are due
+                  than the agent runs at once: higher values run first. Backups of equal priority are
+                  served in turn across namespaces.
+                format: int32
+                type: integer
               registry:
-                description: Registry specifies the registry configuration for storing checkpoints
+                description: |-
+                  Registry specifies the registry configuration for storing checkpoints
+                  If not provided, images will be built locally without pushing to a registry
                 properties:
                   repository:
                     description: Repository path in the registry
@@ -100,6 +134,9 @@ spec:
                       name:
                         description: Name of the referenced secret
                         type: string
+                      namespace:
+                        description: Namespace of the referenced secret
+                        type: string
                     required:
                     - name
                     type: object
@@ -132,15 +169,281 @@ spec:
                 type: object
               schedule:
                 description: Schedule specifies the backup schedule in cron format
+                  or "immediately" for one-time execution
                 type: string
+              startingDeadlineSeconds:
+                description: |-
+                  StartingDeadlineSeconds is how late a scheduled run may still start, e.g. after the
+                  checkpoint agent was restarted. Older missed runs are skipped. When unset the most
+                  recent missed run is always caught up.
+                format: int64
+                minimum: 0
+                type: integer
+              stopPod:
+                description: |-
+                  StopPod specifies whether to delete the pod after checkpointing (default: false)
+                  When true, the pod will be deleted after successful checkpoint creation and no further schedules will be processed
+                type: boolean
+              suspend:
+                description: |-
+                  Suspend stops new scheduled runs; a run in progress completes. Runs missed while
+                  suspended are caught up on resume within startingDeadlineSeconds.
+                type: boolean
+              timeZone:
+                description: TimeZone of the schedule, an IANA name such as "Asia/Seoul".
+                  Defaults to UTC.
+                type: string
+              triggers:
+                description: |-
+                  Triggers start a checkpoint when an event happens, in addition to the schedule, so the
+                  pod is checkpointed before it is evicted. Runs overlap as set by concurrencyPolicy.
+                items:
+                  description: CheckpointTrigger declares an event that starts a checkpoint
+                    of the pod
+                  properties:
+                    taintKeys:
+                      description: |-
+                        TaintKeys limits NodeTaint to taints with these keys. Any NoSchedule or NoExecute taint
+                        fires the trigger when empty.
+                      items:
+                        type: string
+                      type: array
+                    type:
+                      description: Type of the event
+                      enum:
+                      - NodeTaint
+                      - NodeCordon
+                      - PodAnnotation
+                      - ClusterNotReady
+                      - Eviction
+                      type: string
+                  required:
+                  - type
+                  type: object
+                type: array
+                x-kubernetes-list-map-keys:
+                - type
+                x-kubernetes-list-type: map
             required:
             - podRef
-            - registry
             - resourceRef
             - schedule
             type: object
           status:
             description: status defines the observed state of CheckpointBackup
+            properties:
+              builtImages:
+                description: |-
+                  BuiltImages contains the checkpoint images that were successfully built, oldest first.
+                  Every push is kept with its digest so older checkpoints can still be restored.
+                items:
+                  description: BuiltImage represents a successfully built checkpoint
+                    image
+                  properties:
+                    buildTime:
+                      description: BuildTime is when the image was built
+                      format: date-time
+                      type: string
+                    containerName:
+                      description: ContainerName is the name of the container that
+                        was checkpointed
+                      type: string
+                    digest:
+                      description: |-
+                        Digest is the manifest digest of the pushed image; the tag in ImageName is
+                        overwritten by the next checkpoint, the digest is not
+                      type: string
+                    imageName:
+                      description: ImageName is the full name of the built checkpoint
+                        image
+                      type: string
+                    pushed:
+                      description: Pushed indicates whether the image was pushed to
+                        a registry
+                      type: boolean
+                  required:
+                  - containerName
+                  - imageName
+                  type: object
+                type: array
+              checkpointFiles:
+                description: CheckpointFiles contains the paths to checkpoint files
+                  that have been created
+                items:
+                  description: CheckpointFile represents a checkpoint file that has
+                    been created
+                  properties:
+                    checkpointTime:
+                      description: CheckpointTime is when the checkpoint was created
+                      format: date-time
+                      type: string
+                    containerName:
+                      description: ContainerName is the name of the container that
+                        was checkpointed
+                      type: string
+                    filePath:
+                      description: FilePath is the relative path to the checkpoint
+                        file
+                      type: string
+                  required:
+                  - containerName
+                  - filePath
+                  type: object
+                type: array
+              conditions:
+                description: Conditions represent the latest available observations
+                  of the CheckpointBackup's current state
+                items:
+                  description: Condition contains details for one aspect of the current
+                    state of this API Resource.
+                  properties:
+                    lastTransitionTime:
+                      description: |-
+                        lastTransitionTime is the last time the condition transitioned from one status to another.
+                        This should be when the underlying condition changed.  If that is not known, then using the time when the API field changed is acceptable.
+                      format: date-time
+                      type: string
+                    message:
+                      description: |-
+                        message is a human readable message indicating details about the transition.
+                        This may be an empty string.
+                      maxLength: 32768
+                      type: string
+                    observedGeneration:
+                      description: |-
+                        observedGeneration represents the .metadata.generation that the condition was set based upon.
+                        For instance, if .metadata.generation is currently 12, but the .status.conditions[x].observedGeneration is 9, the condition is out of date
+                        with respect to the current state of the instance.
+                      format: int64
+                      minimum: 0
+                      type: integer
+                    reason:
+                      description: |-
+                        reason contains a programmatic identifier indicating the reason for the condition's last transition.
+                        Producers of specific condition types may define expected values and meanings for this field,
+                        and whether the values are considered a guaranteed API.
+                        The value should be a CamelCase string.
+                        This field may not be empty.
+                      maxLength: 1024
+                      minLength: 1
+                      pattern: ^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$
+                      type: string
+                    status:
+                      description: status of the condition, one of True, False, Unknown.
+                      enum:
+                      - "True"
+                      - "False"
+                      - Unknown
+                      type: string
+                    type:
+                      description: type of condition in CamelCase or in foo.example.com/CamelCase.
+                      maxLength: 316
+                      pattern: ^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])$
+                      type: string
+                  required:
+                  - lastTransitionTime
+                  - message
+                  - reason
+                  - status
+                  - type
+                  type: object
+                type: array
+              lastCheckpointTime:
+                description: LastCheckpointTime represents the last time a checkpoint
+                  was successfully created
+                format: date-time
+                type: string
+              lastScheduleTime:
+                description: LastScheduleTime is the scheduled time of the last run
+                  the agent started
+                format: date-time
+                type: string
+              message:
+                description: Message provides additional information about the current
+                  state
+                type: string
+              nextScheduleTime:
+                description: NextScheduleTime is when the next scheduled run is due
+                format: date-time
+                type: string
+              observedGeneration:
+                description: ObservedGeneration reflects the generation of the most
+                  recently observed CheckpointBackup
+                format: int64
+                type: integer
+              phase:
+                description: Phase represents the current phase of the checkpoint
+                  backup operation
+                type: string
+              progress:
+                description: Progress reports the checkpoint run in progress, or the
+                  last one
+                properties:
+                  completedContainers:
+                    description: CompletedContainers is the number of containers checkpointed
+                      so far
+                    format: int32
+                    type: integer
+                  completionTime:
+                    description: CompletionTime is when the run ended, successfully
+                      or not
+                    format: date-time
+                    type: string
+                  currentContainer:
+                    description: CurrentContainer is the container being checkpointed
+                    type: string
+                  deadline:
+                    description: |-
+                      Deadline is when the run is cancelled if it has not completed; it moves later by the
+                      time the run waits for the node's worker pool
+                    format: date-time
+                    type: string
+                  queued:
+                    description: |-
+                      Queued is the step the run is waiting for a free slot of the node's worker pool for:
+                      Checkpoint, Build or Push
+                    type: string
+                  startTime:
+                    description: StartTime is when the run started
+                    format: date-time
+                    type: string
+                  totalContainers:
+                    description: TotalContainers is the number of containers the run
+                      checkpoints
+                    format: int32
+                    type: integer
+                type: object
+              triggers:
+                description: Triggers records the event each trigger last started
+                  a checkpoint for
+                items:
+                  description: CheckpointTriggerStatus records the event that last
+                    fired a trigger
+                  properties:
+                    event:
+                      description: |-
+                        Event identifies the event, e.g. the matching taints of the node or the annotation value.
+                        The trigger fires again when it changes.
+                      type: string
+                    time:
+                      description: Time is when the checkpoint for the event was started
+                      format: date-time
+                      type: string
+                    type:
+                      description: Type of the trigger
+                      enum:
+                      - NodeTaint
+                      - NodeCordon
+                      - PodAnnotation
+                      - ClusterNotReady
+                      - Eviction
+                      type: string
+                  required:
+                  - event
+                  - time
+                  - type
+                  type: object
+                type: array
             type: object
         required:
         - spec
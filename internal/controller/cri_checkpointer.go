@@ -0,0 +1,82 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// CRICheckpointer calls the CRI CheckpointContainer RPC on the container runtime socket,
+// bypassing the kubelet ContainerCheckpoint API
+type CRICheckpointer struct {
+	endpoint string
+	client   runtimeapi.RuntimeServiceClient
+}
+
+// NewCRICheckpointer connects to the runtime endpoint, e.g. unix:///var/run/crio/crio.sock.
+// The connection is established on the first call.
+func NewCRICheckpointer(endpoint string) (*CRICheckpointer, error) {
+	if endpoint == "" {
+		endpoint = DefaultCRIRuntimeEndpoint
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "unix://" + endpoint
+	}
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to CRI endpoint %s: %w", endpoint, err)
+	}
+	return &CRICheckpointer{
+		endpoint: endpoint,
+		client:   runtimeapi.NewRuntimeServiceClient(conn),
+	}, nil
+}
+
+// Backend implements Checkpointer
+func (c *CRICheckpointer) Backend() string {
+	return CheckpointBackendCRI
+}
+
+// Checkpoint exports the checkpoint archive of the container into CheckpointBasePath. The
+// agent mounts that directory at the same path as the host, so the runtime writes it in place.
+func (c *CRICheckpointer) Checkpoint(ctx context.Context, pod *corev1.Pod, containerName string) (string, error) {
+	id, err := containerID(pod, containerName)
+	if err != nil {
+		return "", err
+	}
+	name := checkpointArchiveName(pod, containerName)
+	req := &runtimeapi.CheckpointContainerRequest{
+		ContainerId: id,
+		Location:    filepath.Join(CheckpointBasePath, name),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Timeout = int64(time.Until(deadline).Seconds())
+	}
+	if _, err := c.client.CheckpointContainer(ctx, req); err != nil {
+		return "", fmt.Errorf("CRI CheckpointContainer on %s failed: %w", c.endpoint, err)
+	}
+	return name, nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+const (
+	// LabelCheckpointGeneration marks one-shot CheckpointBackups that belong to a final checkpoint
+	// generation. Scheduled backups carry no generation.
+	LabelCheckpointGeneration = "migration.dcnlab.com/checkpoint-generation"
+
+	// AnnoRestoreGeneration on a ResourceBinding tells the restore controller which generation to restore
+	AnnoRestoreGeneration = "migration.dcnlab.com/restore-generation"
+
+	// CheckpointGenerationLatest restores the newest pushed checkpoint of each pod, of any generation
+	CheckpointGenerationLatest = "latest"
+)
+
+// createFinalCheckpoints copies the StatefulMigration's scheduled CheckpointBackups on clusters into
+// one-shot backups named <prefix>-<pod>-<cluster> that carry extraLabels. They are kept out of the
+// StatefulMigration's label so the backup controller never prunes them.
+func createFinalCheckpoints(ctx context.Context, kc *KarmadaClient, sm *migrationv1.StatefulMigration, clusters []string,
+	prefix string, extraLabels map[string]string, stopPod bool) ([]migrationv1.MigrationRunCheckpoint, error) {
+	var backupList migrationv1.CheckpointBackupList
+	if err := kc.List(ctx, &backupList, &client.ListOptions{
+		Namespace:     sm.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{"stateful-migration": sm.Name}),
+	}); err != nil {
+		return nil, fmt.Errorf("list CheckpointBackups on Karmada: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(clusters))
+	for _, c := range clusters {
+		wanted[c] = true
+	}
+	var out []migrationv1.MigrationRunCheckpoint
+	for _, src := range backupList.Items {
+		cluster, podName := src.Labels["target-cluster"], src.Labels["target-pod"]
+		if !wanted[cluster] || podName == "" {
+			continue
+		}
+		final := &migrationv1.CheckpointBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s-%s", prefix, podName, cluster),
+				Namespace: sm.Namespace,
+				Labels: map[string]string{
+					"target-cluster": cluster,
+					"target-pod":     podName,
+				},
+			},
+			Spec: *src.Spec.DeepCopy(),
+		}
+		if node := src.Labels[LabelTargetNode]; node != "" {
+			final.Labels[LabelTargetNode] = node
+		}
+		for k, v := range extraLabels {
+			final.Labels[k] = v
+		}
+		final.Spec.Schedule = "immediately"
+		// 마이그레이션 체크포인트는 스케줄 일시정지와 무관하게 실행
+		final.Spec.Suspend = nil
+		final.Spec.StopPod = &stopPod
+		setOwnerMetadata(final, sm.Namespace, sm.Name, sm.UID)
+
+		if err := kc.Create(ctx, final); err != nil && !apierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("create final CheckpointBackup %s: %w", final.Name, err)
+		}
+		policy := &karmadav1alpha1.PropagationPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-policy", final.Name),
+				Namespace: final.Namespace,
+			},
+			Spec: karmadav1alpha1.PropagationSpec{
+				ResourceSelectors: []karmadav1alpha1.ResourceSelector{{
+					APIVersion: migrationv1.GroupVersion.String(),
+					Kind:       "CheckpointBackup",
+					Name:       final.Name,
+				}},
+				Placement: karmadav1alpha1.Placement{
+					ClusterAffinity: &karmadav1alpha1.ClusterAffinity{ClusterNames: []string{cluster}},
+				},
+			},
+		}
+		setOwnerMetadata(policy, sm.Namespace, sm.Name, sm.UID)
+		if err := kc.CreateOrUpdatePropagationPolicy(ctx, policy); err != nil {
+			return nil, fmt.Errorf("propagate final CheckpointBackup %s: %w", final.Name, err)
+		}
+		out = append(out, migrationv1.MigrationRunCheckpoint{Name: final.Name, Cluster: cluster, PodName: podName})
+	}
+	return out, nil
+}
+
+// finalCheckpointProgress reads the phase of each final checkpoint from its member cluster, since
+// status is not aggregated on Karmada, and returns how many are pushed. A failed checkpoint is
+// returned as an error.
+func finalCheckpointProgress(ctx context.Context, mc *MemberClusterClient, namespace string, checkpoints []migrationv1.MigrationRunCheckpoint) (int, error) {
+	done := 0
+	for i := range checkpoints {
+		cp := &checkpoints[i]
+		obj, err := mc.GetResourceFromCluster(ctx, cp.Cluster, migrationv1.GroupVersion.String(), "CheckpointBackup", namespace, cp.Name)
+		if err != nil {
+			// 아직 전파되지 않았거나 일시적 오류: 다음 확인에서 재시도
+			if !apierrors.IsNotFound(err) {
+				log.FromContext(ctx).Info("Cannot read final checkpoint", "backup", cp.Name, "cluster", cp.Cluster, "error", err.Error())
+			}
+			continue
+		}
+		cp.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+		switch cp.Phase {
+		case PhaseCompleted, PhaseCompletedPodDeleted:
+			done++
+		case PhaseFailed, PhaseCompletedWithError:
+			msg, _, _ := unstructured.NestedString(obj.Object, "status", "message")
+			return done, fmt.Errorf("final checkpoint %s on %s: %s %s", cp.Name, cp.Cluster, cp.Phase, msg)
+		}
+	}
+	return done, nil
+}
+
+// deleteFinalCheckpoints deletes the final CheckpointBackups matching selector and their PropagationPolicies
+func deleteFinalCheckpoints(ctx context.Context, kc *KarmadaClient, namespace string, selector map[string]string) error {
+	var backupList migrationv1.CheckpointBackupList
+	if err := kc.List(ctx, &backupList, &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: labels.SelectorFromSet(selector),
+	}); err != nil {
+		return fmt.Errorf("list final CheckpointBackups: %w", err)
+	}
+	for i := range backupList.Items {
+		backup := &backupList.Items[i]
+		if err := kc.Delete(ctx, backup); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("delete final CheckpointBackup %s: %w", backup.Name, err)
+		}
+		if err := kc.DeletePropagationPolicy(ctx, &karmadav1alpha1.PropagationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-policy", backup.Name), Namespace: backup.Namespace},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backupsForGeneration keeps the final checkpoints of generation
+func backupsForGeneration(backups []unstructured.Unstructured, generation string) []unstructured.Unstructured {
+	out := make([]unstructured.Unstructured, 0, len(backups))
+	for i := range backups {
+		if backups[i].GetLabels()[LabelCheckpointGeneration] == generation {
+			out = append(out, backups[i])
+		}
+	}
+	return out
+}
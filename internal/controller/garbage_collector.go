@@ -0,0 +1,320 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apischema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// StatefulMigrations live in the management cluster while the objects they create live on
+// Karmada and on member clusters, so Kubernetes owner references cannot be used. Ownership is
+// tracked with the labels and annotation below instead, and MigrationGarbageCollector removes
+// objects whose owner no longer exists.
+const (
+	// LabelOwnerName is the name of the StatefulMigration that created the object
+	LabelOwnerName = "migration.dcnlab.com/owner-name"
+
+	// LabelOwnerNamespace is the namespace of the StatefulMigration that created the object
+	LabelOwnerNamespace = "migration.dcnlab.com/owner-namespace"
+
+	// AnnoOwnerUID is the UID of the StatefulMigration that created the object
+	AnnoOwnerUID = "migration.dcnlab.com/owner-uid"
+
+	// DefaultGarbageCollectInterval is how often orphaned objects are collected
+	DefaultGarbageCollectInterval = 10 * time.Minute
+
+	restorePolicySuffix = "-restore-policy"
+)
+
+// setOwnerMetadata stamps the owner tracking labels and annotation on obj
+func setOwnerMetadata(obj metav1.Object, ownerNamespace, ownerName string, ownerUID types.UID) {
+	lbls := obj.GetLabels()
+	if lbls == nil {
+		lbls = map[string]string{}
+	}
+	lbls[LabelOwnerName] = ownerName
+	lbls[LabelOwnerNamespace] = ownerNamespace
+	obj.SetLabels(lbls)
+
+	if ownerUID != "" {
+		ann := obj.GetAnnotations()
+		if ann == nil {
+			ann = map[string]string{}
+		}
+		ann[AnnoOwnerUID] = string(ownerUID)
+		obj.SetAnnotations(ann)
+	}
+}
+
+// ownerOf returns the StatefulMigration that owns obj. Objects created before owner tracking
+// was introduced are matched through their legacy labels.
+func ownerOf(obj metav1.Object) (types.NamespacedName, types.UID, bool) {
+	lbls := obj.GetLabels()
+	if name := lbls[LabelOwnerName]; name != "" {
+		ns := lbls[LabelOwnerNamespace]
+		if ns == "" {
+			ns = obj.GetNamespace()
+		}
+		return types.NamespacedName{Namespace: ns, Name: name}, types.UID(obj.GetAnnotations()[AnnoOwnerUID]), true
+	}
+	if name := lbls["stateful-migration"]; name != "" {
+		return types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}, "", true
+	}
+	if name := lbls[LabelKeySM]; name != "" {
+		return types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}, "", true
+	}
+	return types.NamespacedName{}, "", false
+}
+
+// isOrphanOf reports whether obj belongs to a StatefulMigration missing from owners, or to an
+// earlier StatefulMigration of the same name. Objects without an owner are never orphans.
+func isOrphanOf(owners map[types.NamespacedName]types.UID, obj metav1.Object) bool {
+	key, uid, ok := ownerOf(obj)
+	if !ok {
+		return false
+	}
+	current, exists := owners[key]
+	return !exists || (uid != "" && uid != current)
+}
+
+// MigrationGarbageCollector periodically deletes CheckpointBackups, CheckpointRestores and
+// PropagationPolicies on Karmada and member clusters whose StatefulMigration is gone.
+type MigrationGarbageCollector struct {
+	// Management cluster client used to look up StatefulMigrations
+	client.Client
+
+	KarmadaClient       *KarmadaClient
+	MemberClusterClient *MemberClusterClient
+
+	// Interval between collections (default: DefaultGarbageCollectInterval)
+	Interval time.Duration
+}
+
+// NeedLeaderElection makes sure only the leader deletes orphans
+func (g *MigrationGarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the collector until ctx is cancelled
+func (g *MigrationGarbageCollector) Start(ctx context.Context) error {
+	lg := ctrl.Log.WithName("migration-gc")
+	interval := g.Interval
+	if interval <= 0 {
+		interval = DefaultGarbageCollectInterval
+	}
+
+	for {
+		if !sleepWithJitterCtx(ctx, interval, 0.2) {
+			return nil
+		}
+		if err := g.initClients(); err != nil {
+			lg.V(4).Info("Karmada client not ready", "error", err.Error())
+			continue
+		}
+		if err := g.collect(ctx); err != nil {
+			lg.Error(err, "garbage collection failed")
+		}
+	}
+}
+
+func (g *MigrationGarbageCollector) initClients() error {
+	if g.KarmadaClient == nil {
+		karmadaClient, err := NewKarmadaClient()
+		if err != nil {
+			return err
+		}
+		g.KarmadaClient = karmadaClient
+	}
+	if g.MemberClusterClient == nil {
+		memberClient, err := NewMemberClusterClient(g.KarmadaClient)
+		if err != nil {
+			return err
+		}
+		g.MemberClusterClient = memberClient
+	}
+	return nil
+}
+
+// collect runs a single garbage collection pass
+func (g *MigrationGarbageCollector) collect(ctx context.Context) error {
+	lg := ctrl.Log.WithName("migration-gc")
+
+	var smList migrationv1.StatefulMigrationList
+	if err := g.List(ctx, &smList); err != nil {
+		return fmt.Errorf("list StatefulMigrations: %w", err)
+	}
+	owners := make(map[types.NamespacedName]types.UID, len(smList.Items))
+	ownerNamespaces := map[string]bool{}
+	for _, sm := range smList.Items {
+		owners[client.ObjectKeyFromObject(&sm)] = sm.UID
+		ownerNamespaces[sm.Namespace] = true
+	}
+	isOrphan := func(obj metav1.Object) bool {
+		return isOrphanOf(owners, obj)
+	}
+
+	// 1) Karmada: CheckpointBackups (and their per-backup PropagationPolicies)
+	var backups migrationv1.CheckpointBackupList
+	if err := g.KarmadaClient.List(ctx, &backups); err != nil {
+		return fmt.Errorf("list CheckpointBackups on Karmada: %w", err)
+	}
+	liveBackups := map[types.NamespacedName]bool{}
+	for i := range backups.Items {
+		backup := &backups.Items[i]
+		if !isOrphan(backup) {
+			liveBackups[client.ObjectKeyFromObject(backup)] = true
+			continue
+		}
+		lg.Info("Deleting orphaned CheckpointBackup on Karmada", "backup", client.ObjectKeyFromObject(backup))
+		if err := client.IgnoreNotFound(g.KarmadaClient.Delete(ctx, backup)); err != nil {
+			return fmt.Errorf("delete CheckpointBackup %s/%s: %w", backup.Namespace, backup.Name, err)
+		}
+	}
+
+	// 2) Karmada: CheckpointRestores
+	var restores migrationv1.CheckpointRestoreList
+	if err := g.KarmadaClient.List(ctx, &restores); err != nil {
+		return fmt.Errorf("list CheckpointRestores on Karmada: %w", err)
+	}
+	for i := range restores.Items {
+		restore := &restores.Items[i]
+		if !isOrphan(restore) {
+			continue
+		}
+		lg.Info("Deleting orphaned CheckpointRestore on Karmada", "restore", client.ObjectKeyFromObject(restore))
+		if err := client.IgnoreNotFound(g.KarmadaClient.Delete(ctx, restore)); err != nil {
+			return fmt.Errorf("delete CheckpointRestore %s/%s: %w", restore.Namespace, restore.Name, err)
+		}
+	}
+
+	// 3) Karmada: PropagationPolicies created by the operator
+	var policies karmadav1alpha1.PropagationPolicyList
+	if err := g.KarmadaClient.List(ctx, &policies); err != nil {
+		return fmt.Errorf("list PropagationPolicies on Karmada: %w", err)
+	}
+	for i := range policies.Items {
+		pol := &policies.Items[i]
+		orphan := false
+		switch {
+		case pol.Labels[LabelOwnerName] != "":
+			orphan = isOrphan(pol)
+		case pol.Labels["created-by"] == "stateful-migration-operator" && pol.Labels["resource-type"] == "namespace":
+			// Namespace policies are shared by every StatefulMigration of the namespace
+			orphan = !ownerNamespaces[pol.Namespace]
+		case strings.HasSuffix(pol.Name, restorePolicySuffix) && selectsOnlyKind(pol, "CheckpointRestore"):
+			_, exists := owners[types.NamespacedName{Namespace: pol.Namespace, Name: strings.TrimSuffix(pol.Name, restorePolicySuffix)}]
+			orphan = !exists
+		case selectsOnlyKind(pol, "CheckpointBackup"):
+			// Per-backup policy: orphaned once every selected backup is gone
+			orphan = true
+			for _, rs := range pol.Spec.ResourceSelectors {
+				ns := rs.Namespace
+				if ns == "" {
+					ns = pol.Namespace
+				}
+				if rs.Name == "" || liveBackups[types.NamespacedName{Namespace: ns, Name: rs.Name}] {
+					orphan = false
+				}
+			}
+		}
+		if !orphan {
+			continue
+		}
+		lg.Info("Deleting orphaned PropagationPolicy on Karmada", "policy", client.ObjectKeyFromObject(pol))
+		if err := g.KarmadaClient.DeletePropagationPolicy(ctx, pol); err != nil {
+			return fmt.Errorf("delete PropagationPolicy %s/%s: %w", pol.Namespace, pol.Name, err)
+		}
+	}
+
+	// 4) Member clusters: copies left behind after the Karmada object is gone
+	clusters, err := g.listMemberClusters(ctx)
+	if err != nil {
+		return err
+	}
+	for _, cluster := range clusters {
+		for _, kind := range []string{"CheckpointBackup", "CheckpointRestore"} {
+			if err := g.collectOnMember(ctx, cluster, kind, isOrphan); err != nil {
+				// An unreachable cluster must not block the others
+				lg.Info("Skipping member cluster garbage collection", "cluster", cluster, "kind", kind, "error", err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+func (g *MigrationGarbageCollector) collectOnMember(ctx context.Context, cluster, kind string, isOrphan func(metav1.Object) bool) error {
+	lg := ctrl.Log.WithName("migration-gc")
+	list, err := g.MemberClusterClient.ListResourcesFromCluster(ctx, cluster, migrationv1.GroupVersion.String(), kind, "", "")
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if !isOrphan(obj) {
+			continue
+		}
+		lg.Info("Deleting orphaned object on member cluster", "cluster", cluster, "kind", kind, "object", client.ObjectKeyFromObject(obj))
+		if err := g.MemberClusterClient.DeleteResourceFromCluster(ctx, cluster, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listMemberClusters returns the names of the clusters registered with Karmada
+func (g *MigrationGarbageCollector) listMemberClusters(ctx context.Context) ([]string, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(apischema.GroupVersionKind{
+		Group:   "cluster.karmada.io",
+		Version: "v1alpha1",
+		Kind:    "ClusterList",
+	})
+	if err := g.KarmadaClient.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("list Karmada clusters: %w", err)
+	}
+	out := make([]string, 0, len(list.Items))
+	for _, it := range list.Items {
+		out = append(out, it.GetName())
+	}
+	return out, nil
+}
+
+// selectsOnlyKind reports whether every resource selector of the policy targets the given migration kind
+func selectsOnlyKind(pol *karmadav1alpha1.PropagationPolicy, kind string) bool {
+	if len(pol.Spec.ResourceSelectors) == 0 {
+		return false
+	}
+	for _, rs := range pol.Spec.ResourceSelectors {
+		if rs.APIVersion != migrationv1.GroupVersion.String() || rs.Kind != kind {
+			return false
+		}
+	}
+	return true
+}
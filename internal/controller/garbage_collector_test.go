@@ -0,0 +1,294 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// ownedMeta returns object metadata in ns with the labels and owner UID annotation
+func ownedMeta(ns, name string, labels map[string]string, uid string) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{Namespace: ns, Name: name, Labels: labels}
+	if uid != "" {
+		meta.Annotations = map[string]string{AnnoOwnerUID: uid}
+	}
+	return meta
+}
+
+func TestOwnerOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    metav1.ObjectMeta
+		want    types.NamespacedName
+		wantUID types.UID
+		wantOK  bool
+	}{
+		{
+			name:    "owner labels",
+			meta:    ownedMeta("app", "b", map[string]string{LabelOwnerName: "db", LabelOwnerNamespace: "ops"}, "uid-1"),
+			want:    types.NamespacedName{Namespace: "ops", Name: "db"},
+			wantUID: "uid-1",
+			wantOK:  true,
+		},
+		{
+			name:   "owner namespace defaults to the object's",
+			meta:   ownedMeta("app", "b", map[string]string{LabelOwnerName: "db"}, ""),
+			want:   types.NamespacedName{Namespace: "app", Name: "db"},
+			wantOK: true,
+		},
+		{
+			name:   "owner labels win over legacy labels",
+			meta:   ownedMeta("app", "b", map[string]string{LabelOwnerName: "db", "stateful-migration": "old", LabelKeySM: "older"}, ""),
+			want:   types.NamespacedName{Namespace: "app", Name: "db"},
+			wantOK: true,
+		},
+		{
+			name:   "legacy backup label",
+			meta:   ownedMeta("app", "b", map[string]string{"stateful-migration": "db"}, "uid-ignored"),
+			want:   types.NamespacedName{Namespace: "app", Name: "db"},
+			wantOK: true,
+		},
+		{
+			name:   "legacy restore label",
+			meta:   ownedMeta("app", "r", map[string]string{LabelKeySM: "db"}, ""),
+			want:   types.NamespacedName{Namespace: "app", Name: "db"},
+			wantOK: true,
+		},
+		{name: "no owner", meta: ownedMeta("app", "x", map[string]string{"app": "db"}, "")},
+		{name: "no labels", meta: ownedMeta("app", "x", nil, "uid-1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, uid, ok := ownerOf(&tt.meta)
+			if key != tt.want || uid != tt.wantUID || ok != tt.wantOK {
+				t.Errorf("ownerOf = (%v, %q, %v), want (%v, %q, %v)", key, uid, ok, tt.want, tt.wantUID, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIsOrphanOf(t *testing.T) {
+	owners := map[types.NamespacedName]types.UID{
+		{Namespace: "app", Name: "db"}:  "uid-1",
+		{Namespace: "ops", Name: "web"}: "uid-2",
+	}
+	owned := func(name string, uid string) metav1.ObjectMeta {
+		return ownedMeta("app", "obj", map[string]string{LabelOwnerName: name}, uid)
+	}
+	tests := []struct {
+		name string
+		meta metav1.ObjectMeta
+		want bool
+	}{
+		{name: "owner exists with the same UID", meta: owned("db", "uid-1")},
+		{name: "owner recreated with another UID", meta: owned("db", "uid-0"), want: true},
+		{name: "owner exists, no UID recorded", meta: owned("db", "")},
+		{name: "owner gone", meta: owned("cache", "uid-3"), want: true},
+		{name: "owner in another namespace", meta: ownedMeta("app", "obj", map[string]string{LabelOwnerName: "web", LabelOwnerNamespace: "ops"}, "uid-2")},
+		{name: "owner name exists only in another namespace", meta: owned("web", ""), want: true},
+		{name: "legacy label of a live owner", meta: ownedMeta("app", "obj", map[string]string{"stateful-migration": "db"}, "")},
+		{name: "legacy label of a gone owner", meta: ownedMeta("app", "obj", map[string]string{"stateful-migration": "cache"}, ""), want: true},
+		{name: "legacy label ignores the UID", meta: ownedMeta("app", "obj", map[string]string{"stateful-migration": "db"}, "uid-0")},
+		{name: "legacy restore label of a gone owner", meta: ownedMeta("app", "obj", map[string]string{LabelKeySM: "cache"}, ""), want: true},
+		{name: "object without an owner", meta: ownedMeta("app", "obj", map[string]string{"app": "cache"}, "uid-0")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOrphanOf(owners, &tt.meta); got != tt.want {
+				t.Errorf("isOrphanOf = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectsOnlyKind(t *testing.T) {
+	sel := func(apiVersion, kind string) karmadav1alpha1.ResourceSelector {
+		return karmadav1alpha1.ResourceSelector{APIVersion: apiVersion, Kind: kind, Name: "x"}
+	}
+	gv := migrationv1.GroupVersion.String()
+	tests := []struct {
+		name      string
+		selectors []karmadav1alpha1.ResourceSelector
+		want      bool
+	}{
+		{name: "no selectors"},
+		{name: "one backup", selectors: []karmadav1alpha1.ResourceSelector{sel(gv, "CheckpointBackup")}, want: true},
+		{name: "two backups", selectors: []karmadav1alpha1.ResourceSelector{sel(gv, "CheckpointBackup"), sel(gv, "CheckpointBackup")}, want: true},
+		{name: "backup and restore", selectors: []karmadav1alpha1.ResourceSelector{sel(gv, "CheckpointBackup"), sel(gv, "CheckpointRestore")}},
+		{name: "backup and workload", selectors: []karmadav1alpha1.ResourceSelector{sel(gv, "CheckpointBackup"), sel("apps/v1", "StatefulSet")}},
+		{name: "same kind in another group", selectors: []karmadav1alpha1.ResourceSelector{sel("example.com/v1", "CheckpointBackup")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pol := &karmadav1alpha1.PropagationPolicy{Spec: karmadav1alpha1.PropagationSpec{ResourceSelectors: tt.selectors}}
+			if got := selectsOnlyKind(pol, "CheckpointBackup"); got != tt.want {
+				t.Errorf("selectsOnlyKind = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGarbageCollectorCollect(t *testing.T) {
+	gv := migrationv1.GroupVersion.String()
+	sm := &migrationv1.StatefulMigration{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "app", UID: "uid-1"}}
+	ownedBy := func(name string) map[string]string { return map[string]string{LabelOwnerName: name} }
+	backup := func(name string, labels map[string]string, uid string) *migrationv1.CheckpointBackup {
+		return &migrationv1.CheckpointBackup{ObjectMeta: ownedMeta("app", name, labels, uid)}
+	}
+	restore := func(name string, labels map[string]string, uid string) *migrationv1.CheckpointRestore {
+		return &migrationv1.CheckpointRestore{ObjectMeta: ownedMeta("app", name, labels, uid)}
+	}
+	policy := func(ns, name string, labels map[string]string, selectors ...karmadav1alpha1.ResourceSelector) *karmadav1alpha1.PropagationPolicy {
+		return &karmadav1alpha1.PropagationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: labels},
+			Spec:       karmadav1alpha1.PropagationSpec{ResourceSelectors: selectors},
+		}
+	}
+	selects := func(kind, name string) karmadav1alpha1.ResourceSelector {
+		return karmadav1alpha1.ResourceSelector{APIVersion: gv, Kind: kind, Name: name}
+	}
+	namespacePolicy := map[string]string{"created-by": "stateful-migration-operator", "resource-type": "namespace"}
+
+	objs := []client.Object{
+		sm,
+		&clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member1"}},
+		&clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member2"}},
+
+		backup("db-0", ownedBy("db"), "uid-1"),
+		backup("legacy-0", map[string]string{"stateful-migration": "db"}, ""),
+		backup("stale-0", ownedBy("db"), "uid-0"),
+		backup("gone-0", ownedBy("cache"), "uid-3"),
+		backup("manual-0", nil, ""),
+		restore("db-0-restore", ownedBy("db"), "uid-1"),
+		restore("legacy-restore", map[string]string{LabelKeySM: "cache"}, ""),
+
+		policy("app", "db-0-policy", ownedBy("db"), selects("CheckpointBackup", "db-0")),
+		policy("app", "gone-owner-policy", ownedBy("cache"), selects("CheckpointBackup", "db-0")),
+		policy("app", "app-namespace", namespacePolicy),
+		policy("ops", "ops-namespace", namespacePolicy),
+		policy("app", "db"+restorePolicySuffix, nil, selects("CheckpointRestore", "")),
+		policy("app", "cache"+restorePolicySuffix, nil, selects("CheckpointRestore", "")),
+		policy("app", "mixed"+restorePolicySuffix, nil, selects("CheckpointRestore", ""), selects("CheckpointBackup", "db-0")),
+		policy("app", "legacy-0-policy", nil, selects("CheckpointBackup", "legacy-0")),
+		policy("app", "gone-0-policy", nil, selects("CheckpointBackup", "gone-0")),
+		policy("app", "missing-policy", nil, selects("CheckpointBackup", "never-existed")),
+		policy("app", "partly-live-policy", nil, selects("CheckpointBackup", "gone-0"), selects("CheckpointBackup", "db-0")),
+		policy("app", "any-backup-policy", nil, selects("CheckpointBackup", "")),
+		policy("app", "user-policy", nil, karmadav1alpha1.ResourceSelector{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "web"}),
+	}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(objs...).Build()
+
+	proxy := newFakeMemberProxy(t)
+	member := func(kind, name string, labels map[string]string, uid string) {
+		obj := newMemberObject(gv, kind, "app", name, nil)
+		meta := ownedMeta("app", name, labels, uid)
+		obj.SetLabels(meta.Labels)
+		obj.SetAnnotations(meta.Annotations)
+		proxy.add("member1", obj)
+	}
+	member("CheckpointBackup", "db-0", ownedBy("db"), "uid-1")
+	member("CheckpointBackup", "gone-0", ownedBy("cache"), "uid-3")
+	member("CheckpointRestore", "db-0-restore", ownedBy("db"), "uid-1")
+	member("CheckpointRestore", "stale-restore", ownedBy("db"), "uid-0")
+	// 응답하지 않는 클러스터는 건너뛰고 나머지는 정리
+	proxy.setDown("member2", true)
+
+	g := &MigrationGarbageCollector{
+		Client:              c,
+		KarmadaClient:       newTestKarmadaClient(t, c, proxy),
+		MemberClusterClient: newTestMemberClusterClient(t, c, proxy),
+	}
+	ctx := context.Background()
+	if err := g.collect(ctx); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	exists := func(obj client.Object) bool {
+		err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if err != nil && !apierrors.IsNotFound(err) {
+			t.Fatal(err)
+		}
+		return err == nil
+	}
+	karmada := []struct {
+		obj  client.Object
+		kept bool
+	}{
+		{obj: &migrationv1.CheckpointBackup{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "db-0"}}, kept: true},
+		{obj: &migrationv1.CheckpointBackup{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "legacy-0"}}, kept: true},
+		{obj: &migrationv1.CheckpointBackup{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "stale-0"}}},
+		{obj: &migrationv1.CheckpointBackup{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "gone-0"}}},
+		{obj: &migrationv1.CheckpointBackup{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "manual-0"}}, kept: true},
+		{obj: &migrationv1.CheckpointRestore{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "db-0-restore"}}, kept: true},
+		{obj: &migrationv1.CheckpointRestore{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "legacy-restore"}}},
+	}
+	policies := []struct {
+		ns, name string
+		kept     bool
+	}{
+		{ns: "app", name: "db-0-policy", kept: true},
+		{ns: "app", name: "gone-owner-policy"},
+		{ns: "app", name: "app-namespace", kept: true},
+		{ns: "ops", name: "ops-namespace"},
+		{ns: "app", name: "db" + restorePolicySuffix, kept: true},
+		{ns: "app", name: "cache" + restorePolicySuffix},
+		{ns: "app", name: "mixed" + restorePolicySuffix, kept: true},
+		{ns: "app", name: "legacy-0-policy", kept: true},
+		{ns: "app", name: "gone-0-policy"},
+		{ns: "app", name: "missing-policy"},
+		{ns: "app", name: "partly-live-policy", kept: true},
+		{ns: "app", name: "any-backup-policy", kept: true},
+		{ns: "app", name: "user-policy", kept: true},
+	}
+	for _, tt := range karmada {
+		if got := exists(tt.obj); got != tt.kept {
+			t.Errorf("%T %s kept = %v, want %v", tt.obj, tt.obj.GetName(), got, tt.kept)
+		}
+	}
+	for _, tt := range policies {
+		pol := &karmadav1alpha1.PropagationPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: tt.ns, Name: tt.name}}
+		if got := exists(pol); got != tt.kept {
+			t.Errorf("PropagationPolicy %s/%s kept = %v, want %v", tt.ns, tt.name, got, tt.kept)
+		}
+	}
+
+	members := []struct {
+		kind, name string
+		kept       bool
+	}{
+		{kind: "CheckpointBackup", name: "db-0", kept: true},
+		{kind: "CheckpointBackup", name: "gone-0"},
+		{kind: "CheckpointRestore", name: "db-0-restore", kept: true},
+		{kind: "CheckpointRestore", name: "stale-restore"},
+	}
+	for _, tt := range members {
+		if got := proxy.get("member1", gv, tt.kind, "app", tt.name) != nil; got != tt.kept {
+			t.Errorf("member1 %s %s kept = %v, want %v", tt.kind, tt.name, got, tt.kept)
+		}
+	}
+}
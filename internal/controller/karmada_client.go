@@ -44,6 +44,7 @@ const (
 type KarmadaClient struct {
 	client.Client
 	restClient rest.Interface
+	restConfig *rest.Config
 }
 
 // NewKarmadaClient creates a new client for Karmada operations using the mounted kubeconfig
@@ -105,6 +106,7 @@ func NewKarmadaClient() (*KarmadaClient, error) {
 	return &KarmadaClient{
 		Client:     karmadaClient,
 		restClient: restClient,
+		restConfig: config,
 	}, nil
 }
 
@@ -174,4 +176,12 @@ func (k *KarmadaClient) TestConnection(ctx context.Context) error {
 // RESTClient returns the REST client for making proxy requests
 func (k *KarmadaClient) RESTClient() rest.Interface {
 	return k.restClient
-}
\ No newline at end of file
+}
+
+// RESTConfig returns a copy of the REST config used to reach the Karmada API server
+func (k *KarmadaClient) RESTConfig() *rest.Config {
+	if k.restConfig == nil {
+		return nil
+	}
+	return rest.CopyConfig(k.restConfig)
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultKubeletServingCertFile is the self-signed serving certificate kubelet writes when
+	// serverTLSBootstrap is off; it is its own CA
+	DefaultKubeletServingCertFile = "/var/lib/kubelet/pki/kubelet.crt"
+	// DefaultKubeletPort is used when the Node does not report status.daemonEndpoints
+	DefaultKubeletPort = 10250
+)
+
+// KubeletClientOptions configure how the checkpoint agent authenticates to the kubelet and
+// verifies its serving certificate
+type KubeletClientOptions struct {
+	// CAFile is a PEM bundle trusted for the kubelet serving certificate. When empty the node's
+	// ServingCertFile and the cluster CA of the service account are trusted.
+	CAFile string
+	// ServingCertFile is the kubelet serving certificate on the node (host path mounted in the pod)
+	ServingCertFile string
+	// CertFile and KeyFile authenticate with a client certificate instead of a bearer token.
+	// Both files are reloaded when they are rotated.
+	CertFile string
+	KeyFile  string
+	// TokenFile holds the bearer token; it is re-read periodically so bound service account
+	// tokens keep working after rotation
+	TokenFile string
+	// InsecureSkipVerify disables verification of the kubelet serving certificate
+	InsecureSkipVerify bool
+	// Timeout of a kubelet request; zero leaves it to the context of the checkpoint run
+	Timeout time.Duration
+}
+
+// KubeletClient handles communication with kubelet API
+type KubeletClient struct {
+	httpClient *http.Client
+	kubeletURL string
+}
+
+// NewKubeletClient creates a kubelet client for the node. The address and port come from the
+// Node object; the serving certificate is verified against the node's host name.
+func NewKubeletClient(ctx context.Context, reader client.Reader, nodeName string, opts KubeletClientOptions) (*KubeletClient, error) {
+	var node corev1.Node
+	if err := reader.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	return newKubeletClientForNode(&node, opts)
+}
+
+// newKubeletClientForNode creates a kubelet client for node
+func newKubeletClientForNode(node *corev1.Node, opts KubeletClientOptions) (*KubeletClient, error) {
+	host, serverName := kubeletAddress(node)
+	if host == "" {
+		return nil, fmt.Errorf("node %s has no address and NODE_IP is not set", node.Name)
+	}
+	port := int(node.Status.DaemonEndpoints.KubeletEndpoint.Port)
+	if port == 0 {
+		port = DefaultKubeletPort
+	}
+	kubeletURL := "https://" + net.JoinHostPort(host, strconv.Itoa(port))
+
+	cfg := &rest.Config{
+		Host:    kubeletURL,
+		Timeout: opts.Timeout,
+		TLSClientConfig: rest.TLSClientConfig{
+			ServerName: serverName,
+		},
+	}
+
+	if opts.InsecureSkipVerify {
+		cfg.Insecure = true
+		cfg.ServerName = ""
+	} else {
+		caData, err := kubeletCABundle(opts)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CAData = caData
+	}
+
+	// 클라이언트 인증서가 있으면 우선, 없으면 회전되는 토큰 파일
+	switch {
+	case opts.CertFile != "" || opts.KeyFile != "":
+		if opts.CertFile == "" || opts.KeyFile == "" {
+			return nil, fmt.Errorf("both a kubelet client certificate and key are required")
+		}
+		cfg.CertFile = opts.CertFile
+		cfg.KeyFile = opts.KeyFile
+	default:
+		tokenFile := opts.TokenFile
+		if tokenFile == "" {
+			tokenFile = filepath.Join(ServiceAccountPath, "token")
+		}
+		if _, err := os.Stat(tokenFile); err != nil {
+			return nil, fmt.Errorf("failed to read service account token: %w", err)
+		}
+		cfg.BearerTokenFile = tokenFile
+	}
+
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubelet HTTP client: %w", err)
+	}
+	return &KubeletClient{
+		httpClient: httpClient,
+		kubeletURL: kubeletURL,
+	}, nil
+}
+
+// Backend implements Checkpointer
+func (kc *KubeletClient) Backend() string {
+	return CheckpointBackendKubelet
+}
+
+// Checkpoint implements Checkpointer with the kubelet ContainerCheckpoint API
+func (kc *KubeletClient) Checkpoint(ctx context.Context, pod *corev1.Pod, containerName string) (string, error) {
+	return kc.CreateCheckpoint(ctx, pod.Namespace, pod.Name, containerName)
+}
+
+// kubeletAddress returns the address to dial and the host name the serving certificate is
+// issued for. InternalIP is preferred; NODE_IP is the fallback when the Node lists no address.
+func kubeletAddress(node *corev1.Node) (string, string) {
+	addrs := map[corev1.NodeAddressType]string{}
+	for _, a := range node.Status.Addresses {
+		if _, found := addrs[a.Type]; !found {
+			addrs[a.Type] = a.Address
+		}
+	}
+	serverName := addrs[corev1.NodeHostName]
+	if serverName == "" {
+		serverName = node.Name
+	}
+	for _, t := range []corev1.NodeAddressType{corev1.NodeInternalIP, corev1.NodeExternalIP, corev1.NodeHostName} {
+		if addr := addrs[t]; addr != "" {
+			return addr, serverName
+		}
+	}
+	return os.Getenv("NODE_IP"), serverName
+}
+
+// kubeletCABundle returns the CA bundle trusted for the kubelet serving certificate: the
+// configured file, or the node's self-signed serving certificate together with the cluster CA
+// (which signs serving certificates issued through serverTLSBootstrap)
+func kubeletCABundle(opts KubeletClientOptions) ([]byte, error) {
+	if opts.CAFile != "" {
+		data, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kubelet CA file: %w", err)
+		}
+		return data, nil
+	}
+	servingCert := opts.ServingCertFile
+	if servingCert == "" {
+		servingCert = DefaultKubeletServingCertFile
+	}
+	var bundle bytes.Buffer
+	for _, f := range []string{servingCert, filepath.Join(ServiceAccountPath, "ca.crt")} {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		bundle.Write(bytes.TrimSpace(data))
+		bundle.WriteString("\n")
+	}
+	if bundle.Len() == 0 {
+		return nil, fmt.Errorf("no CA to verify the kubelet: set --kubelet-ca-file or mount %s", servingCert)
+	}
+	return bundle.Bytes(), nil
+}
+
+// CreateCheckpoint calls kubelet checkpoint API. The request is cancelled with ctx, and the
+// kubelet's own timeout is set to the time left until the deadline of ctx.
+func (kc *KubeletClient) CreateCheckpoint(ctx context.Context, namespace, podName, containerName string) (string, error) {
+	url := fmt.Sprintf("%s/checkpoint/%s/%s/%s", kc.kubeletURL, namespace, podName, containerName)
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := int64(time.Until(deadline).Seconds())
+		if timeout < 1 {
+			return "", context.DeadlineExceeded
+		}
+		url = fmt.Sprintf("%s?timeout=%d", url, timeout)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := kc.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call kubelet checkpoint API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("kubelet checkpoint API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// First, try to read the response body to see what we actually get
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read checkpoint response body: %w", err)
+	}
+
+	// Log the raw response for debugging
+	fmt.Printf("DEBUG: Kubelet checkpoint API response body: %s\n", string(body))
+
+	var checkpointResp CheckpointResponse
+	if err := json.Unmarshal(body, &checkpointResp); err != nil {
+		// If JSON parsing fails, fall back to file search by returning a placeholder
+		responseText := strings.TrimSpace(string(body))
+		fmt.Printf("DEBUG: Failed to parse JSON response from kubelet: %s\n", responseText)
+		return "unknown-checkpoint-file", nil
+	}
+
+	if len(checkpointResp.Items) == 0 {
+		// If JSON response doesn't contain any items, fall back to file search
+		fmt.Printf("DEBUG: JSON response has no checkpoint items, falling back to file search\n")
+		return "unknown-checkpoint-file", nil
+	}
+
+	// Use the first (and likely only) checkpoint path from the response
+	checkpointPath := checkpointResp.Items[0]
+	fmt.Printf("DEBUG: Successfully parsed JSON response, checkpoint path: %s\n", checkpointPath)
+
+	// Convert absolute path to relative path (remove the base path prefix)
+	if strings.HasPrefix(checkpointPath, CheckpointBasePath+"/") {
+		relativePath := strings.TrimPrefix(checkpointPath, CheckpointBasePath+"/")
+		fmt.Printf("DEBUG: Converted to relative path: %s\n", relativePath)
+		return relativePath, nil
+	} else if strings.HasPrefix(checkpointPath, "/var/lib/kubelet/checkpoints/") {
+		relativePath := strings.TrimPrefix(checkpointPath, "/var/lib/kubelet/checkpoints/")
+		fmt.Printf("DEBUG: Converted to relative path: %s\n", relativePath)
+		return relativePath, nil
+	}
+
+	// If path doesn't have expected prefix, just return the filename
+	relativePath := filepath.Base(checkpointPath)
+	fmt.Printf("DEBUG: Using filename only: %s\n", relativePath)
+	return relativePath, nil
+}
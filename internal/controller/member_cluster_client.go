@@ -20,20 +20,33 @@ import (
         "context"
         "fmt"
         "os"
+        "strings"
+        "sync"
 
         appsv1 "k8s.io/api/apps/v1"
         corev1 "k8s.io/api/core/v1"
         apierrors "k8s.io/apimachinery/pkg/api/errors"
+        "k8s.io/apimachinery/pkg/api/meta"
         "k8s.io/apimachinery/pkg/labels"
         metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+        "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+        "k8s.io/apimachinery/pkg/runtime/schema"
+        "k8s.io/client-go/discovery"
+        "k8s.io/client-go/discovery/cached/memory"
+        "k8s.io/client-go/dynamic"
         "k8s.io/client-go/rest"
+        "k8s.io/client-go/restmapper"
         "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // MemberClusterClient: Karmada Aggregated API 프록시로 멤버 클러스터에 접근
 type MemberClusterClient struct {
         karmadaClient *KarmadaClient
-        restConfig    *rest.Config // (직접 사용 안 함: m.karmadaClient.RESTClient() 사용)
+        restConfig    *rest.Config // Karmada REST config (dynamic client 생성용, 그 외는 m.karmadaClient.RESTClient() 사용)
+
+        // 클러스터별 discovery 결과 캐시 (kind → resource)
+        mappersMu sync.Mutex
+        mappers   map[string]*restmapper.DeferredDiscoveryRESTMapper
 }
 
 func NewMemberClusterClient(karmadaClient *KarmadaClient) (*MemberClusterClient, error) {
@@ -42,7 +55,7 @@ func NewMemberClusterClient(karmadaClient *KarmadaClient) (*MemberClusterClient,
         }
         return &MemberClusterClient{
                 karmadaClient: karmadaClient,
-                restConfig:    nil,
+                restConfig:    karmadaClient.RESTConfig(),
         }, nil
 }
 
@@ -262,6 +275,163 @@ func (m *MemberClusterClient) UpdateDeploymentInCluster(ctx context.Context, clu
         return nil
 }
 
+// -------- Dynamic (custom workload kinds) --------
+
+// clusterRESTConfig: 멤버 클러스터 프록시를 Host로 하는 REST config
+func (m *MemberClusterClient) clusterRESTConfig(clusterName string) (*rest.Config, error) {
+        if m.restConfig == nil {
+                return nil, fmt.Errorf("karmada REST config not available")
+        }
+        cfg := rest.CopyConfig(m.restConfig)
+        cfg.Host = strings.TrimSuffix(cfg.Host, "/") + clusterProxyBase(clusterName)
+        return cfg, nil
+}
+
+// restMapper: 멤버 클러스터별 discovery 캐시를 쓰는 RESTMapper (처음 조회할 때 생성)
+func (m *MemberClusterClient) restMapper(clusterName string) (*restmapper.DeferredDiscoveryRESTMapper, error) {
+        m.mappersMu.Lock()
+        defer m.mappersMu.Unlock()
+        if mapper, ok := m.mappers[clusterName]; ok {
+                return mapper, nil
+        }
+        cfg, err := m.clusterRESTConfig(clusterName)
+        if err != nil {
+                return nil, err
+        }
+        dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+        if err != nil {
+                return nil, fmt.Errorf("discovery client for %s: %w", clusterName, err)
+        }
+        mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+        if m.mappers == nil {
+                m.mappers = map[string]*restmapper.DeferredDiscoveryRESTMapper{}
+        }
+        m.mappers[clusterName] = mapper
+        return mapper, nil
+}
+
+// resourceForKind: 멤버 클러스터 discovery로 apiVersion/kind → resource 매핑
+// discovery 결과는 캐시되며, 모르는 kind(새로 설치된 CRD 등)일 때만 다시 조회
+func (m *MemberClusterClient) resourceForKind(clusterName, apiVersion, kind string) (schema.GroupVersionResource, bool, error) {
+        gv, err := schema.ParseGroupVersion(apiVersion)
+        if err != nil {
+                return schema.GroupVersionResource{}, false, fmt.Errorf("parse apiVersion %q: %w", apiVersion, err)
+        }
+        mapper, err := m.restMapper(clusterName)
+        if err != nil {
+                return schema.GroupVersionResource{}, false, err
+        }
+        gk := schema.GroupKind{Group: gv.Group, Kind: kind}
+        mapping, err := mapper.RESTMapping(gk, gv.Version)
+        if meta.IsNoMatchError(err) {
+                mapper.Reset()
+                mapping, err = mapper.RESTMapping(gk, gv.Version)
+        }
+        if meta.IsNoMatchError(err) {
+                return schema.GroupVersionResource{}, false, fmt.Errorf("kind %s is not served by %s on cluster %s", kind, gv.String(), clusterName)
+        }
+        if err != nil {
+                return schema.GroupVersionResource{}, false, fmt.Errorf("discover %s on %s: %w", gv.String(), clusterName, err)
+        }
+        return mapping.Resource, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+func (m *MemberClusterClient) resourceInterface(clusterName, apiVersion, kind, namespace string) (dynamic.ResourceInterface, error) {
+        gvr, namespaced, err := m.resourceForKind(clusterName, apiVersion, kind)
+        if err != nil {
+                return nil, err
+        }
+        cfg, err := m.clusterRESTConfig(clusterName)
+        if err != nil {
+                return nil, err
+        }
+        dc, err := dynamic.NewForConfig(cfg)
+        if err != nil {
+                return nil, fmt.Errorf("dynamic client for %s: %w", clusterName, err)
+        }
+        if namespaced {
+                return dc.Resource(gvr).Namespace(namespace), nil
+        }
+        return dc.Resource(gvr), nil
+}
+
+func (m *MemberClusterClient) GetResourceFromCluster(ctx context.Context, clusterName, apiVersion, kind, namespace, name string) (*unstructured.Unstructured, error) {
+        logger := log.FromContext(ctx)
+        ri, err := m.resourceInterface(clusterName, apiVersion, kind, namespace)
+        if err != nil {
+                return nil, err
+        }
+        obj, err := ri.Get(ctx, name, metav1.GetOptions{})
+        if err != nil {
+                return nil, fmt.Errorf("get %s %s/%s from %s: %w", kind, namespace, name, clusterName, err)
+        }
+        logger.Info("Retrieved resource from member cluster", "cluster", clusterName, "kind", kind, "namespace", namespace, "name", name)
+        return obj, nil
+}
+
+func (m *MemberClusterClient) CreateResourceInCluster(ctx context.Context, clusterName string, obj *unstructured.Unstructured) error {
+        logger := log.FromContext(ctx)
+        if obj == nil {
+                return fmt.Errorf("resource is nil")
+        }
+        ri, err := m.resourceInterface(clusterName, obj.GetAPIVersion(), obj.GetKind(), obj.GetNamespace())
+        if err != nil {
+                return err
+        }
+        if _, err := ri.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+                // 이미 있으면 호출자가 처리하도록 원래 오류를 감싸서 반환
+                return fmt.Errorf("create %s %s/%s on %s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), clusterName, err)
+        }
+        logger.Info("Created resource on member cluster", "cluster", clusterName, "kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
+        return nil
+}
+
+func (m *MemberClusterClient) UpdateResourceInCluster(ctx context.Context, clusterName string, obj *unstructured.Unstructured) error {
+        logger := log.FromContext(ctx)
+        if obj == nil {
+                return fmt.Errorf("resource is nil")
+        }
+        ri, err := m.resourceInterface(clusterName, obj.GetAPIVersion(), obj.GetKind(), obj.GetNamespace())
+        if err != nil {
+                return err
+        }
+        if _, err := ri.Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+                return fmt.Errorf("update %s %s/%s on %s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), clusterName, err)
+        }
+        logger.Info("Updated resource on member cluster", "cluster", clusterName, "kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
+        return nil
+}
+
+func (m *MemberClusterClient) ListResourcesFromCluster(ctx context.Context, clusterName, apiVersion, kind, namespace, labelSelector string) (*unstructured.UnstructuredList, error) {
+        logger := log.FromContext(ctx)
+        ri, err := m.resourceInterface(clusterName, apiVersion, kind, namespace)
+        if err != nil {
+                return nil, err
+        }
+        list, err := ri.List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+        if err != nil {
+                return nil, fmt.Errorf("list %s in %q from %s: %w", kind, namespace, clusterName, err)
+        }
+        logger.V(1).Info("Listed resources from member cluster", "cluster", clusterName, "kind", kind, "namespace", namespace, "count", len(list.Items))
+        return list, nil
+}
+
+func (m *MemberClusterClient) DeleteResourceFromCluster(ctx context.Context, clusterName string, obj *unstructured.Unstructured) error {
+        logger := log.FromContext(ctx)
+        if obj == nil {
+                return fmt.Errorf("resource is nil")
+        }
+        ri, err := m.resourceInterface(clusterName, obj.GetAPIVersion(), obj.GetKind(), obj.GetNamespace())
+        if err != nil {
+                return err
+        }
+        if err := ri.Delete(ctx, obj.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+                return fmt.Errorf("delete %s %s/%s on %s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), clusterName, err)
+        }
+        logger.Info("Deleted resource on member cluster", "cluster", clusterName, "kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
+        return nil
+}
+
 // -------- Connectivity Test --------
 
 func (m *MemberClusterClient) TestClusterConnection(ctx context.Context, clusterName string) error {
@@ -0,0 +1,400 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// fakeMemberResource is a resource served by every cluster behind fakeMemberProxy
+type fakeMemberResource struct {
+	gv         schema.GroupVersion
+	kind       string
+	resource   string
+	namespaced bool
+}
+
+var fakeMemberResources = []fakeMemberResource{
+	{schema.GroupVersion{Version: "v1"}, "Namespace", "namespaces", false},
+	{schema.GroupVersion{Version: "v1"}, "Pod", "pods", true},
+	{schema.GroupVersion{Version: "v1"}, "PersistentVolumeClaim", "persistentvolumeclaims", true},
+	{schema.GroupVersion{Group: "apps", Version: "v1"}, "Deployment", "deployments", true},
+	{schema.GroupVersion{Group: "admissionregistration.k8s.io", Version: "v1"}, "MutatingWebhookConfiguration", "mutatingwebhookconfigurations", false},
+	{schema.GroupVersion{Group: "snapshot.storage.k8s.io", Version: "v1"}, "VolumeSnapshot", "volumesnapshots", true},
+	{schema.GroupVersion{Group: "snapshot.storage.k8s.io", Version: "v1"}, "VolumeSnapshotContent", "volumesnapshotcontents", false},
+	{migrationv1.GroupVersion, "CheckpointBackup", "checkpointbackups", true},
+	{migrationv1.GroupVersion, "CheckpointRestore", "checkpointrestores", true},
+}
+
+// fakeMemberProxy serves the Karmada cluster proxy for any number of member clusters. Each
+// cluster keeps its objects in memory; clusters marked down answer 503.
+type fakeMemberProxy struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	objects  map[string]map[string]*unstructured.Unstructured // cluster → resource/namespace/name
+	down     map[string]bool
+	requests []string // "<METHOD> <cluster> <path>" (discovery 제외)
+}
+
+func newFakeMemberProxy(t *testing.T) *fakeMemberProxy {
+	t.Helper()
+	p := &fakeMemberProxy{objects: map[string]map[string]*unstructured.Unstructured{}, down: map[string]bool{}}
+	p.server = httptest.NewServer(http.HandlerFunc(p.serve))
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// newTestKarmadaClient wraps c as a Karmada client whose proxy requests go to p
+func newTestKarmadaClient(t *testing.T, c client.Client, p *fakeMemberProxy) *KarmadaClient {
+	t.Helper()
+	kc := &KarmadaClient{Client: c}
+	if p == nil {
+		return kc
+	}
+	kc.restConfig = &rest.Config{Host: p.server.URL}
+	cfg := rest.CopyConfig(kc.restConfig)
+	cfg.APIPath = "/apis"
+	cfg.GroupVersion = &schema.GroupVersion{Group: "cluster.karmada.io", Version: "v1alpha1"}
+	cfg.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	rc, err := rest.RESTClientFor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kc.restClient = rc
+	return kc
+}
+
+// newTestMemberClusterClient returns a member cluster client reaching the clusters behind p
+func newTestMemberClusterClient(t *testing.T, c client.Client, p *fakeMemberProxy) *MemberClusterClient {
+	t.Helper()
+	mc, err := NewMemberClusterClient(newTestKarmadaClient(t, c, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mc
+}
+
+// add stores obj on cluster; apiVersion and kind select the resource
+func (p *fakeMemberProxy) add(cluster string, obj *unstructured.Unstructured) {
+	res, ok := fakeMemberResourceFor(obj.GetAPIVersion(), obj.GetKind())
+	if !ok {
+		panic("fake member proxy does not serve " + obj.GetAPIVersion() + "/" + obj.GetKind())
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.store(cluster)[res.resource+"/"+obj.GetNamespace()+"/"+obj.GetName()] = obj.DeepCopy()
+}
+
+// get returns the object stored on cluster, or nil
+func (p *fakeMemberProxy) get(cluster, apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	res, _ := fakeMemberResourceFor(apiVersion, kind)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if obj, ok := p.objects[cluster][res.resource+"/"+namespace+"/"+name]; ok {
+		return obj.DeepCopy()
+	}
+	return nil
+}
+
+// setDown makes every request to cluster fail with 503
+func (p *fakeMemberProxy) setDown(cluster string, down bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down[cluster] = down
+}
+
+// countRequests returns how many requests with the method reached cluster
+func (p *fakeMemberProxy) countRequests(method, cluster string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, r := range p.requests {
+		if strings.HasPrefix(r, method+" "+cluster+" ") {
+			n++
+		}
+	}
+	return n
+}
+
+func (p *fakeMemberProxy) store(cluster string) map[string]*unstructured.Unstructured {
+	if p.objects[cluster] == nil {
+		p.objects[cluster] = map[string]*unstructured.Unstructured{}
+	}
+	return p.objects[cluster]
+}
+
+func fakeMemberResourceFor(apiVersion, kind string) (fakeMemberResource, bool) {
+	for _, r := range fakeMemberResources {
+		if r.gv.String() == apiVersion && r.kind == kind {
+			return r, true
+		}
+	}
+	return fakeMemberResource{}, false
+}
+
+func (p *fakeMemberProxy) serve(w http.ResponseWriter, req *http.Request) {
+	tail, ok := strings.CutPrefix(req.URL.Path, "/apis/cluster.karmada.io/v1alpha1/clusters/")
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	cluster, path, _ := strings.Cut(tail, "/proxy")
+	p.mu.Lock()
+	down := p.down[cluster]
+	p.mu.Unlock()
+	if down {
+		writeStatus(w, apierrors.NewServiceUnavailable("cluster "+cluster+" is unreachable"))
+		return
+	}
+
+	// discovery
+	switch {
+	case path == "/api":
+		writeJSON(w, http.StatusOK, &metav1.APIVersions{Versions: []string{"v1"}})
+		return
+	case path == "/apis":
+		groups := map[string]*metav1.APIGroup{}
+		var names []string
+		for _, r := range fakeMemberResources {
+			if r.gv.Group == "" {
+				continue
+			}
+			if groups[r.gv.Group] == nil {
+				gv := metav1.GroupVersionForDiscovery{GroupVersion: r.gv.String(), Version: r.gv.Version}
+				groups[r.gv.Group] = &metav1.APIGroup{Name: r.gv.Group, Versions: []metav1.GroupVersionForDiscovery{gv}, PreferredVersion: gv}
+				names = append(names, r.gv.Group)
+			}
+		}
+		sort.Strings(names)
+		list := &metav1.APIGroupList{}
+		for _, n := range names {
+			list.Groups = append(list.Groups, *groups[n])
+		}
+		writeJSON(w, http.StatusOK, list)
+		return
+	}
+
+	var gv schema.GroupVersion
+	var segs []string
+	if s, ok := strings.CutPrefix(path, "/api/v1"); ok {
+		gv, segs = schema.GroupVersion{Version: "v1"}, strings.Split(strings.Trim(s, "/"), "/")
+	} else if s, ok := strings.CutPrefix(path, "/apis/"); ok {
+		parts := strings.SplitN(s, "/", 3)
+		if len(parts) < 2 {
+			http.NotFound(w, req)
+			return
+		}
+		gv = schema.GroupVersion{Group: parts[0], Version: parts[1]}
+		if len(parts) == 3 {
+			segs = strings.Split(parts[2], "/")
+		}
+	}
+	if len(segs) == 0 || segs[0] == "" {
+		list := &metav1.APIResourceList{GroupVersion: gv.String()}
+		for _, r := range fakeMemberResources {
+			if r.gv == gv {
+				list.APIResources = append(list.APIResources, metav1.APIResource{
+					Name: r.resource, Kind: r.kind, Namespaced: r.namespaced,
+					Verbs: metav1.Verbs{"get", "list", "create", "update", "delete"},
+				})
+			}
+		}
+		writeJSON(w, http.StatusOK, list)
+		return
+	}
+
+	namespace := ""
+	if len(segs) >= 3 && segs[0] == "namespaces" {
+		namespace, segs = segs[1], segs[2:]
+	}
+	var res fakeMemberResource
+	found := false
+	for _, r := range fakeMemberResources {
+		if r.gv == gv && r.resource == segs[0] {
+			res, found = r, true
+		}
+	}
+	if !found {
+		http.NotFound(w, req)
+		return
+	}
+	name := ""
+	if len(segs) > 1 {
+		name = segs[1]
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, req.Method+" "+cluster+" "+path)
+	objects := p.store(cluster)
+	gr := schema.GroupResource{Group: gv.Group, Resource: res.resource}
+	switch {
+	case req.Method == http.MethodGet && name == "":
+		sel, err := labels.Parse(req.URL.Query().Get("labelSelector"))
+		if err != nil {
+			writeStatus(w, apierrors.NewBadRequest(err.Error()))
+			return
+		}
+		var keys []string
+		for k, obj := range objects {
+			if strings.HasPrefix(k, res.resource+"/") && (namespace == "" || obj.GetNamespace() == namespace) && sel.Matches(labels.Set(obj.GetLabels())) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		items := []interface{}{}
+		for _, k := range keys {
+			items = append(items, objects[k].Object)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"apiVersion": gv.String(), "kind": res.kind + "List",
+			"metadata": map[string]interface{}{}, "items": items,
+		})
+	case req.Method == http.MethodGet:
+		obj, ok := objects[res.resource+"/"+namespace+"/"+name]
+		if !ok {
+			writeStatus(w, apierrors.NewNotFound(gr, name))
+			return
+		}
+		writeJSON(w, http.StatusOK, obj.Object)
+	case req.Method == http.MethodPost || req.Method == http.MethodPut:
+		body, _ := io.ReadAll(req.Body)
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(body); err != nil {
+			writeStatus(w, apierrors.NewBadRequest(err.Error()))
+			return
+		}
+		if obj.GetAPIVersion() == "" {
+			obj.SetAPIVersion(gv.String())
+			obj.SetKind(res.kind)
+		}
+		if res.namespaced && obj.GetNamespace() == "" {
+			obj.SetNamespace(namespace)
+		}
+		key := res.resource + "/" + obj.GetNamespace() + "/" + obj.GetName()
+		_, exists := objects[key]
+		if req.Method == http.MethodPost && exists {
+			writeStatus(w, apierrors.NewAlreadyExists(gr, obj.GetName()))
+			return
+		}
+		if req.Method == http.MethodPut && !exists {
+			writeStatus(w, apierrors.NewNotFound(gr, obj.GetName()))
+			return
+		}
+		objects[key] = obj
+		writeJSON(w, http.StatusOK, obj.Object)
+	case req.Method == http.MethodDelete:
+		key := res.resource + "/" + namespace + "/" + name
+		if _, ok := objects[key]; !ok {
+			writeStatus(w, apierrors.NewNotFound(gr, name))
+			return
+		}
+		delete(objects, key)
+		writeJSON(w, http.StatusOK, &metav1.Status{Status: metav1.StatusSuccess})
+	default:
+		writeStatus(w, apierrors.NewMethodNotSupported(gr, req.Method))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeStatus(w http.ResponseWriter, err *apierrors.StatusError) {
+	st := err.Status()
+	st.Kind, st.APIVersion = "Status", "v1"
+	writeJSON(w, int(st.Code), &st)
+}
+
+// newMemberObject returns an unstructured object for the fake member proxy
+func newMemberObject(apiVersion, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range fields {
+		obj.Object[k] = v
+	}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestMemberClusterClientThroughProxy(t *testing.T) {
+	ctx := context.Background()
+	proxy := newFakeMemberProxy(t)
+	mc := newTestMemberClusterClient(t, nil, proxy)
+
+	backup := newMemberObject(migrationv1.GroupVersion.String(), "CheckpointBackup", "default", "app-0",
+		map[string]interface{}{"status": map[string]interface{}{"phase": PhaseCompleted}})
+	backup.SetLabels(map[string]string{"app": "web"})
+	proxy.add("member1", backup)
+
+	got, err := mc.GetResourceFromCluster(ctx, "member1", migrationv1.GroupVersion.String(), "CheckpointBackup", "default", "app-0")
+	if err != nil {
+		t.Fatalf("GetResourceFromCluster: %v", err)
+	}
+	if phase, _, _ := unstructured.NestedString(got.Object, "status", "phase"); phase != PhaseCompleted {
+		t.Errorf("phase = %q, want %q", phase, PhaseCompleted)
+	}
+	if _, err := mc.GetResourceFromCluster(ctx, "member2", migrationv1.GroupVersion.String(), "CheckpointBackup", "default", "app-0"); !apierrors.IsNotFound(err) {
+		t.Errorf("get from member2: error = %v, want NotFound", err)
+	}
+
+	list, err := mc.ListResourcesFromCluster(ctx, "member1", migrationv1.GroupVersion.String(), "CheckpointBackup", "default", "app=db")
+	if err != nil {
+		t.Fatalf("ListResourcesFromCluster: %v", err)
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("listed %d backups for app=db, want 0", len(list.Items))
+	}
+
+	if err := mc.EnsureNamespace(ctx, "member1", "default"); err != nil {
+		t.Fatalf("EnsureNamespace: %v", err)
+	}
+	if proxy.get("member1", "v1", "Namespace", "", "default") == nil {
+		t.Error("namespace default was not created on member1")
+	}
+	if err := mc.TestClusterConnection(ctx, "member1"); err != nil {
+		t.Errorf("TestClusterConnection: %v", err)
+	}
+	proxy.setDown("member1", true)
+	if err := mc.TestClusterConnection(ctx, "member1"); err == nil {
+		t.Error("TestClusterConnection succeeded for a cluster that is down")
+	}
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// memberWatchKey identifies a watch on a member cluster. An empty Name watches every object
+// of the kind in the namespace that matches Selector, a label selector string.
+type memberWatchKey struct {
+	Cluster    string
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	Selector   string
+}
+
+type memberWatch struct {
+	informer cache.SharedIndexInformer
+	stopCh   chan struct{}
+	owners   map[types.NamespacedName]bool
+}
+
+// MemberClusterWatcher runs informers on member clusters through the Karmada aggregated API
+// proxy and turns pod and workload changes into reconcile events for the StatefulMigrations
+// that registered interest in them.
+//
+// Informer handlers never block: they mark the interested StatefulMigrations as pending, and
+// a single goroutine delivers them to the controller. A slow consumer only delays events,
+// and repeated changes of the same StatefulMigration collapse into one.
+type MemberClusterWatcher struct {
+	mu      sync.Mutex
+	watches map[memberWatchKey]*memberWatch
+	events  chan event.GenericEvent
+
+	dynamicMu sync.Mutex
+	dynamic   map[string]dynamic.Interface
+
+	pendingMu sync.Mutex
+	pending   map[types.NamespacedName]bool
+	wake      chan struct{}
+}
+
+// NewMemberClusterWatcher creates a watcher with no active informers
+func NewMemberClusterWatcher() *MemberClusterWatcher {
+	return &MemberClusterWatcher{
+		watches: map[memberWatchKey]*memberWatch{},
+		dynamic: map[string]dynamic.Interface{},
+		events:  make(chan event.GenericEvent, 1024),
+		pending: map[types.NamespacedName]bool{},
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Events returns the channel reconcile events are delivered on
+func (w *MemberClusterWatcher) Events() <-chan event.GenericEvent {
+	return w.events
+}
+
+// Sync makes sure the given keys are watched on behalf of owner and releases the
+// owner's interest in every other key. Informers nobody is interested in are stopped.
+func (w *MemberClusterWatcher) Sync(mc *MemberClusterClient, owner types.NamespacedName, keys []memberWatchKey) error {
+	w.mu.Lock()
+	var missing []memberWatchKey
+	for _, key := range keys {
+		if _, ok := w.watches[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	w.mu.Unlock()
+
+	// discovery와 클라이언트 생성은 멤버 클러스터가 느릴 수 있으므로 락 밖에서
+	prepared := make(map[memberWatchKey]*memberWatch, len(missing))
+	var errs []error
+	for _, key := range missing {
+		mw, err := w.newWatch(mc, key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		prepared[key] = mw
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wanted := make(map[memberWatchKey]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+		mw, ok := w.watches[key]
+		if !ok {
+			// 동시에 다른 Sync가 먼저 시작했다면 준비한 informer는 실행하지 않고 버림
+			if mw, ok = prepared[key]; !ok {
+				continue
+			}
+			w.watches[key] = mw
+			go mw.informer.Run(mw.stopCh)
+			ctrl.Log.WithName("member-watcher").Info("Started member cluster watch", "cluster", key.Cluster, "kind", key.Kind, "namespace", key.Namespace, "name", key.Name, "selector", key.Selector)
+		}
+		mw.owners[owner] = true
+	}
+	w.releaseLocked(owner, wanted)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("start member cluster watches: %v", errs)
+	}
+	return nil
+}
+
+// Forget releases every watch held by owner
+func (w *MemberClusterWatcher) Forget(owner types.NamespacedName) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.releaseLocked(owner, nil)
+}
+
+func (w *MemberClusterWatcher) releaseLocked(owner types.NamespacedName, keep map[memberWatchKey]bool) {
+	for key, mw := range w.watches {
+		if keep[key] || !mw.owners[owner] {
+			continue
+		}
+		delete(mw.owners, owner)
+		if len(mw.owners) == 0 {
+			close(mw.stopCh)
+			delete(w.watches, key)
+			ctrl.Log.WithName("member-watcher").Info("Stopped member cluster watch", "cluster", key.Cluster, "kind", key.Kind, "namespace", key.Namespace, "name", key.Name)
+		}
+	}
+}
+
+// newWatch builds the informer of key without starting it; Sync runs it once the watch is registered
+func (w *MemberClusterWatcher) newWatch(mc *MemberClusterClient, key memberWatchKey) (*memberWatch, error) {
+	if mc == nil {
+		return nil, fmt.Errorf("member cluster client not initialized")
+	}
+	gvr, _, err := mc.resourceForKind(key.Cluster, key.APIVersion, key.Kind)
+	if err != nil {
+		return nil, err
+	}
+	dc, err := w.dynamicClient(mc, key.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	informer := dynamicinformer.NewFilteredDynamicInformer(dc, gvr, key.Namespace, 0, cache.Indexers{}, func(opts *metav1.ListOptions) {
+		if key.Name != "" {
+			opts.FieldSelector = "metadata.name=" + key.Name
+		}
+		if key.Selector != "" {
+			opts.LabelSelector = key.Selector
+		}
+	}).Informer()
+
+	mw := &memberWatch{informer: informer, stopCh: make(chan struct{}), owners: map[types.NamespacedName]bool{}}
+	notify := func() { w.notify(key) }
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { notify() },
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldU, ok1 := oldObj.(*unstructured.Unstructured)
+			newU, ok2 := newObj.(*unstructured.Unstructured)
+			if !ok1 || !ok2 || relevantMemberChange(oldU, newU) {
+				notify()
+			}
+		},
+		DeleteFunc: func(obj interface{}) { notify() },
+	}); err != nil {
+		return nil, fmt.Errorf("add event handler: %w", err)
+	}
+	return mw, nil
+}
+
+// dynamicClient returns the cached dynamic client of a member cluster
+func (w *MemberClusterWatcher) dynamicClient(mc *MemberClusterClient, cluster string) (dynamic.Interface, error) {
+	w.dynamicMu.Lock()
+	defer w.dynamicMu.Unlock()
+	if dc, ok := w.dynamic[cluster]; ok {
+		return dc, nil
+	}
+	cfg, err := mc.clusterRESTConfig(cluster)
+	if err != nil {
+		return nil, err
+	}
+	dc, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic client for %s: %w", cluster, err)
+	}
+	w.dynamic[cluster] = dc
+	return dc, nil
+}
+
+// notify marks every StatefulMigration interested in key as pending; it never blocks the informer
+func (w *MemberClusterWatcher) notify(key memberWatchKey) {
+	w.mu.Lock()
+	mw, ok := w.watches[key]
+	var owners []types.NamespacedName
+	if ok {
+		for owner := range mw.owners {
+			owners = append(owners, owner)
+		}
+	}
+	w.mu.Unlock()
+	if len(owners) == 0 {
+		return
+	}
+
+	w.pendingMu.Lock()
+	for _, owner := range owners {
+		w.pending[owner] = true
+	}
+	w.pendingMu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliver sends the pending StatefulMigrations to the controller until ctx is done
+func (w *MemberClusterWatcher) deliver(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.wake:
+		}
+		w.pendingMu.Lock()
+		owners := w.pending
+		w.pending = map[types.NamespacedName]bool{}
+		w.pendingMu.Unlock()
+
+		for owner := range owners {
+			select {
+			case w.events <- event.GenericEvent{Object: &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Namespace: owner.Namespace, Name: owner.Name},
+			}}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// Start delivers events and stops every informer once the manager shuts down
+func (w *MemberClusterWatcher) Start(ctx context.Context) error {
+	go w.deliver(ctx)
+	<-ctx.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, mw := range w.watches {
+		close(mw.stopCh)
+		delete(w.watches, key)
+	}
+	return nil
+}
+
+// relevantMemberChange filters out the frequent status-only updates that do not change
+// which pods need a CheckpointBackup
+func relevantMemberChange(oldObj, newObj *unstructured.Unstructured) bool {
+	if oldObj.GetUID() != newObj.GetUID() {
+		return true
+	}
+	if (oldObj.GetDeletionTimestamp() == nil) != (newObj.GetDeletionTimestamp() == nil) {
+		return true
+	}
+	if newObj.GetKind() == "Pod" {
+		oldPhase, _, _ := unstructured.NestedString(oldObj.Object, "status", "phase")
+		newPhase, _, _ := unstructured.NestedString(newObj.Object, "status", "phase")
+		oldNode, _, _ := unstructured.NestedString(oldObj.Object, "spec", "nodeName")
+		newNode, _, _ := unstructured.NestedString(newObj.Object, "spec", "nodeName")
+		return oldPhase != newPhase || oldNode != newNode
+	}
+	if oldObj.GetGeneration() != newObj.GetGeneration() {
+		return true
+	}
+	oldReplicas, _, _ := unstructured.NestedInt64(oldObj.Object, "status", "replicas")
+	newReplicas, _, _ := unstructured.NestedInt64(newObj.Object, "status", "replicas")
+	return oldReplicas != newReplicas
+}
+
+// memberWatchKeysFor returns the pod and workload watches a StatefulMigration needs on its clusters.
+// Pods are watched with the label selector they were listed by on each cluster (podSelectors); an
+// empty selector watches the whole namespace (OwnerReference resolution). A cluster without an
+// entry, e.g. because its workload does not exist yet, only gets the workload watch.
+func memberWatchKeysFor(sm *migrationv1.StatefulMigration, clusters []string, podSelectors map[string]string) []memberWatchKey {
+	ref := sm.Spec.ResourceRef
+	keys := make([]memberWatchKey, 0, 2*len(clusters))
+	for _, cluster := range clusters {
+		if ref.Kind == "Pod" && sm.Spec.PodResolution == nil {
+			keys = append(keys, memberWatchKey{Cluster: cluster, APIVersion: "v1", Kind: "Pod", Namespace: ref.Namespace, Name: ref.Name})
+		} else if selector, ok := podSelectors[cluster]; ok {
+			keys = append(keys, memberWatchKey{Cluster: cluster, APIVersion: "v1", Kind: "Pod", Namespace: ref.Namespace, Selector: selector})
+		}
+		if ref.Kind != "Pod" && ref.APIVersion != "" {
+			keys = append(keys, memberWatchKey{Cluster: cluster, APIVersion: ref.APIVersion, Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name})
+		}
+	}
+	return keys
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+func TestRelevantMemberChange(t *testing.T) {
+	pod := func(uid types.UID, phase, node string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec":   map[string]interface{}{"nodeName": node},
+			"status": map[string]interface{}{"phase": phase},
+		}}
+		u.SetKind("Pod")
+		u.SetUID(uid)
+		return u
+	}
+	workload := func(generation, replicas int64) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"replicas": replicas},
+		}}
+		u.SetKind("StatefulSet")
+		u.SetUID("sts")
+		u.SetGeneration(generation)
+		return u
+	}
+	deleting := func(u *unstructured.Unstructured) *unstructured.Unstructured {
+		u.SetDeletionTimestamp(&metav1.Time{Time: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)})
+		return u
+	}
+	withLabel := func(u *unstructured.Unstructured) *unstructured.Unstructured {
+		u.SetLabels(map[string]string{"restarted": "true"})
+		return u
+	}
+
+	tests := []struct {
+		name   string
+		oldObj *unstructured.Unstructured
+		newObj *unstructured.Unstructured
+		want   bool
+	}{
+		{name: "pod recreated with the same name", oldObj: pod("a", "Running", "n1"), newObj: pod("b", "Running", "n1"), want: true},
+		{name: "pod phase changed", oldObj: pod("a", "Pending", "n1"), newObj: pod("a", "Running", "n1"), want: true},
+		{name: "pod scheduled", oldObj: pod("a", "Pending", ""), newObj: pod("a", "Pending", "n1"), want: true},
+		{name: "pod terminating", oldObj: pod("a", "Running", "n1"), newObj: deleting(pod("a", "Running", "n1")), want: true},
+		{name: "pod status heartbeat", oldObj: pod("a", "Running", "n1"), newObj: pod("a", "Running", "n1")},
+		{name: "pod metadata only", oldObj: pod("a", "Running", "n1"), newObj: withLabel(pod("a", "Running", "n1"))},
+		{name: "workload spec changed", oldObj: workload(1, 3), newObj: workload(2, 3), want: true},
+		{name: "workload scaled", oldObj: workload(2, 3), newObj: workload(2, 4), want: true},
+		{name: "workload deleted", oldObj: workload(2, 3), newObj: deleting(workload(2, 3)), want: true},
+		{name: "workload status only", oldObj: workload(2, 3), newObj: workload(2, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relevantMemberChange(tt.oldObj, tt.newObj); got != tt.want {
+				t.Errorf("relevantMemberChange = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemberWatchKeysFor(t *testing.T) {
+	sts := &migrationv1.StatefulMigration{Spec: migrationv1.StatefulMigrationSpec{
+		ResourceRef: migrationv1.ResourceRef{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "ns", Name: "db"},
+	}}
+	pod := &migrationv1.StatefulMigration{Spec: migrationv1.StatefulMigrationSpec{
+		ResourceRef: migrationv1.ResourceRef{APIVersion: "v1", Kind: "Pod", Namespace: "ns", Name: "web"},
+	}}
+	workload := func(cluster string) memberWatchKey {
+		return memberWatchKey{Cluster: cluster, APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "ns", Name: "db"}
+	}
+
+	tests := []struct {
+		name      string
+		sm        *migrationv1.StatefulMigration
+		selectors map[string]string
+		want      []memberWatchKey
+	}{
+		{
+			name:      "pods scoped by the workload selector",
+			sm:        sts,
+			selectors: map[string]string{"c1": "app=db", "c2": "app=db"},
+			want: []memberWatchKey{
+				{Cluster: "c1", APIVersion: "v1", Kind: "Pod", Namespace: "ns", Selector: "app=db"},
+				workload("c1"),
+				{Cluster: "c2", APIVersion: "v1", Kind: "Pod", Namespace: "ns", Selector: "app=db"},
+				workload("c2"),
+			},
+		},
+		{
+			name:      "cluster without a resolved selector only watches the workload",
+			sm:        sts,
+			selectors: map[string]string{"c1": "app=db"},
+			want: []memberWatchKey{
+				{Cluster: "c1", APIVersion: "v1", Kind: "Pod", Namespace: "ns", Selector: "app=db"},
+				workload("c1"),
+				workload("c2"),
+			},
+		},
+		{
+			name:      "pods resolved by owner reference watch the namespace",
+			sm:        sts,
+			selectors: map[string]string{"c1": "", "c2": ""},
+			want: []memberWatchKey{
+				{Cluster: "c1", APIVersion: "v1", Kind: "Pod", Namespace: "ns"},
+				workload("c1"),
+				{Cluster: "c2", APIVersion: "v1", Kind: "Pod", Namespace: "ns"},
+				workload("c2"),
+			},
+		},
+		{
+			name: "a bare pod is watched by name",
+			sm:   pod,
+			want: []memberWatchKey{
+				{Cluster: "c1", APIVersion: "v1", Kind: "Pod", Namespace: "ns", Name: "web"},
+				{Cluster: "c2", APIVersion: "v1", Kind: "Pod", Namespace: "ns", Name: "web"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := memberWatchKeysFor(tt.sm, []string{"c1", "c2"}, tt.selectors)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keys = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// Metrics of the checkpoint pipeline, served by the manager's metrics endpoint
+// (--metrics-bind-address) next to the controller-runtime defaults
+const metricsNamespace = "stateful_migration"
+
+// Values of the result label of stateful_migration_checkpoint_runs_total
+const (
+	RunResultSuccess = "success"
+	RunResultFailure = "failure"
+)
+
+var (
+	checkpointStepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "checkpoint",
+		Name:      "step_duration_seconds",
+		Help:      "Duration of one step for one container: the checkpoint call to the node's backend, the image build and the image push. Time waiting for the worker pool is not included.",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12),
+	}, []string{"step"})
+
+	checkpointArchiveSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "checkpoint",
+		Name:      "archive_size_bytes",
+		Help:      "Size of the checkpoint archives written by the node's backend.",
+		Buckets:   prometheus.ExponentialBuckets(1<<20, 4, 8),
+	})
+
+	checkpointFreezeDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "checkpoint",
+		Name:      "freeze_duration_seconds",
+		Help:      "How long the container's processes were frozen for the checkpoint, as reported by CRIU in the archive's stats-dump.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 14),
+	})
+
+	checkpointRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "checkpoint",
+		Name:      "runs_total",
+		Help:      "Checkpoint runs that finished, by result and reason: the final phase for successes, the failed step, Timeout or Cancelled for failures.",
+	}, []string{"result", "reason"})
+
+	checkpointDiskUsageDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "checkpoint", "disk_usage_bytes"),
+		"Bytes taken by files under the checkpoint directory of the node.",
+		[]string{"node", "path"}, nil)
+
+	checkpointLastSuccessAgeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "checkpoint", "last_success_age_seconds"),
+		"Seconds since a pod of the StatefulMigration on this node was last checkpointed successfully.",
+		[]string{"namespace", "statefulmigration"}, nil)
+
+	restoresDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "restores"),
+		"ResourceBindings suspended for a restore: pending until the restore succeeds, stuck when it failed and dispatching was not resumed.",
+		[]string{"state"}, nil)
+)
+
+func init() {
+	metrics.Registry.MustRegister(checkpointStepDuration, checkpointArchiveSize, checkpointFreezeDuration, checkpointRuns)
+}
+
+// registerCollector adds a collector to the manager's metrics registry once per process
+func registerCollector(c prometheus.Collector) error {
+	if err := metrics.Registry.Register(c); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if !errors.As(err, &already) {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkpointStepError is returned when a step of a checkpoint run fails; the step becomes the
+// reason of the failed run in stateful_migration_checkpoint_runs_total
+type checkpointStepError struct {
+	step string
+	err  error
+}
+
+func (e *checkpointStepError) Error() string { return e.err.Error() }
+
+func (e *checkpointStepError) Unwrap() error { return e.err }
+
+// observeStep records the duration of a step that started at start
+func observeStep(step string, start time.Time) {
+	checkpointStepDuration.WithLabelValues(strings.ToLower(step)).Observe(time.Since(start).Seconds())
+}
+
+// recordCheckpointRun counts a finished run. Runs that did nothing, e.g. because the pod was not
+// running, are not counted.
+func recordCheckpointRun(ctx context.Context, err error, phase string) {
+	switch {
+	case errors.Is(context.Cause(ctx), context.Canceled):
+		checkpointRuns.WithLabelValues(RunResultFailure, "Cancelled").Inc()
+	case errors.Is(context.Cause(ctx), context.DeadlineExceeded):
+		checkpointRuns.WithLabelValues(RunResultFailure, "Timeout").Inc()
+	case err != nil:
+		reason := "Error"
+		var stepErr *checkpointStepError
+		if errors.As(err, &stepErr) {
+			reason = stepErr.step + "Failed"
+		}
+		checkpointRuns.WithLabelValues(RunResultFailure, reason).Inc()
+	case phase == PhaseCompleted || phase == PhaseCompletedPodDeleted:
+		checkpointRuns.WithLabelValues(RunResultSuccess, phase).Inc()
+	}
+}
+
+// observeCheckpointArchive records the size of a new checkpoint archive and the freeze time CRIU
+// reported in it
+func observeCheckpointArchive(ctx context.Context, path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	checkpointArchiveSize.Observe(float64(info.Size()))
+	frozen, err := archiveFreezeTime(path)
+	if err != nil {
+		logf.FromContext(ctx).V(1).Info("No freeze time in checkpoint archive", "path", path, "reason", err.Error())
+		return
+	}
+	checkpointFreezeDuration.Observe(frozen.Seconds())
+}
+
+// CRIU image magics (criu/include/magic.h)
+const (
+	criuServiceMagic = 0x55105940
+	criuStatsMagic   = 0x57093306
+)
+
+// archiveFreezeTime reads frozen_time from the CRIU stats-dump image in a checkpoint archive.
+// Archive entries are skipped with Seek, so the memory pages are not read.
+func archiveFreezeTime(path string) (time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return 0, errors.New("stats-dump not found")
+		}
+		if err != nil {
+			return 0, err
+		}
+		if filepath.Base(hdr.Name) != "stats-dump" {
+			continue
+		}
+		// stats-dump: service magic, stats magic, then one size-prefixed stats_entry
+		var header struct{ Service, Stats, Size uint32 }
+		if err := binary.Read(tr, binary.LittleEndian, &header); err != nil {
+			return 0, err
+		}
+		if header.Service != criuServiceMagic || header.Stats != criuStatsMagic {
+			return 0, errors.New("stats-dump has an unknown magic")
+		}
+		if header.Size > 1<<16 {
+			return 0, errors.New("stats-dump entry too large")
+		}
+		entry := make([]byte, header.Size)
+		if _, err := io.ReadFull(tr, entry); err != nil {
+			return 0, err
+		}
+		// stats_entry.dump (1) → dump_stats_entry.frozen_time (2), in microseconds
+		dump, ok := protoField(entry, 1)
+		if !ok {
+			return 0, errors.New("stats-dump has no dump statistics")
+		}
+		frozen, ok := protoVarint(dump, 2)
+		if !ok {
+			return 0, errors.New("stats-dump has no frozen_time")
+		}
+		return time.Duration(frozen) * time.Microsecond, nil
+	}
+}
+
+// protoField returns the first length-delimited field num of a protobuf message
+func protoField(msg []byte, num uint64) ([]byte, bool) {
+	var found []byte
+	ok := walkProto(msg, func(n, wire uint64, v uint64, b []byte) bool {
+		if n == num && wire == 2 {
+			found = b
+			return false
+		}
+		return true
+	})
+	return found, ok && found != nil
+}
+
+// protoVarint returns the first varint field num of a protobuf message
+func protoVarint(msg []byte, num uint64) (uint64, bool) {
+	var found uint64
+	hit := false
+	ok := walkProto(msg, func(n, wire uint64, v uint64, b []byte) bool {
+		if n == num && wire == 0 {
+			found, hit = v, true
+			return false
+		}
+		return true
+	})
+	return found, ok && hit
+}
+
+// walkProto calls fn for each field of a protobuf message until fn returns false; it reports
+// whether the message could be decoded up to that point
+func walkProto(msg []byte, fn func(num, wire, v uint64, b []byte) bool) bool {
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return false
+		}
+		msg = msg[n:]
+		num, wire := key>>3, key&7
+		var v uint64
+		var b []byte
+		switch wire {
+		case 0:
+			v, n = binary.Uvarint(msg)
+			if n <= 0 {
+				return false
+			}
+			msg = msg[n:]
+		case 1:
+			if len(msg) < 8 {
+				return false
+			}
+			v, msg = binary.LittleEndian.Uint64(msg), msg[8:]
+		case 2:
+			l, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < l {
+				return false
+			}
+			b, msg = msg[n:n+int(l)], msg[n+int(l):]
+		case 5:
+			if len(msg) < 4 {
+				return false
+			}
+			v, msg = uint64(binary.LittleEndian.Uint32(msg)), msg[4:]
+		default:
+			return false
+		}
+		if !fn(num, wire, v, b) {
+			return true
+		}
+	}
+	return true
+}
+
+// checkpointAgentCollector reports, at scrape time, the disk space taken under the node's
+// checkpoint directory and how long ago each StatefulMigration was last checkpointed on the node
+type checkpointAgentCollector struct {
+	reader   client.Reader
+	nodeName string
+	basePath string
+}
+
+func (c *checkpointAgentCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- checkpointDiskUsageDesc
+	ch <- checkpointLastSuccessAgeDesc
+}
+
+func (c *checkpointAgentCollector) Collect(ch chan<- prometheus.Metric) {
+	var used int64
+	_ = filepath.WalkDir(c.basePath, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				used += info.Size()
+			}
+		}
+		return nil
+	})
+	ch <- prometheus.MustNewConstMetric(checkpointDiskUsageDesc, prometheus.GaugeValue, float64(used), c.nodeName, c.basePath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var backups migrationv1.CheckpointBackupList
+	if err := c.reader.List(ctx, &backups); err != nil {
+		return
+	}
+	// 같은 StatefulMigration의 백업 중 가장 최근 성공 시각
+	latest := map[[2]string]time.Time{}
+	for i := range backups.Items {
+		b := &backups.Items[i]
+		sm := b.Labels["stateful-migration"]
+		if sm == "" {
+			continue
+		}
+		key := [2]string{b.Namespace, sm}
+		if t := lastSuccessfulCheckpoint(&b.Status); t.After(latest[key]) {
+			latest[key] = t
+		}
+	}
+	now := time.Now()
+	for key, t := range latest {
+		ch <- prometheus.MustNewConstMetric(checkpointLastSuccessAgeDesc, prometheus.GaugeValue, now.Sub(t).Seconds(), key[0], key[1])
+	}
+}
+
+// lastSuccessfulCheckpoint returns when the backup last completed a checkpoint: its last
+// checkpoint time or the newest image it built, whichever is later
+func lastSuccessfulCheckpoint(status *migrationv1.CheckpointBackupStatus) time.Time {
+	var t time.Time
+	if status.LastCheckpointTime != nil {
+		t = status.LastCheckpointTime.Time
+	}
+	for _, img := range status.BuiltImages {
+		if img.BuildTime != nil && img.BuildTime.After(t) {
+			t = img.BuildTime.Time
+		}
+	}
+	return t
+}
+
+// restoreCollector counts, at scrape time, the ResourceBindings the restore controller holds
+// suspended, from its cache of the Karmada control plane
+type restoreCollector struct {
+	reader client.Reader
+}
+
+func (c *restoreCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- restoresDesc
+}
+
+func (c *restoreCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rbList := &unstructured.UnstructuredList{}
+	rbList.SetGroupVersionKind(newResourceBindingU().GroupVersionKind())
+	if err := c.reader.List(ctx, rbList); err != nil {
+		return
+	}
+	pending, stuck := 0, 0
+	for i := range rbList.Items {
+		rb := &rbList.Items[i]
+		if !isRBSuspendedU(rb) {
+			continue
+		}
+		switch getRBAnnotation(rb, AnnoRestorePhase) {
+		case "working", "pending":
+			pending++
+		case "failed":
+			// 롤백되지 않은 실패: dispatching이 멈춘 채 남음
+			stuck++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(restoresDesc, prometheus.GaugeValue, float64(pending), "pending")
+	ch <- prometheus.MustNewConstMetric(restoresDesc, prometheus.GaugeValue, float64(stuck), "stuck")
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"archive/tar"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// protoKey appends the key of field num with the given wire type
+func protoKey(b []byte, num, wire uint64) []byte {
+	return binary.AppendUvarint(b, num<<3|wire)
+}
+
+func protoVarintField(b []byte, num, v uint64) []byte {
+	return binary.AppendUvarint(protoKey(b, num, 0), v)
+}
+
+func protoBytesField(b []byte, num uint64, v []byte) []byte {
+	b = binary.AppendUvarint(protoKey(b, num, 2), uint64(len(v)))
+	return append(b, v...)
+}
+
+// statsDump builds a CRIU stats-dump image holding entry
+func statsDump(service, stats uint32, entry []byte) []byte {
+	var b []byte
+	b = binary.LittleEndian.AppendUint32(b, service)
+	b = binary.LittleEndian.AppendUint32(b, stats)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(entry)))
+	return append(b, entry...)
+}
+
+// writeArchive writes a checkpoint archive with the given entries, in order
+func writeArchive(t *testing.T, entries [][2]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "checkpoint.tar")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	for _, e := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: e[0], Mode: 0o600, Size: int64(len(e[1]))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestArchiveFreezeTime(t *testing.T) {
+	// dump_stats_entry: freezing_time (1), frozen_time (2), memdump_time (3), pages_scanned (5)
+	var dump []byte
+	dump = protoVarintField(dump, 1, 1200)
+	dump = protoVarintField(dump, 2, 250000)
+	dump = protoVarintField(dump, 3, 9000)
+	dump = protoVarintField(dump, 5, 4096)
+	valid := protoBytesField(nil, 1, dump)
+
+	tests := []struct {
+		name    string
+		entries [][2]string
+		want    time.Duration
+		wantErr string
+	}{
+		{
+			name: "frozen_time after other images",
+			entries: [][2]string{
+				{"config.dump", "{}"},
+				{"checkpoint/pages-1.img", strings.Repeat("\x00", 64<<10)},
+				{"checkpoint/stats-dump", string(statsDump(criuServiceMagic, criuStatsMagic, valid))},
+			},
+			want: 250 * time.Millisecond,
+		},
+		{
+			name: "restore statistics before the dump statistics",
+			entries: [][2]string{
+				{"stats-dump", string(statsDump(criuServiceMagic, criuStatsMagic,
+					protoBytesField(protoBytesField(nil, 2, protoVarintField(nil, 2, 7)), 1, dump)))},
+			},
+			want: 250 * time.Millisecond,
+		},
+		{
+			name:    "no stats-dump",
+			entries: [][2]string{{"checkpoint/pages-1.img", "data"}},
+			wantErr: "stats-dump not found",
+		},
+		{
+			name:    "unknown magic",
+			entries: [][2]string{{"checkpoint/stats-dump", string(statsDump(criuServiceMagic, 0x12345678, valid))}},
+			wantErr: "unknown magic",
+		},
+		{
+			name:    "oversized entry",
+			entries: [][2]string{{"checkpoint/stats-dump", string(statsDump(criuServiceMagic, criuStatsMagic, make([]byte, 1<<16+1)))}},
+			wantErr: "too large",
+		},
+		{
+			name: "no dump statistics",
+			entries: [][2]string{{"checkpoint/stats-dump", string(statsDump(criuServiceMagic, criuStatsMagic,
+				protoBytesField(nil, 2, protoVarintField(nil, 2, 7))))}},
+			wantErr: "no dump statistics",
+		},
+		{
+			name: "no frozen_time",
+			entries: [][2]string{{"checkpoint/stats-dump", string(statsDump(criuServiceMagic, criuStatsMagic,
+				protoBytesField(nil, 1, protoVarintField(nil, 1, 1200))))}},
+			wantErr: "no frozen_time",
+		},
+		{
+			name:    "truncated entry",
+			entries: [][2]string{{"checkpoint/stats-dump", string(statsDump(criuServiceMagic, criuStatsMagic, valid)[:16])}},
+			wantErr: "EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := archiveFreezeTime(writeArchive(t, tt.entries))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("archiveFreezeTime: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("freeze time = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkProto(t *testing.T) {
+	type field struct {
+		num, wire, v uint64
+		b            string
+	}
+	var msg []byte
+	msg = protoVarintField(msg, 1, 300)
+	msg = binary.LittleEndian.AppendUint64(protoKey(msg, 2, 1), 1<<40)
+	msg = protoBytesField(msg, 3, []byte("abc"))
+	msg = binary.LittleEndian.AppendUint32(protoKey(msg, 4, 5), 7)
+	msg = protoVarintField(msg, 1000, 1)
+
+	tests := []struct {
+		name   string
+		msg    []byte
+		stopAt uint64
+		want   []field
+		wantOK bool
+	}{
+		{
+			name: "every wire type",
+			msg:  msg,
+			want: []field{
+				{num: 1, wire: 0, v: 300},
+				{num: 2, wire: 1, v: 1 << 40},
+				{num: 3, wire: 2, b: "abc"},
+				{num: 4, wire: 5, v: 7},
+				{num: 1000, wire: 0, v: 1},
+			},
+			wantOK: true,
+		},
+		{
+			name:   "stops when fn returns false",
+			msg:    msg,
+			stopAt: 2,
+			want:   []field{{num: 1, wire: 0, v: 300}, {num: 2, wire: 1, v: 1 << 40}},
+			wantOK: true,
+		},
+		{
+			name:   "empty message",
+			wantOK: true,
+		},
+		{
+			name:   "truncated varint",
+			msg:    append(protoVarintField(nil, 1, 1), 0x10, 0x80),
+			want:   []field{{num: 1, wire: 0, v: 1}},
+			wantOK: false,
+		},
+		{
+			name:   "length past the end",
+			msg:    append(protoKey(nil, 3, 2), 10, 'a'),
+			wantOK: false,
+		},
+		{
+			name:   "truncated fixed64",
+			msg:    append(protoKey(nil, 2, 1), 1, 2, 3),
+			wantOK: false,
+		},
+		{
+			name:   "group wire type",
+			msg:    protoKey(nil, 5, 3),
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []field
+			ok := walkProto(tt.msg, func(num, wire, v uint64, b []byte) bool {
+				got = append(got, field{num: num, wire: wire, v: v, b: string(b)})
+				return num != tt.stopAt
+			})
+			if ok != tt.wantOK {
+				t.Errorf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fields = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -22,16 +22,22 @@ import (
         "time"
 
         corev1 "k8s.io/api/core/v1"
+        "k8s.io/apimachinery/pkg/api/equality"
         apierrors "k8s.io/apimachinery/pkg/api/errors"
         metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
         "k8s.io/apimachinery/pkg/labels"
         "k8s.io/apimachinery/pkg/runtime"
         "k8s.io/apimachinery/pkg/types"
+        "k8s.io/client-go/util/retry"
 
         ctrl "sigs.k8s.io/controller-runtime"
+        "sigs.k8s.io/controller-runtime/pkg/builder"
         "sigs.k8s.io/controller-runtime/pkg/client"
         "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+        "sigs.k8s.io/controller-runtime/pkg/handler"
         logf "sigs.k8s.io/controller-runtime/pkg/log"
+        "sigs.k8s.io/controller-runtime/pkg/predicate"
+        "sigs.k8s.io/controller-runtime/pkg/source"
 
         karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
         migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
@@ -45,6 +51,14 @@ const (
         MigrationBackupFinalizer = "migrationbackup.migration.dcnlab.com/finalizer"
 
         maxOrdinalsToProbe = 64
+
+        // Per-cluster backup phases reported in StatefulMigration status.
+        ClusterBackupPhaseReady  = "Ready"
+        ClusterBackupPhaseFailed = "Failed"
+
+        // Pod and workload changes on member clusters trigger reconciles through
+        // MemberClusterWatcher; the periodic resync only catches missed events.
+        BackupResyncInterval = 30 * time.Minute
 )
 
 // MigrationBackupReconciler reconciles a StatefulMigration object.
@@ -53,6 +67,9 @@ type MigrationBackupReconciler struct {
         Scheme              *runtime.Scheme
         KarmadaClient       *KarmadaClient
         MemberClusterClient *MemberClusterClient
+
+        // Watcher enqueues StatefulMigrations when their pods or workload change on a member cluster
+        Watcher *MemberClusterWatcher
 }
 
 // +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations,verbs=get;list;watch;create;update;patch;delete
@@ -125,61 +142,136 @@ func (r *MigrationBackupReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 func (r *MigrationBackupReconciler) reconcileNormal(ctx context.Context, sm *migrationv1.StatefulMigration) (ctrl.Result, error) {
         log := logf.FromContext(ctx)
 
-        // A. 타깃 클러스터 결정
-        targetCluster, err := r.determineTargetCluster(sm)
+        // MigrationRun이 워크로드를 옮기는 동안에는 백업 스펙 갱신/고아 정리를 멈춤
+        if run := sm.Annotations[AnnoMigrationRun]; run != "" {
+                log.Info("MigrationRun in progress; skipping backup reconcile", "name", sm.Name, "migrationRun", run)
+                return ctrl.Result{}, nil
+        }
+
+        // A. 소스 클러스터 목록 결정
+        clusters, err := r.determineSourceClusters(ctx, sm)
         if err != nil {
-                log.Error(err, "cannot determine target cluster")
+                log.Error(err, "cannot determine source clusters")
                 return ctrl.Result{}, err
         }
         if r.MemberClusterClient == nil {
                 return ctrl.Result{}, fmt.Errorf("member cluster client not initialized")
         }
 
-        // B. 멤버(타깃)에서 대상 리소스 라벨링
-        if err := r.addLabelToTargetResource(ctx, sm, targetCluster); err != nil {
-                log.Error(err, "Failed to label target resource on member", "cluster", targetCluster)
+        // B. Karmada에 네임스페이스 보장 + 모든 소스 클러스터로 전파
+        if err := r.ensureStatefulMigrationNamespace(ctx, sm, clusters); err != nil {
+                log.Error(err, "Failed to ensure stateful-migration namespace")
                 return ctrl.Result{}, err
         }
 
-        // C. 멤버(타깃)에서 관련 Pod 수집
-        pods, err := r.getPodsFromResourceRef(ctx, sm, targetCluster)
-        if err != nil {
-                log.Error(err, "Failed to get pods from member", "cluster", targetCluster)
-                return ctrl.Result{}, err
+        // C. 클러스터별 처리: 한 클러스터의 실패가 나머지 클러스터를 막지 않음
+        podsByCluster := make(map[string][]corev1.Pod, len(clusters))
+        podSelectors := make(map[string]string, len(clusters))
+        statuses := make([]migrationv1.ClusterBackupStatus, 0, len(clusters))
+        failed := 0
+        for _, cluster := range clusters {
+                now := metav1.Now()
+                st := migrationv1.ClusterBackupStatus{Name: cluster, LastReconcileTime: &now}
+
+                pods, selector, err := r.reconcileSourceCluster(ctx, sm, cluster)
+                // 라벨로 찾지 않은 Pod(OwnerReference)는 네임스페이스 전체를 감시
+                if selector != nil {
+                        podSelectors[cluster] = selector.String()
+                } else if err == nil {
+                        podSelectors[cluster] = ""
+                }
+                if err != nil {
+                        failed++
+                        log.Error(err, "Failed to reconcile source cluster", "cluster", cluster)
+                        st.Phase = ClusterBackupPhaseFailed
+                        st.Message = err.Error()
+                } else {
+                        podsByCluster[cluster] = pods
+                        st.Phase = ClusterBackupPhaseReady
+                        st.BackupCount = int32(len(pods))
+                        st.Message = fmt.Sprintf("%d pod(s) protected", len(pods))
+                }
+                statuses = append(statuses, st)
         }
 
-        // D. Karmada에 네임스페이스 보장 + 타깃 단일 클러스터 전파
-        if err := r.ensureStatefulMigrationNamespace(ctx, sm, targetCluster); err != nil {
-                log.Error(err, "Failed to ensure stateful-migration namespace")
+        // D. 멤버 클러스터의 Pod/워크로드 변경을 감시(스케일업, Pod 교체 시 즉시 재조정)
+        if r.Watcher != nil {
+                if err := r.Watcher.Sync(r.MemberClusterClient, client.ObjectKeyFromObject(sm), memberWatchKeysFor(sm, clusters, podSelectors)); err != nil {
+                        // 감시 실패 시에도 주기적 재조정으로 계속 동작
+                        log.Info("Failed to watch member clusters", "error", err.Error())
+                }
+        }
+
+        // E. 고아 CheckpointBackup 정리(Karmada에서)
+        if err := r.cleanupOrphanedCheckpointBackups(ctx, sm, clusters, podsByCluster); err != nil {
+                log.Error(err, "Failed to cleanup orphaned CheckpointBackups")
                 return ctrl.Result{}, err
         }
 
-        // E. 타깃 클러스터에 CheckpointBackup CRD 보장
-        if err := r.MemberClusterClient.EnsureCRD(ctx, targetCluster); err != nil {
-                log.Error(err, "Failed to ensure CheckpointBackup CRD on cluster", "cluster", targetCluster)
+        // F. 클러스터별 상태 기록
+        if err := r.updateSourceClusterStatus(ctx, sm, statuses); err != nil {
+                log.Error(err, "Failed to update StatefulMigration status")
                 return ctrl.Result{}, err
         }
 
-        // F. 각 Pod에 대해 CheckpointBackup + PropagationPolicy(Placement=타깃 클러스터)
-        for _, pod := range pods {
-                if err := r.reconcileCheckpointBackupForPod(ctx, sm, &pod, targetCluster); err != nil {
-                        log.Error(err, "Failed to reconcile CheckpointBackup", "pod", pod.Name, "cluster", targetCluster)
-                        return ctrl.Result{}, err
-                }
+        if failed > 0 {
+                log.Info("Reconciled StatefulMigration with failed clusters", "name", sm.Name, "clusters", clusters, "failed", failed)
+                return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
         }
+        log.Info("Reconciled StatefulMigration", "name", sm.Name, "clusters", clusters)
+        return ctrl.Result{RequeueAfter: BackupResyncInterval}, nil
+}
 
-        // G. 고아 CheckpointBackup 정리(Karmada에서)
-        if err := r.cleanupOrphanedCheckpointBackups(ctx, sm, pods); err != nil {
-                log.Error(err, "Failed to cleanup orphaned CheckpointBackups")
-                return ctrl.Result{}, err
+// reconcileSourceCluster protects every pod of the workload on a single source cluster and returns those
+// pods and the label selector they were found by, if any.
+func (r *MigrationBackupReconciler) reconcileSourceCluster(ctx context.Context, sm *migrationv1.StatefulMigration, cluster string) ([]corev1.Pod, labels.Selector, error) {
+        // 1. 멤버에서 대상 리소스 라벨링
+        if err := r.addLabelToTargetResource(ctx, sm, cluster); err != nil {
+                return nil, nil, fmt.Errorf("label target resource: %w", err)
+        }
+
+        // 2. 멤버에서 관련 Pod 수집
+        pods, selector, err := r.getPodsFromResourceRef(ctx, sm, cluster)
+        if err != nil {
+                return nil, nil, fmt.Errorf("get pods: %w", err)
+        }
+
+        // 3. 멤버에 네임스페이스 + CheckpointBackup CRD 보장
+        if err := r.MemberClusterClient.EnsureNamespace(ctx, cluster, sm.Namespace); err != nil {
+                return nil, selector, err
+        }
+        if err := r.MemberClusterClient.EnsureCRD(ctx, cluster); err != nil {
+                return nil, selector, fmt.Errorf("ensure CheckpointBackup CRD: %w", err)
         }
 
-        log.Info("Reconciled StatefulMigration", "name", sm.Name, "targetCluster", targetCluster)
-        return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+        // 4. 각 Pod에 대해 CheckpointBackup + PropagationPolicy(Placement=해당 클러스터)
+        for i := range pods {
+                if err := r.reconcileCheckpointBackupForPod(ctx, sm, &pods[i], cluster); err != nil {
+                        return nil, selector, fmt.Errorf("reconcile CheckpointBackup for pod %s: %w", pods[i].Name, err)
+                }
+        }
+        return pods, selector, nil
+}
+
+// updateSourceClusterStatus records the per-cluster backup state on the StatefulMigration.
+func (r *MigrationBackupReconciler) updateSourceClusterStatus(ctx context.Context, sm *migrationv1.StatefulMigration, statuses []migrationv1.ClusterBackupStatus) error {
+        return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+                var latest migrationv1.StatefulMigration
+                if err := r.Get(ctx, client.ObjectKeyFromObject(sm), &latest); err != nil {
+                        return err
+                }
+                latest.Status.ObservedGeneration = latest.Generation
+                latest.Status.SourceClusters = statuses
+                if err := r.Status().Update(ctx, &latest); err != nil {
+                        return err
+                }
+                sm.Status = latest.Status
+                return nil
+        })
 }
 
 // ensureStatefulMigrationNamespace ensures the stateful-migration namespace exists on Karmada and is propagated to member clusters.
-func (r *MigrationBackupReconciler) ensureStatefulMigrationNamespace(ctx context.Context, sm *migrationv1.StatefulMigration, targetCluster string) error {
+func (r *MigrationBackupReconciler) ensureStatefulMigrationNamespace(ctx context.Context, sm *migrationv1.StatefulMigration, clusters []string) error {
         log := logf.FromContext(ctx)
         namespaceName := sm.Namespace
         if namespaceName == "" {
@@ -214,10 +306,10 @@ func (r *MigrationBackupReconciler) ensureStatefulMigrationNamespace(ctx context
                 log.Info("Namespace already exists on Karmada", "namespace", namespaceName)
         }
 
-        return r.ensureNamespacePropagationPolicy(ctx, namespaceName, targetCluster)
+        return r.ensureNamespacePropagationPolicy(ctx, namespaceName, clusters)
 }
 
-func (r *MigrationBackupReconciler) ensureNamespacePropagationPolicy(ctx context.Context, namespaceName, targetCluster string) error {
+func (r *MigrationBackupReconciler) ensureNamespacePropagationPolicy(ctx context.Context, namespaceName string, clusters []string) error {
         log := logf.FromContext(ctx)
         policyName := namespaceName + "-propagation"
 
@@ -245,7 +337,7 @@ func (r *MigrationBackupReconciler) ensureNamespacePropagationPolicy(ctx context
                                 }},
                                 Placement: karmadav1alpha1.Placement{
                                         ClusterAffinity: &karmadav1alpha1.ClusterAffinity{
-                                                ClusterNames: []string{targetCluster},
+                                                ClusterNames: clusters,
                                         },
                                 },
                         },
@@ -256,17 +348,16 @@ func (r *MigrationBackupReconciler) ensureNamespacePropagationPolicy(ctx context
                 return fmt.Errorf("failed to check PropagationPolicy %s: %w", policyName, err)
         }
 
-        // 대상 클러스터가 다르면 업데이트
+        // 대상 클러스터 집합이 다르면 업데이트
         needUpdate := true
         if existingPolicy.Spec.Placement.ClusterAffinity != nil {
-                names := existingPolicy.Spec.Placement.ClusterAffinity.ClusterNames
-                if len(names) == 1 && names[0] == targetCluster {
+                if stringSetsEqual(existingPolicy.Spec.Placement.ClusterAffinity.ClusterNames, clusters) {
                         needUpdate = false
                 }
         }
         if needUpdate {
                 existingPolicy.Spec.Placement.ClusterAffinity = &karmadav1alpha1.ClusterAffinity{
-                        ClusterNames: []string{targetCluster},
+                        ClusterNames: clusters,
                 }
                 return r.KarmadaClient.Update(ctx, existingPolicy)
         }
@@ -277,10 +368,16 @@ func (r *MigrationBackupReconciler) ensureNamespacePropagationPolicy(ctx context
 func (r *MigrationBackupReconciler) reconcileDelete(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) (ctrl.Result, error) {
         log := logf.FromContext(ctx)
 
-        // 타깃 클러스터 결정(실패해도 무시하고 계속)
-        targetCluster, _ := r.determineTargetCluster(statefulMigration)
-        if targetCluster != "" {
-                _ = r.removeLabelFromTargetResource(ctx, statefulMigration, targetCluster)
+        if r.Watcher != nil {
+                r.Watcher.Forget(client.ObjectKeyFromObject(statefulMigration))
+        }
+
+        // 소스 클러스터별 라벨 제거(실패해도 무시하고 계속)
+        clusters, _ := r.determineSourceClusters(ctx, statefulMigration)
+        for _, cluster := range clusters {
+                if err := r.removeLabelFromTargetResource(ctx, statefulMigration, cluster); err != nil {
+                        log.Info("Failed to remove label from target resource", "cluster", cluster, "error", err.Error())
+                }
         }
 
         // Delete all related CheckpointBackup resources.
@@ -289,6 +386,12 @@ func (r *MigrationBackupReconciler) reconcileDelete(ctx context.Context, statefu
                 return ctrl.Result{}, err
         }
 
+        // Delete CheckpointRestores and the restore PropagationPolicy.
+        if err := deleteRestoreArtifacts(ctx, r.KarmadaClient, statefulMigration); err != nil {
+                log.Error(err, "Failed to delete CheckpointRestore resources")
+                return ctrl.Result{}, err
+        }
+
         // Remove finalizer.
         controllerutil.RemoveFinalizer(statefulMigration, MigrationBackupFinalizer)
         if err := r.Update(ctx, statefulMigration); err != nil {
@@ -350,7 +453,10 @@ func (r *MigrationBackupReconciler) addLabelToTargetResource(ctx context.Context
                 return nil
 
         default:
-                return fmt.Errorf("unsupported resource kind: %s", ref.Kind)
+                if sm.Spec.PodResolution == nil {
+                        return fmt.Errorf("unsupported resource kind: %s (set spec.podResolution for custom kinds)", ref.Kind)
+                }
+                return r.setCustomResourceLabel(ctx, cluster, ref, true)
         }
 }
 
@@ -396,55 +502,66 @@ func (r *MigrationBackupReconciler) removeLabelFromTargetResource(ctx context.Co
                 return nil
 
         default:
-                return fmt.Errorf("unsupported resource kind: %s", ref.Kind)
+                if sm.Spec.PodResolution == nil {
+                        return fmt.Errorf("unsupported resource kind: %s", ref.Kind)
+                }
+                return r.setCustomResourceLabel(ctx, cluster, ref, false)
         }
 }
 
-// getPodsFromResourceRef gets all pods related to the resource reference.
-func (r *MigrationBackupReconciler) getPodsFromResourceRef(ctx context.Context, sm *migrationv1.StatefulMigration, cluster string) ([]corev1.Pod, error) {
+// getPodsFromResourceRef gets all pods related to the resource reference and the label selector
+// they were listed by; the selector is nil when the pods are not found by labels.
+func (r *MigrationBackupReconciler) getPodsFromResourceRef(ctx context.Context, sm *migrationv1.StatefulMigration, cluster string) ([]corev1.Pod, labels.Selector, error) {
         if r.MemberClusterClient == nil {
-                return nil, fmt.Errorf("member cluster client not initialized")
+                return nil, nil, fmt.Errorf("member cluster client not initialized")
         }
         ref := sm.Spec.ResourceRef
 
+        // 선언된 PodResolution이 있으면 kind와 무관하게 우선 적용
+        if sm.Spec.PodResolution != nil {
+                return r.resolvePodsWithPodResolution(ctx, sm, cluster)
+        }
+
         switch strings.ToLower(ref.Kind) {
         case "statefulset":
                 sts, err := r.MemberClusterClient.GetStatefulSetFromCluster(ctx, cluster, ref.Namespace, ref.Name)
                 if err != nil {
-                        return nil, err
+                        return nil, nil, err
                 }
                 if sts.Spec.Selector == nil {
-                        return nil, fmt.Errorf("statefulset %s/%s has nil .spec.selector", ref.Namespace, ref.Name)
+                        return nil, nil, fmt.Errorf("statefulset %s/%s has nil .spec.selector", ref.Namespace, ref.Name)
                 }
                 sel, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
                 if err != nil {
-                        return nil, err
+                        return nil, nil, err
                 }
-                return r.MemberClusterClient.ListPodsBySelector(ctx, cluster, ref.Namespace, sel)
+                pods, err := r.MemberClusterClient.ListPodsBySelector(ctx, cluster, ref.Namespace, sel)
+                return pods, sel, err
 
         case "deployment":
                 dep, err := r.MemberClusterClient.GetDeploymentFromCluster(ctx, cluster, ref.Namespace, ref.Name)
                 if err != nil {
-                        return nil, err
+                        return nil, nil, err
                 }
                 if dep.Spec.Selector == nil {
-                        return nil, fmt.Errorf("deployment %s/%s has nil .spec.selector", ref.Namespace, ref.Name)
+                        return nil, nil, fmt.Errorf("deployment %s/%s has nil .spec.selector", ref.Namespace, ref.Name)
                 }
                 sel, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
                 if err != nil {
-                        return nil, err
+                        return nil, nil, err
                 }
-                return r.MemberClusterClient.ListPodsBySelector(ctx, cluster, ref.Namespace, sel)
+                pods, err := r.MemberClusterClient.ListPodsBySelector(ctx, cluster, ref.Namespace, sel)
+                return pods, sel, err
 
         case "pod":
                 pod, err := r.MemberClusterClient.GetPodFromCluster(ctx, cluster, ref.Namespace, ref.Name)
                 if err != nil {
-                        return nil, err
+                        return nil, nil, err
                 }
-                return []corev1.Pod{*pod}, nil
+                return []corev1.Pod{*pod}, nil, nil
 
         default:
-                return nil, fmt.Errorf("unsupported resource kind: %s", ref.Kind)
+                return nil, nil, fmt.Errorf("unsupported resource kind: %s (set spec.podResolution for custom kinds)", ref.Kind)
         }
 }
 
@@ -481,10 +598,19 @@ func (r *MigrationBackupReconciler) reconcileCheckpointBackupForPod(ctx context.
 			},
 		},
 		Spec: migrationv1.CheckpointBackupSpec{
-			Schedule: statefulMigration.Spec.Schedule,
+			Schedule:                statefulMigration.Spec.Schedule,
+			TimeZone:                statefulMigration.Spec.TimeZone,
+			Suspend:                 statefulMigration.Spec.Suspend,
+			CheckpointTimeout:       statefulMigration.Spec.CheckpointTimeout,
+			Priority:                statefulMigration.Spec.Priority,
+			StartingDeadlineSeconds: statefulMigration.Spec.StartingDeadlineSeconds,
+			ConcurrencyPolicy:       concurrencyPolicyOrDefault(statefulMigration.Spec.ConcurrencyPolicy),
+			Triggers:                statefulMigration.Spec.Triggers,
 			PodRef: migrationv1.PodRef{
-				Namespace: pod.Namespace,
-				Name:      pod.Name,
+				Namespace:    pod.Namespace,
+				Name:         pod.Name,
+				GenerateName: pod.GenerateName,
+				Labels:       pod.Labels,
 			},
 			ResourceRef: statefulMigration.Spec.ResourceRef,
 			Registry:    &statefulMigration.Spec.Registry,
@@ -496,6 +622,11 @@ func (r *MigrationBackupReconciler) reconcileCheckpointBackupForPod(ctx context.
 		backup.Labels = map[string]string{}
 	}
 	backup.Labels["stateful-migration"] = statefulMigration.Name
+	// Only the checkpoint agent of the pod's node watches the backup
+	if pod.Spec.NodeName != "" {
+		backup.Labels[LabelTargetNode] = pod.Spec.NodeName
+	}
+	setOwnerMetadata(backup, statefulMigration.Namespace, statefulMigration.Name, statefulMigration.UID)
 
 	// Create or update CheckpointBackup on Karmada control plane (not mgmt cluster)
 	if r.KarmadaClient == nil {
@@ -505,7 +636,7 @@ func (r *MigrationBackupReconciler) reconcileCheckpointBackupForPod(ctx context.
 	var existingBackup migrationv1.CheckpointBackup
 	err := r.KarmadaClient.Get(ctx, types.NamespacedName{Name: backupName, Namespace: statefulMigration.Namespace}, &existingBackup)
 
-	if errors.IsNotFound(err) {
+	if apierrors.IsNotFound(err) {
 		// Create new CheckpointBackup on Karmada control plane
 		log := logf.FromContext(ctx)
 		log.Info("Creating CheckpointBackup on Karmada", "name", backupName, "namespace", statefulMigration.Namespace, "cluster", cluster)
@@ -517,19 +648,43 @@ func (r *MigrationBackupReconciler) reconcileCheckpointBackupForPod(ctx context.
 		return fmt.Errorf("failed to check CheckpointBackup on Karmada: %w", err)
 	} else {
 		// Update existing CheckpointBackup on Karmada control plane
-		log := logf.FromContext(ctx)
-		log.Info("Updating CheckpointBackup on Karmada", "name", backupName, "namespace", statefulMigration.Namespace)
-		existingBackup.Spec = backup.Spec
-		if err := r.KarmadaClient.Update(ctx, &existingBackup); err != nil {
-			return fmt.Errorf("failed to update CheckpointBackup on Karmada: %w", err)
+		updated := existingBackup.DeepCopy()
+		updated.Spec = backup.Spec
+		if updated.Labels == nil {
+			updated.Labels = map[string]string{}
+		}
+		for k, v := range backup.Labels {
+			updated.Labels[k] = v
+		}
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		for k, v := range backup.Annotations {
+			updated.Annotations[k] = v
+		}
+		// 변경이 없으면 갱신하지 않음 (멤버 Pod 이벤트마다 재조정되므로)
+		if !equality.Semantic.DeepEqual(&existingBackup, updated) {
+			log := logf.FromContext(ctx)
+			log.Info("Updating CheckpointBackup on Karmada", "name", backupName, "namespace", statefulMigration.Namespace)
+			if err := r.KarmadaClient.Update(ctx, updated); err != nil {
+				return fmt.Errorf("failed to update CheckpointBackup on Karmada: %w", err)
+			}
+			log.Info("Successfully updated CheckpointBackup on Karmada", "name", backupName)
 		}
-		log.Info("Successfully updated CheckpointBackup on Karmada", "name", backupName)
 	}
 
 	// Create Karmada PropagationPolicy to distribute CheckpointBackup to target cluster
 	return r.createOrUpdatePropagationPolicy(ctx, backup, cluster)
 }
 
+// concurrencyPolicyOrDefault applies the CheckpointBackup CRD default, so an unchanged spec compares equal
+func concurrencyPolicyOrDefault(policy migrationv1.ConcurrencyPolicy) migrationv1.ConcurrencyPolicy {
+	if policy == "" {
+		return migrationv1.ForbidConcurrent
+	}
+	return policy
+}
+
 // extractContainerInfo extracts container information from a pod.
 func (r *MigrationBackupReconciler) extractContainerInfo(pod *corev1.Pod, registry migrationv1.Registry) []migrationv1.Container {
         var containers []migrationv1.Container
@@ -578,12 +733,30 @@ func (r *MigrationBackupReconciler) createOrUpdatePropagationPolicy(
                         },
                 },
         }
+        if owner, uid, ok := ownerOf(backup); ok {
+                setOwnerMetadata(policy, owner.Namespace, owner.Name, uid)
+        }
 
         return r.KarmadaClient.CreateOrUpdatePropagationPolicy(ctx, policy)
 }
 
+// deleteCheckpointBackup deletes a CheckpointBackup and its PropagationPolicy from Karmada.
+func (r *MigrationBackupReconciler) deleteCheckpointBackup(ctx context.Context, backup *migrationv1.CheckpointBackup) error {
+        if err := r.KarmadaClient.Delete(ctx, backup); err != nil && !apierrors.IsNotFound(err) {
+                return fmt.Errorf("failed to delete CheckpointBackup %s/%s from Karmada: %w", backup.Namespace, backup.Name, err)
+        }
+        policy := &karmadav1alpha1.PropagationPolicy{
+                ObjectMeta: metav1.ObjectMeta{
+                        Name:      fmt.Sprintf("%s-policy", backup.Name),
+                        Namespace: backup.Namespace,
+                },
+        }
+        return r.KarmadaClient.DeletePropagationPolicy(ctx, policy)
+}
+
 // cleanupOrphanedCheckpointBackups removes CheckpointBackup resources that no longer have corresponding pods.
-func (r *MigrationBackupReconciler) cleanupOrphanedCheckpointBackups(ctx context.Context, sm *migrationv1.StatefulMigration, currentPods []corev1.Pod) error {
+// Backups of clusters that failed this round are kept; backups of clusters no longer listed as sources are removed.
+func (r *MigrationBackupReconciler) cleanupOrphanedCheckpointBackups(ctx context.Context, sm *migrationv1.StatefulMigration, clusters []string, podsByCluster map[string][]corev1.Pod) error {
         if r.KarmadaClient == nil {
                 return fmt.Errorf("Karmada client not initialized")
         }
@@ -597,16 +770,27 @@ func (r *MigrationBackupReconciler) cleanupOrphanedCheckpointBackups(ctx context
                 return fmt.Errorf("failed to list CheckpointBackup resources on Karmada: %w", err)
         }
 
-        currentPodNames := make(map[string]bool, len(currentPods))
-        for _, pod := range currentPods {
-                currentPodNames[pod.Name] = true
+        sourceClusters := make(map[string]bool, len(clusters))
+        for _, c := range clusters {
+                sourceClusters[c] = true
+        }
+        currentPods := make(map[string]bool)
+        for cluster, pods := range podsByCluster {
+                for _, pod := range pods {
+                        currentPods[cluster+"/"+pod.Name] = true
+                }
         }
 
         for _, backup := range backupList.Items {
+                cluster := backup.Labels["target-cluster"]
+                if _, reconciled := podsByCluster[cluster]; !reconciled && sourceClusters[cluster] {
+                        // 이번 라운드에 실패한 클러스터: 상태를 알 수 없으므로 유지
+                        continue
+                }
                 podName, exists := backup.Labels["target-pod"]
-                if !exists || !currentPodNames[podName] {
-                        if err := r.KarmadaClient.Delete(ctx, &backup); err != nil && !apierrors.IsNotFound(err) {
-                                return fmt.Errorf("failed to delete CheckpointBackup %s/%s from Karmada: %w", backup.Namespace, backup.Name, err)
+                if !exists || !currentPods[cluster+"/"+podName] {
+                        if err := r.deleteCheckpointBackup(ctx, &backup); err != nil {
+                                return err
                         }
                 }
         }
@@ -630,31 +814,136 @@ func (r *MigrationBackupReconciler) deleteAllCheckpointBackups(ctx context.Conte
         log := logf.FromContext(ctx)
         for _, backup := range backupList.Items {
                 log.Info("Deleting CheckpointBackup from Karmada", "name", backup.Name, "namespace", backup.Namespace)
-                if err := r.KarmadaClient.Delete(ctx, &backup); err != nil && !apierrors.IsNotFound(err) {
-                        return fmt.Errorf("failed to delete CheckpointBackup from Karmada: %w", err)
+                if err := r.deleteCheckpointBackup(ctx, &backup); err != nil {
+                        return err
                 }
         }
         return nil
 }
 
-// target-cluster 라벨 우선, 없으면 spec.sourceClusters[0] 폴백
-func (r *MigrationBackupReconciler) determineTargetCluster(sm *migrationv1.StatefulMigration) (string, error) {
+// deleteRestoreArtifacts deletes the CheckpointRestores and the restore PropagationPolicy created for the StatefulMigration.
+func deleteRestoreArtifacts(ctx context.Context, kc *KarmadaClient, sm *migrationv1.StatefulMigration) error {
+        if kc == nil {
+                return fmt.Errorf("Karmada client not initialized")
+        }
+        var restoreList migrationv1.CheckpointRestoreList
+        if err := kc.List(ctx, &restoreList, &client.ListOptions{
+                Namespace: sm.Namespace,
+                LabelSelector: labels.SelectorFromSet(map[string]string{
+                        LabelKeySM: sm.Name,
+                }),
+        }); err != nil {
+                return fmt.Errorf("failed to list CheckpointRestore resources on Karmada: %w", err)
+        }
+
+        log := logf.FromContext(ctx)
+        for i := range restoreList.Items {
+                restore := &restoreList.Items[i]
+                log.Info("Deleting CheckpointRestore from Karmada", "name", restore.Name, "namespace", restore.Namespace)
+                if err := kc.Delete(ctx, restore); err != nil && !apierrors.IsNotFound(err) {
+                        return fmt.Errorf("failed to delete CheckpointRestore from Karmada: %w", err)
+                }
+        }
+
+        policy := &karmadav1alpha1.PropagationPolicy{
+                ObjectMeta: metav1.ObjectMeta{
+                        Name:      sm.Name + restorePolicySuffix,
+                        Namespace: sm.Namespace,
+                },
+        }
+        return kc.DeletePropagationPolicy(ctx, policy)
+}
+
+// target-cluster 라벨이 있으면 해당 클러스터만, 없으면 spec.sourceClusters 전체(중복 제거)
+// sourceClusterDiscovery=ResourceBinding이면 워크로드 RB의 spec.clusters를 따름
+func (r *MigrationBackupReconciler) determineSourceClusters(ctx context.Context, sm *migrationv1.StatefulMigration) ([]string, error) {
         if sm.Labels != nil {
                 if c := sm.Labels["target-cluster"]; c != "" {
-                        return c, nil
+                        return []string{c}, nil
+                }
+        }
+
+        candidates := sm.Spec.SourceClusters
+        if sm.Spec.SourceClusterDiscovery == migrationv1.SourceClusterDiscoveryResourceBinding {
+                discovered, err := r.discoverSourceClustersFromRB(ctx, sm)
+                if err != nil {
+                        // RB 조회 실패 시 마지막으로 관측한 클러스터 유지
+                        last := observedSourceClusters(sm)
+                        if len(last) == 0 {
+                                return nil, fmt.Errorf("discover source clusters from ResourceBinding: %w", err)
+                        }
+                        logf.FromContext(ctx).Info("Using last observed source clusters", "reason", err.Error(), "clusters", last)
+                        discovered = last
+                }
+                candidates = discovered
+        }
+
+        seen := make(map[string]bool, len(candidates))
+        clusters := make([]string, 0, len(candidates))
+        for _, c := range candidates {
+                if c != "" && !seen[c] {
+                        seen[c] = true
+                        clusters = append(clusters, c)
+                }
+        }
+        if len(clusters) == 0 {
+                return nil, fmt.Errorf("source clusters not specified (metadata.labels['target-cluster'], spec.sourceClusters 또는 sourceClusterDiscovery=ResourceBinding 필요)")
+        }
+        return clusters, nil
+}
+
+// discoverSourceClustersFromRB returns the clusters the workload's ResourceBinding is scheduled to.
+// While dispatching is suspended (restore in progress) the workload has not moved yet, so the
+// last observed clusters are kept.
+func (r *MigrationBackupReconciler) discoverSourceClustersFromRB(ctx context.Context, sm *migrationv1.StatefulMigration) ([]string, error) {
+        if r.KarmadaClient == nil {
+                return nil, fmt.Errorf("Karmada client not initialized")
+        }
+        rb, err := findResourceBindingU(ctx, r.KarmadaClient, sm.Spec.ResourceRef)
+        if err != nil {
+                return nil, err
+        }
+        if isRBSuspendedU(rb) {
+                if last := observedSourceClusters(sm); len(last) > 0 {
+                        return last, nil
                 }
         }
-        if len(sm.Spec.SourceClusters) > 0 {
-                return sm.Spec.SourceClusters[0], nil
+        clusters, err := getRBClusterNamesU(rb)
+        if err != nil {
+                return nil, fmt.Errorf("parse RB clusters: %w", err)
+        }
+        if len(clusters) == 0 {
+                return nil, fmt.Errorf("ResourceBinding %s is not scheduled to any cluster yet", namespacedNameU(rb))
         }
-        return "", fmt.Errorf("target cluster not specified (metadata.labels['target-cluster'] 또는 spec.sourceClusters[0] 필요)")
+        return clusters, nil
+}
+
+// observedSourceClusters returns the source clusters recorded in status by the previous reconcile.
+func observedSourceClusters(sm *migrationv1.StatefulMigration) []string {
+        out := make([]string, 0, len(sm.Status.SourceClusters))
+        for _, st := range sm.Status.SourceClusters {
+                out = append(out, st.Name)
+        }
+        return out
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// CheckpointBackups live on Karmada, not in the manager's cluster, so they cannot be watched
+// through owner references; MigrationGarbageCollector takes care of orphans instead.
+// Pods and workloads live on member clusters and are watched through MemberClusterWatcher.
 func (r *MigrationBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+        if r.Watcher == nil {
+                r.Watcher = NewMemberClusterWatcher()
+        }
+        if err := mgr.Add(r.Watcher); err != nil {
+                return err
+        }
         return ctrl.NewControllerManagedBy(mgr).
-                For(&migrationv1.StatefulMigration{}).
-                Owns(&migrationv1.CheckpointBackup{}).
+                // Status is written by this and the restore controller; only spec changes need a reconcile,
+                // plus annotation changes so backups resume as soon as a MigrationRun releases the StatefulMigration
+                For(&migrationv1.StatefulMigration{}, builder.WithPredicates(predicate.Or(
+                        predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+                WatchesRawSource(source.Channel(r.Watcher.Events(), &handler.EnqueueRequestForObject{})).
                 Named("migrationbackup").
                 Complete(r)
 }
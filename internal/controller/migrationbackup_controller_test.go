@@ -17,7 +17,23 @@ limitations under the License.
 package controller
 
 import (
+	"context"
+	"testing"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	karmadaworkv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
 	. "github.com/onsi/ginkgo/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 )
 
 var _ = Describe("MigrationBackup Controller", func() {
@@ -30,3 +46,78 @@ var _ = Describe("MigrationBackup Controller", func() {
 		})
 	})
 })
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		migrationv1.AddToScheme,
+		karmadav1alpha1.AddToScheme,
+		karmadaworkv1alpha2.AddToScheme,
+		clusterv1alpha1.AddToScheme,
+	} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestReconcileCheckpointBackupForPodSkipsUnchangedBackups(t *testing.T) {
+	sm := &migrationv1.StatefulMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ns", UID: "sm-uid"},
+		Spec: migrationv1.StatefulMigrationSpec{
+			ResourceRef: migrationv1.ResourceRef{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "app", Name: "db"},
+			Schedule:    "*/5 * * * *",
+			Registry:    migrationv1.Registry{URL: "registry:5000", Repository: "ckpt"},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "app", Labels: map[string]string{"app": "db"}},
+		Spec: corev1.PodSpec{
+			NodeName:   "node-1",
+			Containers: []corev1.Container{{Name: "db", Image: "postgres:16"}},
+		},
+	}
+
+	updates := 0
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if _, ok := obj.(*migrationv1.CheckpointBackup); ok {
+				updates++
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+	r := &MigrationBackupReconciler{KarmadaClient: &KarmadaClient{Client: c}}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := r.reconcileCheckpointBackupForPod(ctx, sm, pod, "c1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if updates != 0 {
+		t.Fatalf("unchanged CheckpointBackup updated %d times", updates)
+	}
+
+	// 노드가 바뀌면 라벨 갱신
+	pod.Spec.NodeName = "node-2"
+	if err := r.reconcileCheckpointBackupForPod(ctx, sm, pod, "c1"); err != nil {
+		t.Fatal(err)
+	}
+	if updates != 1 {
+		t.Fatalf("changed CheckpointBackup updated %d times, want 1", updates)
+	}
+	var backup migrationv1.CheckpointBackup
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "db-db-0-c1"}, &backup); err != nil {
+		t.Fatal(err)
+	}
+	if got := backup.Labels[LabelTargetNode]; got != "node-2" {
+		t.Errorf("target node label = %q, want node-2", got)
+	}
+	if backup.Spec.ConcurrencyPolicy != migrationv1.ForbidConcurrent {
+		t.Errorf("concurrencyPolicy = %q, want the CRD default", backup.Spec.ConcurrencyPolicy)
+	}
+}
@@ -0,0 +1,505 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	apischema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+const (
+	// RB 어노테이션: 마지막으로 처리한 축출 작업 (<cluster>@<생성시각>); 그보다 앞선 작업은 다시 처리하지 않음
+	AnnoFailoverTask = "migration.dcnlab.com/failover-task"
+	// RB 어노테이션: 진행 중인 최종 체크포인트 세대와 시작 시각 (완료/대체 시 삭제)
+	AnnoFailoverGeneration = "migration.dcnlab.com/failover-generation"
+	AnnoFailoverStartedAt  = "migration.dcnlab.com/failover-started-at"
+
+	// 축출 시 만든 최종 체크포인트 CheckpointBackup 라벨
+	LabelFailoverCheckpoint = "migration.dcnlab.com/failover"
+
+	// 최종 체크포인트 대기 기본값 (spec.failover.checkpointTimeout으로 변경 가능)
+	DefaultFailoverCheckpointTimeout = 5 * time.Minute
+	// 진행 중인 최종 체크포인트 재확인 주기
+	FailoverCheckInterval = 5 * time.Second
+)
+
+// MigrationFailoverReconciler reacts to Karmada graceful evictions of protected workloads. It
+// suspends dispatching of the ResourceBinding, takes a final checkpoint on the evicted cluster
+// while it is reachable and tells the restore controller which checkpoint generation to use.
+type MigrationFailoverReconciler struct {
+	// 관리 클러스터 client (StatefulMigration 조회)
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Karmada control-plane client (RB/Backup 쓰기)
+	KarmadaClient *KarmadaClient
+
+	// 축출된 클러스터 연결 확인, 최종 체크포인트 상태 조회
+	MemberClusterClient *MemberClusterClient
+
+	// StatefulMigration에 failover 이벤트 기록
+	Recorder record.EventRecorder
+
+	// Karmada control-plane cache: RB/Cluster 감시
+	karmadaCluster cluster.Cluster
+}
+
+// evictionTask is a graceful eviction task of a ResourceBinding
+type evictionTask struct {
+	Cluster string
+	Reason  string
+	Created string
+}
+
+// key identifies the task in AnnoFailoverTask
+func (t evictionTask) key() string {
+	return t.Cluster + "@" + t.Created
+}
+
+// after orders tasks by creation time, then by cluster, so every task is either before or after another
+func (t evictionTask) after(o evictionTask) bool {
+	if t.Created != o.Created {
+		return t.Created > o.Created
+	}
+	return t.Cluster > o.Cluster
+}
+
+// parseEvictionTaskKey reads a task from its key in AnnoFailoverTask
+func parseEvictionTaskKey(key string) (evictionTask, bool) {
+	cluster, created, ok := strings.Cut(key, "@")
+	if !ok || cluster == "" {
+		return evictionTask{}, false
+	}
+	return evictionTask{Cluster: cluster, Created: created}, true
+}
+
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.karmada.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile handles a single ResourceBinding with graceful eviction tasks or a final checkpoint in progress
+func (r *MigrationFailoverReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	rb := newResourceBindingU()
+	if err := r.karmadaCluster.GetClient().Get(ctx, req.NamespacedName, rb); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !isFailoverCandidateRB(rb) {
+		return ctrl.Result{}, nil
+	}
+	sm, err := r.statefulMigrationFor(ctx, rb)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if sm == nil {
+		return ctrl.Result{}, nil
+	}
+
+	// 진행 중인 최종 체크포인트가 있으면 완료/대체 여부 확인
+	if gen := getRBAnnotation(rb, AnnoFailoverGeneration); gen != "" {
+		done, err := r.checkFinalCheckpoint(ctx, rb, sm, gen)
+		if err != nil || done {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: withJitter(FailoverCheckInterval, 0.2)}, nil
+	}
+
+	task, ok := nextEvictionTask(rb, sm)
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+	started, err := r.startFailover(ctx, rb, sm, task)
+	if err != nil || !started {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: withJitter(FailoverCheckInterval, 0.2)}, nil
+}
+
+// startFailover suspends dispatching and fires an immediate checkpoint on the evicted cluster.
+// When the cluster is unreachable, or has nothing to checkpoint, the restore falls back to the
+// newest pushed checkpoints right away. It reports whether a final checkpoint was started.
+func (r *MigrationFailoverReconciler) startFailover(ctx context.Context, rb *unstructured.Unstructured, sm *migrationv1.StatefulMigration, task evictionTask) (bool, error) {
+	lg := log.FromContext(ctx)
+	lg.Info("Workload evicted from source cluster", "rb", namespacedNameU(rb), "cluster", task.Cluster, "reason", task.Reason)
+
+	// 이전 축출에서 만든 최종 체크포인트 정리
+	if err := deleteFinalCheckpoints(ctx, r.KarmadaClient, sm.Namespace, map[string]string{
+		LabelOwnerName: sm.Name, LabelFailoverCheckpoint: "true",
+	}); err != nil {
+		return false, err
+	}
+
+	adds := map[string]string{AnnoFailoverTask: task.key()}
+	gen := fmt.Sprintf("failover-%s-%d", task.Cluster, time.Now().Unix())
+	var checkpoints []migrationv1.MigrationRunCheckpoint
+	reachErr := r.clusterReachable(ctx, task.Cluster)
+	if reachErr == nil {
+		var err error
+		checkpoints, err = createFinalCheckpoints(ctx, r.KarmadaClient, sm, []string{task.Cluster}, gen,
+			map[string]string{LabelCheckpointGeneration: gen, LabelFailoverCheckpoint: "true"}, false)
+		if err != nil {
+			return false, err
+		}
+	}
+	if len(checkpoints) > 0 {
+		// 최종 체크포인트가 끝날 때까지 복원 보류
+		adds[AnnoFailoverGeneration] = gen
+		adds[AnnoFailoverStartedAt] = time.Now().UTC().Format(time.RFC3339)
+		adds[AnnoRestorePhase] = "pending"
+		r.recordEvent(sm, corev1.EventTypeNormal, "FailoverCheckpoint",
+			"%s evicted from %s (%s); taking %d final checkpoint(s) as generation %s", rb.GetName(), task.Cluster, task.Reason, len(checkpoints), gen)
+	} else {
+		adds[AnnoRestoreGeneration] = CheckpointGenerationLatest
+		reason := "no protected pod to checkpoint"
+		if reachErr != nil {
+			reason = reachErr.Error()
+		}
+		r.recordEvent(sm, corev1.EventTypeWarning, "FailoverFallback",
+			"%s evicted from %s (%s); restoring the newest pushed checkpoints: %s", rb.GetName(), task.Cluster, task.Reason, reason)
+	}
+
+	// 이전 복원 표식 초기화 + dispatching 중단: 복원 컨트롤러가 새 클러스터로 복원
+	if err := r.updateRB(ctx, rb, func(fresh *unstructured.Unstructured) error {
+		ann := fresh.GetAnnotations()
+		if ann == nil {
+			ann = map[string]string{}
+		}
+		for _, k := range []string{AnnoRestorePhase, AnnoRestoreStartedAt, AnnoRestoreFailureReason, AnnoRestoreRolledBack,
+			AnnoRestoreGeneration, AnnoFailoverGeneration, AnnoFailoverStartedAt} {
+			delete(ann, k)
+		}
+		for k, v := range adds {
+			ann[k] = v
+		}
+		fresh.SetAnnotations(ann)
+		return unstructured.SetNestedField(fresh.Object, true, "spec", "suspension", "dispatching")
+	}); err != nil {
+		return false, fmt.Errorf("suspend ResourceBinding for failover: %w", err)
+	}
+	return len(checkpoints) > 0, nil
+}
+
+// checkFinalCheckpoint hands the final checkpoint generation to the restore controller once every
+// checkpoint is pushed. A failed checkpoint, an unreachable cluster or the checkpoint timeout
+// falls back to the newest pushed checkpoints. It reports whether the failover is settled.
+func (r *MigrationFailoverReconciler) checkFinalCheckpoint(ctx context.Context, rb *unstructured.Unstructured, sm *migrationv1.StatefulMigration, gen string) (bool, error) {
+	var backupList migrationv1.CheckpointBackupList
+	if err := r.KarmadaClient.List(ctx, &backupList, client.InNamespace(sm.Namespace), client.MatchingLabels{LabelCheckpointGeneration: gen}); err != nil {
+		return false, fmt.Errorf("list final CheckpointBackups: %w", err)
+	}
+	checkpoints := make([]migrationv1.MigrationRunCheckpoint, 0, len(backupList.Items))
+	for _, b := range backupList.Items {
+		checkpoints = append(checkpoints, migrationv1.MigrationRunCheckpoint{Name: b.Name, Cluster: b.Labels["target-cluster"], PodName: b.Labels["target-pod"]})
+	}
+
+	cluster := strings.SplitN(getRBAnnotation(rb, AnnoFailoverTask), "@", 2)[0]
+	done, err := finalCheckpointProgress(ctx, r.MemberClusterClient, sm.Namespace, checkpoints)
+	switch {
+	case err != nil:
+		return true, r.settleFailover(ctx, rb, sm, CheckpointGenerationLatest, err.Error())
+	case len(checkpoints) > 0 && done == len(checkpoints):
+		return true, r.settleFailover(ctx, rb, sm, gen, "")
+	case len(checkpoints) == 0:
+		return true, r.settleFailover(ctx, rb, sm, CheckpointGenerationLatest, "final CheckpointBackups of generation "+gen+" are gone")
+	}
+	if reachErr := r.clusterReachable(ctx, cluster); reachErr != nil {
+		return true, r.settleFailover(ctx, rb, sm, CheckpointGenerationLatest, reachErr.Error())
+	}
+	if t, err := time.Parse(time.RFC3339, getRBAnnotation(rb, AnnoFailoverStartedAt)); err == nil {
+		if timeout := failoverCheckpointTimeout(sm); time.Since(t) > timeout {
+			return true, r.settleFailover(ctx, rb, sm, CheckpointGenerationLatest,
+				fmt.Sprintf("final checkpoint did not complete within %s (%d/%d pushed)", timeout, done, len(checkpoints)))
+		}
+	}
+	return false, nil
+}
+
+// settleFailover releases the ResourceBinding to the restore controller with the generation to restore
+func (r *MigrationFailoverReconciler) settleFailover(ctx context.Context, rb *unstructured.Unstructured, sm *migrationv1.StatefulMigration, generation, fallbackReason string) error {
+	if err := r.updateRB(ctx, rb, func(fresh *unstructured.Unstructured) error {
+		ann := fresh.GetAnnotations()
+		if ann == nil {
+			ann = map[string]string{}
+		}
+		delete(ann, AnnoFailoverGeneration)
+		delete(ann, AnnoFailoverStartedAt)
+		if ann[AnnoRestorePhase] == "pending" {
+			delete(ann, AnnoRestorePhase)
+		}
+		ann[AnnoRestoreGeneration] = generation
+		fresh.SetAnnotations(ann)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("release ResourceBinding for restore: %w", err)
+	}
+	if fallbackReason != "" {
+		r.recordEvent(sm, corev1.EventTypeWarning, "FailoverFallback", "Restoring %s from the newest pushed checkpoints: %s", rb.GetName(), fallbackReason)
+	} else {
+		r.recordEvent(sm, corev1.EventTypeNormal, "FailoverCheckpointReady", "Restoring %s from final checkpoint generation %s", rb.GetName(), generation)
+	}
+	return nil
+}
+
+// clusterReachable reports why Karmada considers the cluster unreachable, or probes it through the proxy
+func (r *MigrationFailoverReconciler) clusterReachable(ctx context.Context, name string) error {
+	c := newClusterU()
+	if err := r.karmadaCluster.GetClient().Get(ctx, types.NamespacedName{Name: name}, c); err != nil {
+		return fmt.Errorf("get cluster %s: %w", name, err)
+	}
+	taints, _, _ := unstructured.NestedSlice(c.Object, "spec", "taints")
+	for _, t := range taints {
+		if m, ok := t.(map[string]interface{}); ok && m["key"] == clusterv1alpha1.TaintClusterUnreachable {
+			return fmt.Errorf("cluster %s is tainted %s", name, clusterv1alpha1.TaintClusterUnreachable)
+		}
+	}
+	if ready := clusterReadyStatusU(c); ready != "True" {
+		return fmt.Errorf("cluster %s is not ready (Ready=%s)", name, ready)
+	}
+	if r.MemberClusterClient == nil {
+		return fmt.Errorf("member cluster client not initialized")
+	}
+	return r.MemberClusterClient.TestClusterConnection(ctx, name)
+}
+
+// statefulMigrationFor returns the StatefulMigration with failover enabled that protects the RB's workload
+func (r *MigrationFailoverReconciler) statefulMigrationFor(ctx context.Context, rb *unstructured.Unstructured) (*migrationv1.StatefulMigration, error) {
+	resNS, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "namespace")
+	var smList migrationv1.StatefulMigrationList
+	if err := r.List(ctx, &smList, client.InNamespace(resNS), client.MatchingFields{IndexKeyResourceRef: rbResourceKeyU(rb)}); err != nil {
+		return nil, fmt.Errorf("list StatefulMigrations: %w", err)
+	}
+	sort.Slice(smList.Items, func(i, j int) bool { return smList.Items[i].Name < smList.Items[j].Name })
+	for i := range smList.Items {
+		sm := &smList.Items[i]
+		// MigrationRun이 진행 중인 SM은 run이 배치를 관리
+		if p := sm.Spec.Failover; p != nil && p.Enabled && sm.Annotations[AnnoMigrationRun] == "" {
+			return sm, nil
+		}
+	}
+	return nil, nil
+}
+
+// updateRB applies mutate to the latest version of the RB on Karmada
+func (r *MigrationFailoverReconciler) updateRB(ctx context.Context, rb *unstructured.Unstructured, mutate func(*unstructured.Unstructured) error) error {
+	key := types.NamespacedName{Namespace: rb.GetNamespace(), Name: rb.GetName()}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		fresh := newResourceBindingU()
+		if err := r.KarmadaClient.Get(ctx, key, fresh); err != nil {
+			return err
+		}
+		if err := mutate(fresh); err != nil {
+			return err
+		}
+		return r.KarmadaClient.Update(ctx, fresh)
+	})
+}
+
+// recordEvent records an event on the StatefulMigration when a recorder is configured
+func (r *MigrationFailoverReconciler) recordEvent(sm *migrationv1.StatefulMigration, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(sm, eventType, reason, messageFmt, args...)
+	}
+}
+
+// SetupWithManager watches ResourceBindings and Clusters through a cache on the Karmada control plane
+func (r *MigrationFailoverReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.KarmadaClient == nil {
+		return fmt.Errorf("Karmada client not initialized")
+	}
+	karmadaCluster, err := cluster.New(r.KarmadaClient.RESTConfig(), func(o *cluster.Options) {
+		o.Scheme = r.KarmadaClient.Scheme()
+		o.Client.Cache = &client.CacheOptions{Unstructured: true}
+	})
+	if err != nil {
+		return fmt.Errorf("create Karmada cluster cache: %w", err)
+	}
+	if err := mgr.Add(karmadaCluster); err != nil {
+		return err
+	}
+	r.karmadaCluster = karmadaCluster
+	if r.MemberClusterClient == nil {
+		memberClient, err := NewMemberClusterClient(r.KarmadaClient)
+		if err != nil {
+			return fmt.Errorf("create member cluster client: %w", err)
+		}
+		r.MemberClusterClient = memberClient
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("migrationfailover")
+	}
+	if err := indexStatefulMigrationsByResourceRef(context.Background(), mgr); err != nil {
+		return err
+	}
+
+	rbPredicate := predicate.NewTypedPredicateFuncs(func(rb *unstructured.Unstructured) bool {
+		return isFailoverCandidateRB(rb)
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("migrationfailover").
+		WatchesRawSource(source.Kind(karmadaCluster.GetCache(), newResourceBindingU(),
+			&handler.TypedEnqueueRequestForObject[*unstructured.Unstructured]{}, rbPredicate)).
+		WatchesRawSource(source.Kind(karmadaCluster.GetCache(), newClusterU(),
+			handler.TypedEnqueueRequestsFromMapFunc(r.resourceBindingsForCluster), clusterConditionPredicate())).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 1}).
+		Complete(r)
+}
+
+// clusterConditionPredicate passes Cluster updates that change its Ready condition or taints and
+// ignores heartbeats
+func clusterConditionPredicate() predicate.TypedPredicate[*unstructured.Unstructured] {
+	return predicate.TypedFuncs[*unstructured.Unstructured]{
+		UpdateFunc: func(e event.TypedUpdateEvent[*unstructured.Unstructured]) bool {
+			oldTaints, _, _ := unstructured.NestedSlice(e.ObjectOld.Object, "spec", "taints")
+			newTaints, _, _ := unstructured.NestedSlice(e.ObjectNew.Object, "spec", "taints")
+			return clusterReadyStatusU(e.ObjectOld) != clusterReadyStatusU(e.ObjectNew) ||
+				fmt.Sprintf("%v", oldTaints) != fmt.Sprintf("%v", newTaints)
+		},
+	}
+}
+
+// resourceBindingsForCluster maps a Karmada Cluster to the RBs being evicted from it
+func (r *MigrationFailoverReconciler) resourceBindingsForCluster(ctx context.Context, c *unstructured.Unstructured) []reconcile.Request {
+	rbList := &unstructured.UnstructuredList{}
+	rbList.SetGroupVersionKind(apischema.GroupVersionKind{
+		Group:   "work.karmada.io",
+		Version: "v1alpha2",
+		Kind:    "ResourceBindingList",
+	})
+	if err := r.karmadaCluster.GetClient().List(ctx, rbList); err != nil {
+		log.FromContext(ctx).Error(err, "list ResourceBindings for cluster", "cluster", c.GetName())
+		return nil
+	}
+	var out []reconcile.Request
+	for i := range rbList.Items {
+		rb := &rbList.Items[i]
+		if !isFailoverCandidateRB(rb) {
+			continue
+		}
+		if strings.HasPrefix(getRBAnnotation(rb, AnnoFailoverTask), c.GetName()+"@") {
+			out = append(out, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rb)})
+			continue
+		}
+		for _, t := range gracefulEvictionTasksU(rb) {
+			if t.Cluster == c.GetName() {
+				out = append(out, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rb)})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// isFailoverCandidateRB reports whether the RB is being evicted or waits for a final checkpoint
+func isFailoverCandidateRB(rb *unstructured.Unstructured) bool {
+	return getRBAnnotation(rb, AnnoFailoverGeneration) != "" || len(gracefulEvictionTasksU(rb)) > 0
+}
+
+// nextEvictionTask returns the newest eviction task from a source cluster that was created after
+// the last handled one. Older tasks still listed on the RB were superseded by it and are skipped,
+// so a settled failover is not started again for them.
+func nextEvictionTask(rb *unstructured.Unstructured, sm *migrationv1.StatefulMigration) (evictionTask, bool) {
+	sources := map[string]bool{}
+	for _, c := range effectiveSourceClusters(sm) {
+		sources[c] = true
+	}
+	handled, hasHandled := parseEvictionTaskKey(getRBAnnotation(rb, AnnoFailoverTask))
+	var next evictionTask
+	found := false
+	for _, t := range gracefulEvictionTasksU(rb) {
+		if !sources[t.Cluster] || (hasHandled && !t.after(handled)) {
+			continue
+		}
+		if !found || t.after(next) {
+			next, found = t, true
+		}
+	}
+	return next, found
+}
+
+// gracefulEvictionTasksU returns spec.gracefulEvictionTasks of the RB
+func gracefulEvictionTasksU(rb *unstructured.Unstructured) []evictionTask {
+	raw, _, _ := unstructured.NestedSlice(rb.Object, "spec", "gracefulEvictionTasks")
+	out := make([]evictionTask, 0, len(raw))
+	for _, it := range raw {
+		m, ok := it.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		t := evictionTask{}
+		t.Cluster, _, _ = unstructured.NestedString(m, "fromCluster")
+		t.Reason, _, _ = unstructured.NestedString(m, "reason")
+		t.Created, _, _ = unstructured.NestedString(m, "creationTimestamp")
+		if t.Cluster != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// failoverCheckpointTimeout returns how long to wait for the final checkpoint on eviction
+func failoverCheckpointTimeout(sm *migrationv1.StatefulMigration) time.Duration {
+	if p := sm.Spec.Failover; p != nil && p.CheckpointTimeout != nil && p.CheckpointTimeout.Duration > 0 {
+		return p.CheckpointTimeout.Duration
+	}
+	return DefaultFailoverCheckpointTimeout
+}
+
+// newClusterU returns an empty unstructured Karmada Cluster
+func newClusterU() *unstructured.Unstructured {
+	c := &unstructured.Unstructured{}
+	c.SetGroupVersionKind(apischema.GroupVersionKind{
+		Group:   "cluster.karmada.io",
+		Version: "v1alpha1",
+		Kind:    "Cluster",
+	})
+	return c
+}
+
+// clusterReadyStatusU returns the status of the Ready condition of a Karmada Cluster
+func clusterReadyStatusU(c *unstructured.Unstructured) string {
+	conds, _, _ := unstructured.NestedSlice(c.Object, "status", "conditions")
+	for _, it := range conds {
+		if m, ok := it.(map[string]interface{}); ok && m["type"] == clusterv1alpha1.ClusterConditionReady {
+			s, _ := m["status"].(string)
+			return s
+		}
+	}
+	return "Unknown"
+}
@@ -0,0 +1,349 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// fakeKarmadaCluster serves the Karmada cache of a controller from a fake client
+type fakeKarmadaCluster struct {
+	cluster.Cluster
+	c client.Client
+}
+
+func (f fakeKarmadaCluster) GetClient() client.Client { return f.c }
+
+// newEvictedRB returns a ResourceBinding with the graceful eviction tasks (cluster, creationTimestamp)
+func newEvictedRB(annotations map[string]string, tasks ...[2]string) *unstructured.Unstructured {
+	rb := newResourceBindingU()
+	rb.SetNamespace("app")
+	rb.SetName("db-statefulset")
+	rb.SetAnnotations(annotations)
+	var raw []interface{}
+	for _, t := range tasks {
+		raw = append(raw, map[string]interface{}{"fromCluster": t[0], "reason": "TaintUntolerated", "creationTimestamp": t[1]})
+	}
+	if raw != nil {
+		_ = unstructured.SetNestedSlice(rb.Object, raw, "spec", "gracefulEvictionTasks")
+	}
+	return rb
+}
+
+func TestGracefulEvictionTasksU(t *testing.T) {
+	rb := newResourceBindingU()
+	_ = unstructured.SetNestedSlice(rb.Object, []interface{}{
+		map[string]interface{}{"fromCluster": "member1", "reason": "TaintUntolerated", "creationTimestamp": "2026-01-01T00:00:00Z"},
+		map[string]interface{}{"reason": "ApplicationFailure"},
+		"not a task",
+		map[string]interface{}{"fromCluster": "member2"},
+	}, "spec", "gracefulEvictionTasks")
+
+	got := gracefulEvictionTasksU(rb)
+	want := []evictionTask{
+		{Cluster: "member1", Reason: "TaintUntolerated", Created: "2026-01-01T00:00:00Z"},
+		{Cluster: "member2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tasks = %+v, want %+v", got, want)
+	}
+	if got := gracefulEvictionTasksU(newResourceBindingU()); len(got) != 0 {
+		t.Errorf("tasks of an RB without evictions = %+v, want none", got)
+	}
+}
+
+func TestNextEvictionTask(t *testing.T) {
+	const (
+		t1 = "2026-01-01T00:00:00Z"
+		t2 = "2026-01-01T00:05:00Z"
+	)
+	sm := &migrationv1.StatefulMigration{Spec: migrationv1.StatefulMigrationSpec{SourceClusters: []string{"member1", "member2"}}}
+
+	tests := []struct {
+		name    string
+		handled string
+		tasks   [][2]string
+		want    string
+	}{
+		{
+			name:  "newest task from a source cluster",
+			tasks: [][2]string{{"member1", t1}, {"member2", t2}},
+			want:  "member2@" + t2,
+		},
+		{
+			name:  "tasks from other clusters are ignored",
+			tasks: [][2]string{{"member1", t1}, {"member3", t2}},
+			want:  "member1@" + t1,
+		},
+		{
+			name:  "same creation time is ordered by cluster",
+			tasks: [][2]string{{"member2", t1}, {"member1", t1}},
+			want:  "member2@" + t1,
+		},
+		{
+			name:    "handled task is not started again",
+			handled: "member1@" + t1,
+			tasks:   [][2]string{{"member1", t1}},
+		},
+		{
+			name:    "older task of another source cluster does not flip back",
+			handled: "member2@" + t2,
+			tasks:   [][2]string{{"member1", t1}, {"member2", t2}},
+		},
+		{
+			name:    "task created together with the handled one does not flip back",
+			handled: "member2@" + t1,
+			tasks:   [][2]string{{"member1", t1}, {"member2", t1}},
+		},
+		{
+			name:    "task newer than the handled one",
+			handled: "member1@" + t1,
+			tasks:   [][2]string{{"member1", t1}, {"member2", t2}},
+			want:    "member2@" + t2,
+		},
+		{
+			name:    "unparsable handled key is ignored",
+			handled: "garbage",
+			tasks:   [][2]string{{"member1", t1}},
+			want:    "member1@" + t1,
+		},
+		{
+			name: "no tasks",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := map[string]string{}
+			if tt.handled != "" {
+				ann[AnnoFailoverTask] = tt.handled
+			}
+			task, ok := nextEvictionTask(newEvictedRB(ann, tt.tasks...), sm)
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("next task = %s, want none", task.key())
+				}
+				return
+			}
+			if !ok || task.key() != tt.want {
+				t.Fatalf("next task = %s (found %v), want %s", task.key(), ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFinalCheckpoint(t *testing.T) {
+	const gen = "failover-member1-1"
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		phases    []string // 멤버 클러스터의 최종 체크포인트 상태, "" = 아직 전파 안 됨
+		noBackups bool
+		cluster   clusterv1alpha1.Cluster
+		down      bool
+		startedAt time.Duration // 경과 시간
+		timeout   time.Duration
+
+		wantDone   bool
+		wantGen    string
+		wantReason string
+		wantEvent  string
+	}{
+		{
+			name:      "every final checkpoint pushed",
+			phases:    []string{PhaseCompleted, PhaseCompletedPodDeleted},
+			wantDone:  true,
+			wantGen:   gen,
+			wantEvent: "FailoverCheckpointReady",
+		},
+		{
+			name:       "failed final checkpoint falls back",
+			phases:     []string{PhaseCompleted, PhaseFailed},
+			wantDone:   true,
+			wantGen:    CheckpointGenerationLatest,
+			wantReason: "Failed",
+			wantEvent:  "FailoverFallback",
+		},
+		{
+			name:       "final checkpoints deleted",
+			noBackups:  true,
+			wantDone:   true,
+			wantGen:    CheckpointGenerationLatest,
+			wantReason: "are gone",
+			wantEvent:  "FailoverFallback",
+		},
+		{
+			name:   "in progress on a reachable cluster",
+			phases: []string{PhaseCompleted, ""},
+		},
+		{
+			name:   "unreachable taint falls back",
+			phases: []string{"", ""},
+			cluster: clusterv1alpha1.Cluster{Spec: clusterv1alpha1.ClusterSpec{Taints: []corev1.Taint{
+				{Key: clusterv1alpha1.TaintClusterUnreachable, Effect: corev1.TaintEffectNoExecute},
+			}}},
+			wantDone:   true,
+			wantGen:    CheckpointGenerationLatest,
+			wantReason: "tainted",
+			wantEvent:  "FailoverFallback",
+		},
+		{
+			name:   "not ready cluster falls back",
+			phases: []string{""},
+			cluster: clusterv1alpha1.Cluster{Status: clusterv1alpha1.ClusterStatus{Conditions: []metav1.Condition{
+				{Type: clusterv1alpha1.ClusterConditionReady, Status: metav1.ConditionFalse},
+			}}},
+			wantDone:   true,
+			wantGen:    CheckpointGenerationLatest,
+			wantReason: "not ready",
+			wantEvent:  "FailoverFallback",
+		},
+		{
+			name:       "proxy unreachable falls back",
+			phases:     []string{""},
+			down:       true,
+			wantDone:   true,
+			wantGen:    CheckpointGenerationLatest,
+			wantReason: "via karmada proxy",
+			wantEvent:  "FailoverFallback",
+		},
+		{
+			name:       "checkpoint timeout falls back",
+			phases:     []string{PhaseCompleted, ""},
+			startedAt:  2 * time.Minute,
+			timeout:    time.Minute,
+			wantDone:   true,
+			wantGen:    CheckpointGenerationLatest,
+			wantReason: "did not complete within 1m0s (1/2 pushed)",
+			wantEvent:  "FailoverFallback",
+		},
+		{
+			name:      "within the checkpoint timeout",
+			phases:    []string{""},
+			startedAt: 30 * time.Second,
+			timeout:   time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+				Spec:       migrationv1.StatefulMigrationSpec{Failover: &migrationv1.FailoverPolicy{Enabled: true}},
+			}
+			if tt.timeout > 0 {
+				sm.Spec.Failover.CheckpointTimeout = &metav1.Duration{Duration: tt.timeout}
+			}
+			started := time.Now().Add(-tt.startedAt).UTC().Format(time.RFC3339)
+			rb := newEvictedRB(map[string]string{
+				AnnoFailoverTask:       "member1@2026-01-01T00:00:00Z",
+				AnnoFailoverGeneration: gen,
+				AnnoFailoverStartedAt:  started,
+				AnnoRestorePhase:       "pending",
+			}, [2]string{"member1", "2026-01-01T00:00:00Z"})
+
+			cl := tt.cluster.DeepCopy()
+			cl.Name = "member1"
+			if cl.Status.Conditions == nil {
+				cl.Status.Conditions = []metav1.Condition{{Type: clusterv1alpha1.ClusterConditionReady, Status: metav1.ConditionTrue}}
+			}
+			objs := []client.Object{rb, cl}
+			proxy := newFakeMemberProxy(t)
+			if !tt.noBackups {
+				for i, phase := range tt.phases {
+					name := gen + "-" + string(rune('a'+i))
+					objs = append(objs, &migrationv1.CheckpointBackup{ObjectMeta: metav1.ObjectMeta{
+						Name: name, Namespace: sm.Namespace,
+						Labels: map[string]string{LabelCheckpointGeneration: gen, "target-cluster": "member1", "target-pod": name},
+					}})
+					if phase != "" {
+						proxy.add("member1", newMemberObject(migrationv1.GroupVersion.String(), "CheckpointBackup", sm.Namespace, name,
+							map[string]interface{}{"status": map[string]interface{}{"phase": phase}}))
+					}
+				}
+			}
+			proxy.setDown("member1", tt.down)
+
+			c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(objs...).Build()
+			recorder := record.NewFakeRecorder(10)
+			kc := newTestKarmadaClient(t, c, proxy)
+			r := &MigrationFailoverReconciler{
+				KarmadaClient:       kc,
+				MemberClusterClient: newTestMemberClusterClient(t, c, proxy),
+				Recorder:            recorder,
+				karmadaCluster:      fakeKarmadaCluster{c: c},
+			}
+
+			done, err := r.checkFinalCheckpoint(ctx, rb, sm, gen)
+			if err != nil {
+				t.Fatalf("checkFinalCheckpoint: %v", err)
+			}
+			if done != tt.wantDone {
+				t.Fatalf("done = %v, want %v", done, tt.wantDone)
+			}
+
+			got := newResourceBindingU()
+			if err := c.Get(ctx, types.NamespacedName{Namespace: rb.GetNamespace(), Name: rb.GetName()}, got); err != nil {
+				t.Fatal(err)
+			}
+			ann := got.GetAnnotations()
+			if !tt.wantDone {
+				if ann[AnnoFailoverGeneration] != gen || ann[AnnoRestorePhase] != "pending" || ann[AnnoRestoreGeneration] != "" {
+					t.Errorf("unsettled failover changed the RB annotations: %v", ann)
+				}
+				if len(recorder.Events) != 0 {
+					t.Errorf("unexpected event %q", <-recorder.Events)
+				}
+				return
+			}
+			if ann[AnnoRestoreGeneration] != tt.wantGen {
+				t.Errorf("%s = %q, want %q", AnnoRestoreGeneration, ann[AnnoRestoreGeneration], tt.wantGen)
+			}
+			for _, k := range []string{AnnoFailoverGeneration, AnnoFailoverStartedAt, AnnoRestorePhase} {
+				if v, ok := ann[k]; ok {
+					t.Errorf("%s = %q was not removed", k, v)
+				}
+			}
+			if ann[AnnoFailoverTask] == "" {
+				t.Errorf("%s was removed; the handled task must stay recorded", AnnoFailoverTask)
+			}
+			select {
+			case ev := <-recorder.Events:
+				if !strings.Contains(ev, tt.wantEvent) || !strings.Contains(ev, tt.wantReason) {
+					t.Errorf("event = %q, want %s with %q", ev, tt.wantEvent, tt.wantReason)
+				}
+			default:
+				t.Errorf("no event recorded, want %s", tt.wantEvent)
+			}
+		})
+	}
+}
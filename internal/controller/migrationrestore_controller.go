@@ -19,157 +19,320 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"math/rand"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apischema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 
+	"k8s.io/client-go/util/workqueue"
+	"golang.org/x/time/rate"
+
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 )
 
 const (
-	// 폴링 주기
-	RestoreCheckInterval = 5 * time.Second
+	// 진행 중인 복원 재확인 주기 (시작 후 경과 시간에 비례해 최대값까지 늘어남)
+	RestoreCheckInterval    = 5 * time.Second
+	RestoreMaxCheckInterval = 2 * time.Minute
+	// StatefulMigration/ResourceBinding 공통 인덱스: apiVersion/kind/namespace/name
+	IndexKeyResourceRef = "spec.resourceRef"
 	// 라벨 키: SM 단위 전파정책/Restore 매칭
 	LabelKeySM = "migration.dcnlab.com/sm"
 
 	// ✅ 추가: MigrationRestore 진행 상태 어노테이션 키
         AnnoRestorePhase     = "migration.dcnlab.com/restore-phase"         // working|succeeded|failed
         AnnoRestoreStartedAt = "migration.dcnlab.com/restore-started-at"    // RFC3339
-        RestoreSoftTimeout   = 20 * time.Minute
+        AnnoRestoreFailureReason = "migration.dcnlab.com/restore-failure-reason"
+        AnnoRestoreRolledBack    = "migration.dcnlab.com/restore-rolled-back"
+        // 복원 마감 기본값 (StatefulMigration.spec.restorePolicy.timeout으로 변경 가능)
+        DefaultRestoreTimeout = 20 * time.Minute
+
+        // StatefulMigration.status.restore.phase 값
+        RestorePhaseWorking   = "Working"
+        RestorePhaseSucceeded = "Succeeded"
+        RestorePhaseFailed    = "Failed"
+
+        // StatefulMigration 조건: 모든 Pod에 복원 가능한 완료 체크포인트가 있는지
+        ConditionRestoreReady = "RestoreReady"
+
+        // 목적지 클러스터의 복원 웹훅 (Mutation/mutating-yaml 참고)
+        RestoreWebhookName      = "checkpoint-restore-webhook"
+        RestoreWebhookNamespace = "stateful-migration"
 )
 
 // +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // 참고: Karmada API 접근은 별도 kubeconfig를 쓰므로 이 파일의 RBAC 주석이 Karmada 권한을 보장하지는 않습니다.
 // (Karmada 쪽 권한은 그 kubeconfig의 권한에 따릅니다.)
 
 type MigrationRestoreReconciler struct {
-	// 관리(운영) 클러스터 API client (여기서 StatefulMigration CR들을 unstructured로 조회)
+	// 관리(운영) 클러스터 API client (여기서 StatefulMigration CR들을 조회)
 	client.Client
 	Scheme *runtime.Scheme
 
 	// Karmada control-plane client (RB/PP/Backup/Restore 접근) - 우리 타입으로 통일
 	KarmadaClient *KarmadaClient
+
+	// 목적지 클러스터 접근(웹훅 준비 확인)
+	MemberClusterClient *MemberClusterClient
+
+	// StatefulMigration에 복원 진행 이벤트 기록
+	Recorder record.EventRecorder
+
+	// Karmada control-plane cache: RB 조회는 캐시에서, 쓰기는 KarmadaClient로
+	karmadaCluster cluster.Cluster
+
+	// 멤버 클러스터별 CheckpointBackup 상태 LIST 결과 (backupStatusTTL 동안 재사용)
+	backupStatuses backupStatusCache
 }
 
+// Reconcile handles a single suspended ResourceBinding on the Karmada control plane
 func (r *MigrationRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	// 폴링 워커가 직접 처리하므로, 누군가 호출해도 안전하게 noop
+	lg := log.FromContext(ctx)
+
+	rb := newResourceBindingU()
+	if err := r.karmadaCluster.GetClient().Get(ctx, req.NamespacedName, rb); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !isRestoreCandidateRB(rb) {
+		return ctrl.Result{}, nil
+	}
+
+	// 관리 클러스터에서 관련 SM 탐색(인덱스 사용)
+	var smList migrationv1.StatefulMigrationList
+	resNS, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "namespace")
+	if err := r.List(ctx, &smList, client.InNamespace(resNS), client.MatchingFields{IndexKeyResourceRef: rbResourceKeyU(rb)}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("list StatefulMigrations: %w", err)
+	}
+	if len(smList.Items) == 0 {
+		return ctrl.Result{}, nil
+	}
+	// 여러 SM이 매칭되면 이름순으로 첫 개만 처리
+	sort.Slice(smList.Items, func(i, j int) bool { return smList.Items[i].Name < smList.Items[j].Name })
+	if len(smList.Items) > 1 {
+		lg.Info("Multiple StatefulMigrations matched; taking the first", "count", len(smList.Items), "sm", smList.Items[0].Name)
+	}
+	sm := &smList.Items[0]
+
+	if err := r.handleSuspendedRBForSM(ctx, rb, sm); err != nil {
+		return ctrl.Result{}, fmt.Errorf("handle suspended RB %s for SM %s: %w", namespacedNameU(rb), sm.Name, err)
+	}
+
+	// Backup/Restore/Restore RB 변화는 감시로 들어오지만 멤버 클러스터의 체크포인트 상태와
+	// 웹훅 준비는 이벤트가 없으므로 완료될 때까지 점점 긴 간격으로 재확인
+	fresh := newResourceBindingU()
+	if err := r.karmadaCluster.GetClient().Get(ctx, req.NamespacedName, fresh); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if isRestoreCandidateRB(fresh) {
+		return ctrl.Result{RequeueAfter: restoreRecheckAfter(fresh, sm)}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
-// 워커는 RB를 "먼저" 보고(suspension 감지) → 해당 SM을 찾아 → 바로 처리
+// restoreRecheckAfter returns when to look at a working restore again: a quarter of the time it
+// has been working, between RestoreCheckInterval and RestoreMaxCheckInterval, and not later than
+// its deadline
+func restoreRecheckAfter(rb *unstructured.Unstructured, sm *migrationv1.StatefulMigration) time.Duration {
+	d := RestoreCheckInterval
+	if t, err := time.Parse(time.RFC3339, getRBAnnotation(rb, AnnoRestoreStartedAt)); err == nil {
+		elapsed := time.Since(t)
+		d = min(max(elapsed/4, RestoreCheckInterval), RestoreMaxCheckInterval)
+		if left := restoreTimeout(sm) - elapsed; left > 0 && left+time.Second < d {
+			d = left + time.Second
+		}
+	}
+	return withJitter(d, 0.2)
+}
+
+// SetupWithManager watches ResourceBindings, CheckpointBackups and CheckpointRestores through a
+// cache on the Karmada control plane. Only suspended RBs that still need a restore are queued;
+// StatefulMigrations, backups, restores and the RBs of the restores are mapped to them through
+// the resource reference index.
 func (r *MigrationRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
-		lg := ctrl.Log.WithName("rb-poller")
-		rand.Seed(time.Now().UnixNano())
-
-		for {
-			select {
-			case <-ctx.Done():
-				return nil
-			default:
-			}
-			if r.KarmadaClient == nil {
-				lg.V(4).Info("Karmada client not ready")
-				// wait with jitter
-				if !sleepWithJitterCtx(ctx, RestoreCheckInterval, 0.2) {
-                    return nil
-                }
-				continue
-			}
+	if r.KarmadaClient == nil {
+		return fmt.Errorf("Karmada client not initialized")
+	}
+	karmadaCluster, err := cluster.New(r.KarmadaClient.RESTConfig(), func(o *cluster.Options) {
+		o.Scheme = r.KarmadaClient.Scheme()
+		o.Client.Cache = &client.CacheOptions{Unstructured: true}
+	})
+	if err != nil {
+		return fmt.Errorf("create Karmada cluster cache: %w", err)
+	}
+	if err := mgr.Add(karmadaCluster); err != nil {
+		return err
+	}
+	r.karmadaCluster = karmadaCluster
+	// 복원 대기/실패로 멈춘 RB 수는 scrape 시 Karmada 캐시에서 계산
+	if err := registerCollector(&restoreCollector{reader: karmadaCluster.GetClient()}); err != nil {
+		return fmt.Errorf("register restore metrics: %w", err)
+	}
+	if r.MemberClusterClient == nil {
+		memberClient, err := NewMemberClusterClient(r.KarmadaClient)
+		if err != nil {
+			return fmt.Errorf("create member cluster client: %w", err)
+		}
+		r.MemberClusterClient = memberClient
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("migrationrestore")
+	}
 
-			// 1) Karmada에서 RB 전수 조사
-			rbList := &unstructured.UnstructuredList{}
-			rbList.SetGroupVersionKind(apischema.GroupVersionKind{
-				Group:   "work.karmada.io",
-				Version: "v1alpha2",
-				Kind:    "ResourceBindingList",
-			})
+	ctx := context.Background()
+	if err := karmadaCluster.GetFieldIndexer().IndexField(ctx, newResourceBindingU(), IndexKeyResourceRef, func(obj client.Object) []string {
+		rb, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+		return []string{rbResourceKeyU(rb)}
+	}); err != nil {
+		return fmt.Errorf("index ResourceBindings: %w", err)
+	}
+	if err := karmadaCluster.GetFieldIndexer().IndexField(ctx, newCheckpointBackupU(), IndexKeyResourceRef, func(obj client.Object) []string {
+		backup, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+		return []string{backupResourceKeyU(backup)}
+	}); err != nil {
+		return fmt.Errorf("index CheckpointBackups: %w", err)
+	}
+	if err := indexStatefulMigrationsByResourceRef(ctx, mgr); err != nil {
+		return err
+	}
 
-			if err := r.KarmadaClient.List(ctx, rbList); err != nil {
-				lg.Error(err, "list ResourceBindings failed")
-                // 에러 시에도 잠깐 쉬어 백투백 오류/스핀 방지
-                if !sleepWithJitterCtx(ctx, RestoreCheckInterval, 0.2) {
-                    return nil
-				}
-				continue				
-			}
+	rbPredicate := predicate.NewTypedPredicateFuncs(func(rb *unstructured.Unstructured) bool {
+		return isRestoreCandidateRB(rb)
+	})
+	// Restore의 RB: 목적지 클러스터에 바인딩되면 워크로드 RB를 다시 확인
+	restoreRBPredicate := predicate.NewTypedPredicateFuncs(func(rb *unstructured.Unstructured) bool {
+		apiV, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "apiVersion")
+		kind, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "kind")
+		return apiV == migrationv1.GroupVersion.String() && kind == "CheckpointRestore"
+	})
 
-			for i := range rbList.Items {
-				rb := &rbList.Items[i]
-				if !isRBSuspendedU(rb) {
-					continue
-				}
-				// StatefulSet만 대상으로
-				kind, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "kind")
-				if !strings.EqualFold(kind, "StatefulSet") {
-					continue
-				}
-                // ✅ 완료/실패 RB는 빠르게 스킵하여 불필요한 처리 방지
-                if phase := getRBAnnotation(rb, AnnoRestorePhase); phase == "succeeded" || phase == "failed" {
-                    continue
-                }
-
-				// 2) 원본 Ref
-				resNS, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "namespace")
-				resName, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "name")
-				resAPIV, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "apiVersion")
-				resKind, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "kind")
-
-				// 3) 관리 클러스터에서 관련 SM 탐색
-				smList := &unstructured.UnstructuredList{}
-				smList.SetGroupVersionKind(apischema.GroupVersionKind{
-					Group:   "migration.dcnlab.com",
-					Version: "v1",
-					Kind:    "StatefulMigrationList",
-				})
-				if err := r.Client.List(ctx, smList, &client.ListOptions{Namespace: resNS}); err != nil {
-					lg.Error(err, "list StatefulMigrations failed", "ns", resNS)
-					continue
-				}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("migrationrestore").
+		WatchesRawSource(source.Kind(karmadaCluster.GetCache(), newResourceBindingU(),
+			&handler.TypedEnqueueRequestForObject[*unstructured.Unstructured]{}, rbPredicate)).
+		WatchesRawSource(source.Kind(karmadaCluster.GetCache(), newResourceBindingU(),
+			handler.TypedEnqueueRequestsFromMapFunc(r.resourceBindingsForRestoreRB), restoreRBPredicate)).
+		WatchesRawSource(source.Kind(karmadaCluster.GetCache(), newCheckpointBackupU(),
+			handler.TypedEnqueueRequestsFromMapFunc(r.resourceBindingsForBackup))).
+		WatchesRawSource(source.Kind(karmadaCluster.GetCache(), newCheckpointRestoreU(),
+			handler.TypedEnqueueRequestsFromMapFunc(r.resourceBindingsForRestore))).
+		// RB보다 SM이 늦게 생성/변경된 경우 해당 RB를 다시 큐에 넣음
+		Watches(&migrationv1.StatefulMigration{}, handler.EnqueueRequestsFromMapFunc(r.resourceBindingsForSM)).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: 1,
+			RateLimiter: workqueue.NewTypedMaxOfRateLimiter(
+				workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](RestoreCheckInterval, 5*time.Minute),
+				&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+			),
+		}).
+		Complete(r)
+}
 
-				// --- 여기부터: 여러 SM이 매칭되면 첫 개만 처리 ---
-				matched := []*unstructured.Unstructured{}
-				for j := range smList.Items {
-						sm := &smList.Items[j]
-						refNS, _, _ := unstructured.NestedString(sm.Object, "spec", "resourceRef", "namespace")
-						refName, _, _ := unstructured.NestedString(sm.Object, "spec", "resourceRef", "name")
-						refAPIV, _, _ := unstructured.NestedString(sm.Object, "spec", "resourceRef", "apiVersion")
-						refKind, _, _ := unstructured.NestedString(sm.Object, "spec", "resourceRef", "kind")
-						if refNS == resNS && refName == resName && strings.EqualFold(refKind, resKind) && refAPIV == resAPIV {
-						matched = append(matched, sm)
-						}
-				}
-				if len(matched) == 0 {
-						continue
-				}
-				if len(matched) > 1 {
-						lg.Info("Multiple StatefulMigrations matched; taking the first", "count", len(matched))
-				}
-				if err := r.handleSuspendedRBForSM(ctx, rb, matched[0]); err != nil {
-						lg.Error(err, "handleSuspendedRBForSM failed", "rb", namespacedNameU(rb), "sm", namespacedNameU(matched[0]))
-				}
-			}
-            // ✅ 라운드 종료 후 지터 슬립(핵심)
-            if !sleepWithJitterCtx(ctx, RestoreCheckInterval, 0.2) {
-                return nil
-            }			
+// resourceBindingsForSM maps a StatefulMigration to the suspended ResourceBindings of its workload
+func (r *MigrationRestoreReconciler) resourceBindingsForSM(ctx context.Context, obj client.Object) []reconcile.Request {
+	sm, ok := obj.(*migrationv1.StatefulMigration)
+	if !ok {
+		return nil
+	}
+	ref := sm.Spec.ResourceRef
+	return r.restoreCandidatesFor(ctx, ref.Namespace, resourceRefKey(ref.APIVersion, ref.Kind, ref.Namespace, ref.Name))
+}
+
+// resourceBindingsForBackup maps a CheckpointBackup to the suspended ResourceBindings of the workload it protects
+func (r *MigrationRestoreReconciler) resourceBindingsForBackup(ctx context.Context, backup *unstructured.Unstructured) []reconcile.Request {
+	refNS, _, _ := unstructured.NestedString(backup.Object, "spec", "resourceRef", "namespace")
+	return r.restoreCandidatesFor(ctx, refNS, backupResourceKeyU(backup))
+}
+
+// resourceBindingsForRestore maps a CheckpointRestore to the suspended ResourceBindings of its StatefulMigration
+func (r *MigrationRestoreReconciler) resourceBindingsForRestore(ctx context.Context, restore *unstructured.Unstructured) []reconcile.Request {
+	smName := restore.GetLabels()[LabelKeySM]
+	if smName == "" {
+		return nil
+	}
+	var sm migrationv1.StatefulMigration
+	if err := r.Get(ctx, types.NamespacedName{Namespace: restore.GetNamespace(), Name: smName}, &sm); err != nil {
+		return nil
+	}
+	return r.resourceBindingsForSM(ctx, &sm)
+}
+
+// resourceBindingsForRestoreRB maps the ResourceBinding of a CheckpointRestore to the suspended
+// ResourceBindings the restore was created for
+func (r *MigrationRestoreReconciler) resourceBindingsForRestoreRB(ctx context.Context, rb *unstructured.Unstructured) []reconcile.Request {
+	ns, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "namespace")
+	name, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "name")
+	restore := newCheckpointRestoreU()
+	if err := r.karmadaCluster.GetClient().Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, restore); err != nil {
+		return nil
+	}
+	return r.resourceBindingsForRestore(ctx, restore)
+}
+
+// restoreCandidatesFor returns the suspended ResourceBindings in ns whose workload has the resource reference key
+func (r *MigrationRestoreReconciler) restoreCandidatesFor(ctx context.Context, ns, key string) []reconcile.Request {
+	rbList := &unstructured.UnstructuredList{}
+	rbList.SetGroupVersionKind(apischema.GroupVersionKind{
+		Group:   "work.karmada.io",
+		Version: "v1alpha2",
+		Kind:    "ResourceBindingList",
+	})
+	if err := r.karmadaCluster.GetClient().List(ctx, rbList, client.InNamespace(ns), client.MatchingFields{IndexKeyResourceRef: key}); err != nil {
+		log.FromContext(ctx).Error(err, "list ResourceBindings", "namespace", ns, "resource", key)
+		return nil
+	}
+	out := make([]reconcile.Request, 0, len(rbList.Items))
+	for i := range rbList.Items {
+		if isRestoreCandidateRB(&rbList.Items[i]) {
+			out = append(out, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rbList.Items[i])})
 		}
-	}))
+	}
+	return out
+}
+
+// isRestoreCandidateRB reports whether the RB is suspended for a migration and not finished yet.
+// Any workload kind qualifies; RBs without a matching StatefulMigration are dropped in Reconcile.
+func isRestoreCandidateRB(rb *unstructured.Unstructured) bool {
+	if !isRBSuspendedU(rb) {
+		return false
+	}
+	// 완료/실패 RB는 스킵하여 불필요한 처리 방지
+	// pending: MigrationRun이 최종 체크포인트를 받을 때까지 복원 보류
+	phase := getRBAnnotation(rb, AnnoRestorePhase)
+	return phase != "succeeded" && phase != "failed" && phase != "pending"
 }
 
-func (r *MigrationRestoreReconciler) handleSuspendedRBForSM(ctx context.Context, rb *unstructured.Unstructured, sm *unstructured.Unstructured) error {
+func (r *MigrationRestoreReconciler) handleSuspendedRBForSM(ctx context.Context, rb *unstructured.Unstructured, sm *migrationv1.StatefulMigration) error {
 	lg := log.FromContext(ctx)
 
 	if r.KarmadaClient == nil {
@@ -188,19 +351,26 @@ func (r *MigrationRestoreReconciler) handleSuspendedRBForSM(ctx context.Context,
         // 진행 표식: 처음 진입 시에만 working + 시작시각 기록
         curr := getRBAnnotation(rb, AnnoRestorePhase)
         if curr != "working" && curr != "succeeded" && curr != "failed" {
+                startedAt := time.Now().UTC().Format(time.RFC3339)
                 _ = r.patchRBAnnotationsU(ctx, rb, map[string]string{
                 AnnoRestorePhase:     "working",
-                AnnoRestoreStartedAt: time.Now().UTC().Format(time.RFC3339),
+                AnnoRestoreStartedAt: startedAt,
+                })
+                setRBAnnotation(rb, AnnoRestoreStartedAt, startedAt)
+                r.recordEvent(sm, corev1.EventTypeNormal, "RestoreStarted", "ResourceBinding %s is suspended; restoring from checkpoints", rb.GetName())
+                r.updateRestoreStatus(ctx, sm, func(st *migrationv1.RestoreStatus) {
+                        now := metav1.Now()
+                        *st = migrationv1.RestoreStatus{Phase: RestorePhaseWorking, ResourceBinding: rb.GetName(), StartTime: &now}
                 })
         }
 
-	// SM의 SourceClusters
-	srcClusters, _, _ := nestedStringSlice(sm.Object, "spec", "sourceClusters")
+	// SM의 SourceClusters (자동 탐색 시에는 status에 기록된 클러스터)
+	srcClusters := effectiveSourceClusters(sm)
 	targetClusters := diffClusters(rbClusters, srcClusters)
 	if len(targetClusters) == 0 {
 		lg.Info("No destination clusters after excluding source clusters; skip",
 			"rbClusters", rbClusters, "sourceClusters", srcClusters)
-		return nil
+		return r.checkRestoreDeadline(ctx, rb, sm, fmt.Sprintf("no destination cluster besides source clusters %v", srcClusters))
 	}
 
 	// 원본 Ref
@@ -214,27 +384,75 @@ func (r *MigrationRestoreReconciler) handleSuspendedRBForSM(ctx context.Context,
 	if err != nil {
 		return fmt.Errorf("list backups: %w", err)
 	}
-	if len(backups) == 0 {
-		lg.Info("No related CheckpointBackups; nothing to restore yet")
-		return nil
+	// 복원 소스: 축출/MigrationRun의 최종 체크포인트 세대, 없으면 spec.restorePolicy.source (기본 최신)
+	src := restoreSourceFor(rb, sm)
+	generation := describeRestoreSource(src)
+	candidates, blocking, err := r.selectRestoreCandidates(ctx, backups, src)
+	if err != nil {
+		r.setRestoreReadyCondition(ctx, sm, metav1.ConditionFalse, "InvalidSource", err.Error())
+		return r.checkRestoreDeadline(ctx, rb, sm, err.Error())
+	}
+	// 모든 Pod의 체크포인트가 완료(모든 컨테이너 push)된 뒤에만 Restore 생성
+	if len(blocking) > 0 {
+		pods := make([]string, 0, len(blocking))
+		for pod := range blocking {
+			pods = append(pods, pod)
+		}
+		sort.Strings(pods)
+		reasons := make([]string, 0, len(pods))
+		for _, pod := range pods {
+			reasons = append(reasons, fmt.Sprintf("%s (%s)", pod, blocking[pod]))
+		}
+		msg := fmt.Sprintf("waiting for complete checkpoints of %s: %s", generation, strings.Join(reasons, "; "))
+		lg.Info("Checkpoints not ready; holding restore", "blocking", pods)
+		r.setRestoreReadyCondition(ctx, sm, metav1.ConditionFalse, "CheckpointsNotReady", msg)
+		return r.checkRestoreDeadline(ctx, rb, sm, msg)
+	}
+	if len(candidates) == 0 {
+		lg.Info("No pushed checkpoint for the restore source; nothing to restore yet", "source", generation)
+		msg := fmt.Sprintf("no pushed checkpoint of %s found for the workload", generation)
+		r.setRestoreReadyCondition(ctx, sm, metav1.ConditionFalse, "NoCheckpoints", msg)
+		return r.checkRestoreDeadline(ctx, rb, sm, msg)
+	}
+	r.setRestoreReadyCondition(ctx, sm, metav1.ConditionTrue, "CheckpointsReady",
+		fmt.Sprintf("%d pod(s) have a complete checkpoint of %s", len(candidates), generation))
+	oldest := candidates[0].checkpointTime
+	for _, c := range candidates[1:] {
+		if c.checkpointTime.Before(oldest) {
+			oldest = c.checkpointTime
+		}
+	}
+	if sm.Status.Restore == nil || sm.Status.Restore.Generation != generation {
+		r.recordEvent(sm, corev1.EventTypeNormal, "RestoreGeneration", "Restoring %s from checkpoint generation %s (%d pod(s), oldest checkpoint %s)",
+			rb.GetName(), generation, len(candidates), oldest.UTC().Format(time.RFC3339))
+	}
+	if sm.Status.Restore == nil || sm.Status.Restore.Generation != generation ||
+		sm.Status.Restore.CheckpointTime == nil || !sm.Status.Restore.CheckpointTime.Time.Equal(oldest) {
+		r.updateRestoreStatus(ctx, sm, func(st *migrationv1.RestoreStatus) {
+			st.Generation = generation
+			st.CheckpointTime = &metav1.Time{Time: oldest}
+		})
 	}
 
 	// 2) SM 단위 PropagationPolicy 보장
 	smName := sm.GetName()
-	bkNS := backups[0].GetNamespace()
+	bkNS := candidates[0].backup.GetNamespace()
 	if err := r.ensurePropagationPolicyU(ctx, smName, bkNS, targetClusters); err != nil {
 		return fmt.Errorf("ensure PP: %w", err)
 	}
 
 	// 3) 백업 → Restore 보장 + 4) Restore RB 바인딩 확인
 	readyAll := true
-	for i := range backups {
-		restore, created, err := r.ensureRestoreFromBackupU(ctx, smName, &backups[i])
+	var pending []string
+	keep := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		restore, created, err := r.ensureRestoreFromBackupU(ctx, sm, c.backup, c.containers)
 		if err != nil {
-			return fmt.Errorf("ensure restore for %s: %w", backups[i].GetName(), err)
+			return fmt.Errorf("ensure restore for %s: %w", c.backup.GetName(), err)
 		}
+		keep[restore.GetName()] = true
 		if created {
-			lg.Info("Created CheckpointRestore", "restore", restore.GetName(), "backup", backups[i].GetName())
+			lg.Info("Created CheckpointRestore", "restore", restore.GetName(), "backup", c.backup.GetName())
 		}
 
 		ok, err := r.isRestoreBoundU(ctx, restore, targetClusters)
@@ -243,82 +461,315 @@ func (r *MigrationRestoreReconciler) handleSuspendedRBForSM(ctx context.Context,
 		}
 		if !ok {
 			readyAll = false
+			pending = append(pending, restore.GetName())
 			lg.Info("Restore not bound yet", "restore", restore.GetName(), "wantClusters", targetClusters)
 		}
 	}
+	// 다른 세대의 Restore가 남아 있으면 웹훅이 잘못된 체크포인트를 고를 수 있으므로 삭제
+	if err := r.pruneStaleRestores(ctx, sm, keep); err != nil {
+		return fmt.Errorf("prune stale restores: %w", err)
+	}
 	if readyAll {
+		autoResume := sm.Spec.RestorePolicy != nil && sm.Spec.RestorePolicy.AutoResume
+		if autoResume && getRBAnnotation(rb, AnnoRestorePhase) != "succeeded" {
+			// 모든 목적지에서 웹훅이 준비된 뒤에만 dispatching 재개
+			var notReady []string
+			for _, cluster := range targetClusters {
+				if err := restoreWebhookReady(ctx, r.MemberClusterClient, cluster); err != nil {
+					lg.Info("Restore webhook not ready", "cluster", cluster, "reason", err.Error())
+					notReady = append(notReady, cluster)
+				}
+			}
+			if len(notReady) > 0 {
+				r.recordEvent(sm, corev1.EventTypeWarning, "WebhookNotReady", "Waiting for the restore webhook on %v before resuming %s", notReady, rb.GetName())
+				return r.checkRestoreDeadline(ctx, rb, sm, fmt.Sprintf("restore webhook not ready on %v", notReady))
+			}
+			if err := r.setRBDispatchingU(ctx, rb, false); err != nil {
+				return fmt.Errorf("resume RB dispatching: %w", err)
+			}
+			lg.Info("Resumed RB dispatching after restore", "rb", namespacedNameU(rb))
+			r.recordEvent(sm, corev1.EventTypeNormal, "DispatchingResumed", "Resumed dispatching of ResourceBinding %s to %v", rb.GetName(), targetClusters)
+		}
 		if getRBAnnotation(rb, AnnoRestorePhase) != "succeeded" {
 			if err := r.patchRBAnnotationsU(ctx, rb, map[string]string{AnnoRestorePhase: "succeeded"}); err != nil {
 				return fmt.Errorf("annotate RB succeeded: %w", err)
 			}
 			lg.Info("Marked restore succeeded on RB annotation", "rb", namespacedNameU(rb))
+			r.recordEvent(sm, corev1.EventTypeNormal, "RestoreSucceeded", "%d CheckpointRestore(s) bound to %v", len(candidates), targetClusters)
+			r.updateRestoreStatus(ctx, sm, func(st *migrationv1.RestoreStatus) {
+				now := metav1.Now()
+				st.Phase = RestorePhaseSucceeded
+				st.Reason = ""
+				st.CompletionTime = &now
+			})
 		}
+		return nil
 	}
-        // 아직 모두 준비되지 않았다면 soft-timeout 감시(로그 또는 실패 어노테이션)
-        if !readyAll {
-                if started := getRBAnnotation(rb, AnnoRestoreStartedAt); started != "" {
-                if t, err := time.Parse(time.RFC3339, started); err == nil && time.Since(t) > RestoreSoftTimeout {
-                        lg.Info("Restore soft-timeout reached; still working", "rb", namespacedNameU(rb))
-                        // 필요 시 실패 표식까지 남기려면 아래 주석 해제
-                        // _ = r.patchRBAnnotationsU(ctx, rb, map[string]string{AnnoRestorePhase: "failed"})
-                }
-                }
-        }
 
+	// 아직 모두 준비되지 않았다면 마감 시간 감시(초과 시 실패 + 선택적 롤백)
+	return r.checkRestoreDeadline(ctx, rb, sm, fmt.Sprintf("CheckpointRestores not bound to %v: %s", targetClusters, strings.Join(pending, ", ")))
+}
+
+// recordEvent records an event on the StatefulMigration when a recorder is configured
+func (r *MigrationRestoreReconciler) recordEvent(sm *migrationv1.StatefulMigration, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(sm, eventType, reason, messageFmt, args...)
+	}
+}
+
+// restoreWebhookReady reports whether the restore webhook is registered and has an available
+// replica on the cluster, so pods created after dispatching resumes get checkpoint images
+func restoreWebhookReady(ctx context.Context, mc *MemberClusterClient, cluster string) error {
+	if mc == nil {
+		return fmt.Errorf("member cluster client not initialized")
+	}
+	if _, err := mc.GetResourceFromCluster(ctx, cluster, "admissionregistration.k8s.io/v1",
+		"MutatingWebhookConfiguration", "", RestoreWebhookName); err != nil {
+		return fmt.Errorf("MutatingWebhookConfiguration %s: %w", RestoreWebhookName, err)
+	}
+	dep, err := mc.GetDeploymentFromCluster(ctx, cluster, RestoreWebhookNamespace, RestoreWebhookName)
+	if err != nil {
+		return fmt.Errorf("Deployment %s/%s: %w", RestoreWebhookNamespace, RestoreWebhookName, err)
+	}
+	if dep.Status.AvailableReplicas < 1 {
+		return fmt.Errorf("Deployment %s/%s has no available replica", RestoreWebhookNamespace, RestoreWebhookName)
+	}
 	return nil
 }
 
-func (r *MigrationRestoreReconciler) listRelatedBackupsU(ctx context.Context, apiVersion, kind, ns, name string) ([]unstructured.Unstructured, error) {
-	if r.KarmadaClient == nil {
-		return nil, fmt.Errorf("Karmada client not initialized")
+// restoreTimeout returns the restore deadline configured on the StatefulMigration
+func restoreTimeout(sm *migrationv1.StatefulMigration) time.Duration {
+	if p := sm.Spec.RestorePolicy; p != nil && p.Timeout != nil && p.Timeout.Duration > 0 {
+		return p.Timeout.Duration
+	}
+	return DefaultRestoreTimeout
+}
+
+// checkRestoreDeadline fails the restore when it has been working longer than the configured timeout.
+// waitingFor describes what the restore is still waiting for and becomes the failure reason.
+func (r *MigrationRestoreReconciler) checkRestoreDeadline(ctx context.Context, rb *unstructured.Unstructured, sm *migrationv1.StatefulMigration, waitingFor string) error {
+	started := getRBAnnotation(rb, AnnoRestoreStartedAt)
+	if started == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, started)
+	if err != nil {
+		return nil
+	}
+	timeout := restoreTimeout(sm)
+	if time.Since(t) <= timeout {
+		return nil
 	}
+	return r.failRestore(ctx, rb, sm, fmt.Sprintf("restore did not complete within %s: %s", timeout, waitingFor))
+}
+
+// failRestore marks the restore failed on the RB and the StatefulMigration. With the ColdStart
+// rollback policy the CheckpointRestores are removed and dispatching is resumed, so the workload
+// starts from its original images instead of staying down.
+func (r *MigrationRestoreReconciler) failRestore(ctx context.Context, rb *unstructured.Unstructured, sm *migrationv1.StatefulMigration, reason string) error {
+	lg := log.FromContext(ctx)
+	lg.Info("Restore failed", "rb", namespacedNameU(rb), "sm", sm.Name, "reason", reason)
+	r.recordEvent(sm, corev1.EventTypeWarning, "RestoreFailed", "%s", reason)
+
+	if err := r.patchRBAnnotationsU(ctx, rb, map[string]string{
+		AnnoRestorePhase:         "failed",
+		AnnoRestoreFailureReason: reason,
+	}); err != nil {
+		return fmt.Errorf("annotate RB failed: %w", err)
+	}
+
+	rolledBack := false
+	if p := sm.Spec.RestorePolicy; p != nil && p.Rollback == migrationv1.RestoreRollbackColdStart {
+		// Restore가 남아 있으면 웹훅이 체크포인트 이미지로 바꾸므로 먼저 삭제
+		if err := deleteRestoreArtifacts(ctx, r.KarmadaClient, sm); err != nil {
+			return fmt.Errorf("delete restores for rollback: %w", err)
+		}
+		if err := r.setRBDispatchingU(ctx, rb, false); err != nil {
+			return fmt.Errorf("resume RB for rollback: %w", err)
+		}
+		if err := r.patchRBAnnotationsU(ctx, rb, map[string]string{AnnoRestoreRolledBack: "true"}); err != nil {
+			return fmt.Errorf("annotate RB rolled back: %w", err)
+		}
+		rolledBack = true
+		lg.Info("Rolled back failed restore; workload resumes with original images", "rb", namespacedNameU(rb))
+		r.recordEvent(sm, corev1.EventTypeNormal, "RolledBack", "Removed CheckpointRestores and resumed dispatching of %s with original images", rb.GetName())
+	}
+
+	r.updateRestoreStatus(ctx, sm, func(st *migrationv1.RestoreStatus) {
+		now := metav1.Now()
+		st.Phase = RestorePhaseFailed
+		st.Reason = reason
+		st.ResourceBinding = rb.GetName()
+		st.CompletionTime = &now
+		st.RolledBack = rolledBack
+	})
+	return nil
+}
+
+// updateRestoreStatus applies mutate to status.restore of the StatefulMigration (best effort)
+func (r *MigrationRestoreReconciler) updateRestoreStatus(ctx context.Context, sm *migrationv1.StatefulMigration, mutate func(*migrationv1.RestoreStatus)) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest migrationv1.StatefulMigration
+		if err := r.Get(ctx, client.ObjectKeyFromObject(sm), &latest); err != nil {
+			return err
+		}
+		st := migrationv1.RestoreStatus{}
+		if latest.Status.Restore != nil {
+			st = *latest.Status.Restore.DeepCopy()
+		}
+		mutate(&st)
+		latest.Status.Restore = &st
+		if err := r.Status().Update(ctx, &latest); err != nil {
+			return err
+		}
+		sm.Status = latest.Status
+		return nil
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "update StatefulMigration restore status", "sm", client.ObjectKeyFromObject(sm))
+	}
+}
+
+// setRestoreReadyCondition sets the RestoreReady condition of the StatefulMigration when it changed (best effort)
+func (r *MigrationRestoreReconciler) setRestoreReadyCondition(ctx context.Context, sm *migrationv1.StatefulMigration, status metav1.ConditionStatus, reason, message string) {
+	if c := meta.FindStatusCondition(sm.Status.Conditions, ConditionRestoreReady); c != nil &&
+		c.Status == status && c.Reason == reason && c.Message == message && c.ObservedGeneration == sm.Generation {
+		return
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest migrationv1.StatefulMigration
+		if err := r.Get(ctx, client.ObjectKeyFromObject(sm), &latest); err != nil {
+			return err
+		}
+		meta.SetStatusCondition(&latest.Status.Conditions, metav1.Condition{
+			Type:               ConditionRestoreReady,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: latest.Generation,
+		})
+		if err := r.Status().Update(ctx, &latest); err != nil {
+			return err
+		}
+		sm.Status = latest.Status
+		return nil
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "update StatefulMigration RestoreReady condition", "sm", client.ObjectKeyFromObject(sm))
+	}
+}
+
+// setRBDispatchingU sets spec.suspension.dispatching of the RB
+func (r *MigrationRestoreReconciler) setRBDispatchingU(ctx context.Context, rb *unstructured.Unstructured, suspended bool) error {
+	key := types.NamespacedName{Namespace: rb.GetNamespace(), Name: rb.GetName()}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		fresh := newResourceBindingU()
+		if err := r.KarmadaClient.Get(ctx, key, fresh); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(fresh.Object, suspended, "spec", "suspension", "dispatching"); err != nil {
+			return err
+		}
+		return r.KarmadaClient.Update(ctx, fresh)
+	})
+}
+
+// listRelatedBackupsU returns the CheckpointBackups of the workload from the Karmada cache
+func (r *MigrationRestoreReconciler) listRelatedBackupsU(ctx context.Context, apiVersion, kind, ns, name string) ([]unstructured.Unstructured, error) {
 	list := &unstructured.UnstructuredList{}
 	list.SetGroupVersionKind(apischema.GroupVersionKind{
 		Group:   "migration.dcnlab.com",
 		Version: "v1",
 		Kind:    "CheckpointBackupList",
 	})
-	if err := r.KarmadaClient.List(ctx, list, &client.ListOptions{Namespace: ns}); err != nil {
+	if err := r.karmadaCluster.GetClient().List(ctx, list, client.InNamespace(ns),
+		client.MatchingFields{IndexKeyResourceRef: resourceRefKey(apiVersion, kind, ns, name)}); err != nil {
 		return nil, err
 	}
-	out := make([]unstructured.Unstructured, 0, len(list.Items))
-	for i := range list.Items {
-		it := list.Items[i]
-		refAPIV, _, _ := unstructured.NestedString(it.Object, "spec", "resourceRef", "apiVersion")
-		refKind, _, _ := unstructured.NestedString(it.Object, "spec", "resourceRef", "kind")
-		refNS, _, _ := unstructured.NestedString(it.Object, "spec", "resourceRef", "namespace")
-		refName, _, _ := unstructured.NestedString(it.Object, "spec", "resourceRef", "name")
-		if refAPIV == apiVersion && strings.EqualFold(refKind, kind) && refNS == ns && refName == name {
-			out = append(out, it)
+	return list.Items, nil
+}
+
+// pruneStaleRestores deletes the StatefulMigration's CheckpointRestores that are not in keep
+func (r *MigrationRestoreReconciler) pruneStaleRestores(ctx context.Context, sm *migrationv1.StatefulMigration, keep map[string]bool) error {
+	restoreList := &unstructured.UnstructuredList{}
+	restoreList.SetGroupVersionKind(newCheckpointRestoreU().GroupVersionKind())
+	restoreList.SetKind("CheckpointRestoreList")
+	if err := r.karmadaCluster.GetClient().List(ctx, restoreList, client.InNamespace(sm.Namespace), client.MatchingLabels{LabelKeySM: sm.Name}); err != nil {
+		return err
+	}
+	for i := range restoreList.Items {
+		restore := &restoreList.Items[i]
+		if keep[restore.GetName()] {
+			continue
+		}
+		log.FromContext(ctx).Info("Deleting CheckpointRestore of another generation", "restore", restore.GetName())
+		if err := r.KarmadaClient.Delete(ctx, restore); err != nil && !apierrors.IsNotFound(err) {
+			return err
 		}
 	}
-	return out, nil
+	return nil
 }
 
-func (r *MigrationRestoreReconciler) ensureRestoreFromBackupU(ctx context.Context, smName string, backup *unstructured.Unstructured) (*unstructured.Unstructured, bool, error) {
+// checkpointGenerationOf returns the checkpoint generation a CheckpointBackup belongs to
+func checkpointGenerationOf(backup *unstructured.Unstructured) string {
+	if gen := backup.GetLabels()[LabelCheckpointGeneration]; gen != "" {
+		return gen
+	}
+	return CheckpointGenerationLatest
+}
+
+// ensureRestoreFromBackupU creates or updates the CheckpointRestore of the backup with the pinned containers
+func (r *MigrationRestoreReconciler) ensureRestoreFromBackupU(ctx context.Context, sm *migrationv1.StatefulMigration, backup *unstructured.Unstructured, containers []interface{}) (*unstructured.Unstructured, bool, error) {
 	if r.KarmadaClient == nil {
 		return nil, false, fmt.Errorf("Karmada client not initialized")
 	}
+	smName := sm.Name
+	match := restorePodMatchU(sm, backup)
+	// MigrationRun이 볼륨을 옮긴 경우 PVC가 준비될 때까지 웹훅이 pod를 거부
+	match["transferredVolumes"] = nil
+	if sm.Spec.VolumeTransfer != nil && backup.GetLabels()[LabelMigrationRun] != "" {
+		match["transferredVolumes"] = true
+	}
 	ns := backup.GetNamespace()
 	bkName := backup.GetName()
 	restoreName := fmt.Sprintf("%s-restore", bkName)
+	generation := checkpointGenerationOf(backup)
 
-	existing := &unstructured.Unstructured{}
-	existing.SetGroupVersionKind(apischema.GroupVersionKind{
-		Group:   "migration.dcnlab.com",
-		Version: "v1",
-		Kind:    "CheckpointRestore",
-	})
-	if err := r.KarmadaClient.Get(ctx, types.NamespacedName{Namespace: ns, Name: restoreName}, existing); err == nil {
+	existing := newCheckpointRestoreU()
+	if err := r.karmadaCluster.GetClient().Get(ctx, types.NamespacedName{Namespace: ns, Name: restoreName}, existing); err == nil {
 		labels := existing.GetLabels()
 		if labels == nil {
 			labels = map[string]string{}
 		}
-		if labels[LabelKeySM] != smName {
+		need := false
+		if labels[LabelKeySM] != smName || labels[LabelCheckpointGeneration] != generation {
 			labels[LabelKeySM] = smName
+			labels[LabelCheckpointGeneration] = generation
 			existing.SetLabels(labels)
+			need = true
+		}
+		for field, want := range match {
+			curr, found, _ := unstructured.NestedFieldNoCopy(existing.Object, "spec", field)
+			if want == nil {
+				if found {
+					unstructured.RemoveNestedField(existing.Object, "spec", field)
+					need = true
+				}
+				continue
+			}
+			if !found || fmt.Sprintf("%v", curr) != fmt.Sprintf("%v", want) {
+				_ = unstructured.SetNestedField(existing.Object, want, "spec", field)
+				need = true
+			}
+		}
+		// 다른 체크포인트가 선택되면 digest 갱신
+		if curr, _, _ := unstructured.NestedSlice(existing.Object, "spec", "containers"); fmt.Sprintf("%v", curr) != fmt.Sprintf("%v", containers) {
+			_ = unstructured.SetNestedSlice(existing.Object, containers, "spec", "containers")
+			need = true
+		}
+		if need {
 			if err := r.KarmadaClient.Update(ctx, existing); err != nil {
-				return nil, false, fmt.Errorf("patch restore labels: %w", err)
+				return nil, false, fmt.Errorf("patch restore: %w", err)
 			}
 		}
 		return existing, false, nil
@@ -326,33 +777,130 @@ func (r *MigrationRestoreReconciler) ensureRestoreFromBackupU(ctx context.Contex
 		return nil, false, fmt.Errorf("get restore: %w", err)
 	}
 
-	restore := &unstructured.Unstructured{}
-	restore.SetGroupVersionKind(apischema.GroupVersionKind{
-		Group:   "migration.dcnlab.com",
-		Version: "v1",
-		Kind:    "CheckpointRestore",
-	})
+	restore := newCheckpointRestoreU()
 	restore.SetNamespace(ns)
 	restore.SetName(restoreName)
 	restore.SetLabels(map[string]string{
 		LabelKeySM:                     smName,
 		"migration.dcnlab.com/restore": "true",
 		"migration.dcnlab.com/backup":  bkName,
+		LabelCheckpointGeneration:      generation,
 	})
+	if owner, uid, ok := ownerOf(backup); ok {
+		setOwnerMetadata(restore, owner.Namespace, owner.Name, uid)
+	}
 
 	podName, _, _ := unstructured.NestedString(backup.Object, "spec", "podRef", "name")
-	containers, _, _ := unstructured.NestedSlice(backup.Object, "spec", "containers")
 
 	_ = unstructured.SetNestedField(restore.Object, map[string]interface{}{"name": bkName}, "spec", "backupRef")
 	_ = unstructured.SetNestedField(restore.Object, podName, "spec", "podName")
+	for field, want := range match {
+		if want != nil {
+			_ = unstructured.SetNestedField(restore.Object, want, "spec", field)
+		}
+	}
 	_ = unstructured.SetNestedSlice(restore.Object, containers, "spec", "containers")
 
 	if err := r.KarmadaClient.Create(ctx, restore); err != nil {
+		// 캐시에 아직 없는 Restore: 캐시에 들어오면 감시로 다시 확인
+		if apierrors.IsAlreadyExists(err) {
+			return restore, false, nil
+		}
 		return nil, false, fmt.Errorf("create restore: %w", err)
 	}
 	return restore, true, nil
 }
 
+// Pod labels that differ between replicas or revisions and therefore cannot select restored pods
+var perPodLabels = map[string]bool{
+	"pod-template-hash":                  true,
+	"controller-revision-hash":           true,
+	"statefulset.kubernetes.io/pod-name": true,
+	"apps.kubernetes.io/pod-index":       true,
+}
+
+// restorePodMatchU returns the CheckpointRestore spec fields that decide which new pod takes
+// the backup's checkpoint; a nil value means the field must be unset.
+// StatefulSet pods default to Ordinal and bare pods keep their own name, so both are matched
+// by podName first; for other kinds (e.g. Deployment ReplicaSets) pod names change on
+// reschedule, so by default any pod of the workload may claim the next unused checkpoint.
+func restorePodMatchU(sm *migrationv1.StatefulMigration, backup *unstructured.Unstructured) map[string]interface{} {
+	kind, _, _ := unstructured.NestedString(backup.Object, "spec", "resourceRef", "kind")
+	podName, _, _ := unstructured.NestedString(backup.Object, "spec", "podRef", "name")
+	genName, _, _ := unstructured.NestedString(backup.Object, "spec", "podRef", "generateName")
+	podLabels, _, _ := unstructured.NestedStringMap(backup.Object, "spec", "podRef", "labels")
+
+	mapping := sm.Spec.PodMapping
+	if mapping == nil {
+		switch {
+		case strings.EqualFold(kind, "StatefulSet"):
+			mapping = &migrationv1.PodMapping{Strategy: migrationv1.PodMappingOrdinal}
+		case strings.EqualFold(kind, "Pod"):
+		default:
+			mapping = &migrationv1.PodMapping{Strategy: migrationv1.PodMappingRoundRobin}
+		}
+	}
+
+	match := map[string]interface{}{
+		"podGenerateName": nil,
+		"podSelector":     nil,
+		"podMapping":      nil,
+		"podOrdinal":      nil,
+		"podLabelValue":   nil,
+	}
+	if mapping == nil {
+		return match
+	}
+
+	m := map[string]interface{}{"strategy": string(mapping.Strategy)}
+	if mapping.LabelKey != "" {
+		m["labelKey"] = mapping.LabelKey
+	}
+	match["podMapping"] = m
+
+	selector := map[string]interface{}{}
+	for k, v := range podLabels {
+		if !perPodLabels[k] && k != mapping.LabelKey {
+			selector[k] = v
+		}
+	}
+	if len(selector) > 0 {
+		match["podSelector"] = selector
+	} else if genName != "" {
+		match["podGenerateName"] = genName
+	}
+
+	switch mapping.Strategy {
+	case migrationv1.PodMappingOrdinal:
+		if ord, ok := podOrdinal(podName, podLabels); ok {
+			match["podOrdinal"] = ord
+		}
+	case migrationv1.PodMappingLabel:
+		if v := podLabels[mapping.LabelKey]; v != "" {
+			match["podLabelValue"] = v
+		}
+	}
+	return match
+}
+
+// podOrdinal returns the ordinal of a pod from the apps.kubernetes.io/pod-index label or its name suffix
+func podOrdinal(podName string, podLabels map[string]string) (int64, bool) {
+	if idx := podLabels["apps.kubernetes.io/pod-index"]; idx != "" {
+		if n, err := strconv.ParseInt(idx, 10, 32); err == nil {
+			return n, true
+		}
+	}
+	i := strings.LastIndex(podName, "-")
+	if i < 0 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(podName[i+1:], 10, 32)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
 func (r *MigrationRestoreReconciler) ensurePropagationPolicyU(ctx context.Context, smName, ns string, clusterNames []string) error {
 	if r.KarmadaClient == nil {
 		return fmt.Errorf("Karmada client not initialized")
@@ -360,7 +908,7 @@ func (r *MigrationRestoreReconciler) ensurePropagationPolicyU(ctx context.Contex
 	if len(clusterNames) == 0 {
 		return fmt.Errorf("no target clusters")
 	}
-	polName := fmt.Sprintf("%s-restore-policy", smName)
+	polName := smName + restorePolicySuffix
 
 	pol := &unstructured.Unstructured{}
 	pol.SetGroupVersionKind(apischema.GroupVersionKind{
@@ -406,6 +954,7 @@ func (r *MigrationRestoreReconciler) ensurePropagationPolicyU(ctx context.Contex
 		newPol.SetGroupVersionKind(pol.GroupVersionKind())
 		newPol.SetNamespace(ns)
 		newPol.SetName(polName)
+		setOwnerMetadata(newPol, ns, smName, "")
 		_ = unstructured.SetNestedSlice(newPol.Object, []interface{}{selector}, "spec", "resourceSelectors")
 		_ = unstructured.SetNestedField(newPol.Object, placement, "spec", "placement")
 		return r.KarmadaClient.Create(ctx, newPol)
@@ -415,34 +964,21 @@ func (r *MigrationRestoreReconciler) ensurePropagationPolicyU(ctx context.Contex
 	}
 }
 
+// isRestoreBoundU reports whether Karmada bound the CheckpointRestore to exactly the target clusters
 func (r *MigrationRestoreReconciler) isRestoreBoundU(ctx context.Context, restore *unstructured.Unstructured, targetClusters []string) (bool, error) {
-	if r.KarmadaClient == nil {
-		return false, fmt.Errorf("Karmada client not initialized")
-	}
 	rbList := &unstructured.UnstructuredList{}
 	rbList.SetGroupVersionKind(apischema.GroupVersionKind{
 		Group:   "work.karmada.io",
 		Version: "v1alpha2",
 		Kind:    "ResourceBindingList",
 	})
-	if err := r.KarmadaClient.List(ctx, rbList, &client.ListOptions{Namespace: restore.GetNamespace()}); err != nil {
+	key := resourceRefKey(migrationv1.GroupVersion.String(), "CheckpointRestore", restore.GetNamespace(), restore.GetName())
+	if err := r.karmadaCluster.GetClient().List(ctx, rbList, client.InNamespace(restore.GetNamespace()), client.MatchingFields{IndexKeyResourceRef: key}); err != nil {
 		return false, err
 	}
-	wantAPIV := migrationv1.GroupVersion.String()
-	wantKind := "CheckpointRestore"
-	wantNS := restore.GetNamespace()
-	wantName := restore.GetName()
-
 	for i := range rbList.Items {
-		rb := &rbList.Items[i]
-		apiV, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "apiVersion")
-		kd, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "kind")
-		ns, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "namespace")
-		name, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "name")
-		if apiV == wantAPIV && strings.EqualFold(kd, wantKind) && ns == wantNS && name == wantName {
-			curr, _ := getRBClusterNamesU(rb)
-			return stringSetsEqual(curr, targetClusters), nil
-		}
+		curr, _ := getRBClusterNamesU(&rbList.Items[i])
+		return stringSetsEqual(curr, targetClusters), nil
 	}
 	return false, nil
 }
@@ -489,6 +1025,29 @@ func (r *MigrationRestoreReconciler) patchRBAnnotationsU(ctx context.Context, rb
 	})
 }
 
+// findResourceBindingU finds the ResourceBinding of the referenced workload on Karmada
+func findResourceBindingU(ctx context.Context, c client.Client, ref migrationv1.ResourceRef) (*unstructured.Unstructured, error) {
+	rbList := &unstructured.UnstructuredList{}
+	rbList.SetGroupVersionKind(apischema.GroupVersionKind{
+		Group:   "work.karmada.io",
+		Version: "v1alpha2",
+		Kind:    "ResourceBindingList",
+	})
+	if err := c.List(ctx, rbList, &client.ListOptions{Namespace: ref.Namespace}); err != nil {
+		return nil, fmt.Errorf("list ResourceBindings in %s: %w", ref.Namespace, err)
+	}
+	for i := range rbList.Items {
+		rb := &rbList.Items[i]
+		apiV, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "apiVersion")
+		kd, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "kind")
+		name, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "name")
+		if apiV == ref.APIVersion && strings.EqualFold(kd, ref.Kind) && name == ref.Name {
+			return rb, nil
+		}
+	}
+	return nil, fmt.Errorf("no ResourceBinding found for %s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+}
+
 func getRBClusterNamesU(rb *unstructured.Unstructured) ([]string, error) {
 	clusters, found, err := unstructured.NestedSlice(rb.Object, "spec", "clusters")
 	if err != nil {
@@ -577,11 +1136,102 @@ func nestedStringSlice(obj map[string]interface{}, fields ...string) ([]string,
 	return out, true, nil
 }
 
+// effectiveSourceClusters returns spec.sourceClusters, or the clusters observed in status when
+// the StatefulMigration discovers its source clusters from the ResourceBinding
+func effectiveSourceClusters(sm *migrationv1.StatefulMigration) []string {
+	// The discovered clusters win, as in determineSourceClusters; the CRD rejects both being set
+	if sm.Spec.SourceClusterDiscovery != migrationv1.SourceClusterDiscoveryResourceBinding && len(sm.Spec.SourceClusters) > 0 {
+		return sm.Spec.SourceClusters
+	}
+	return observedSourceClusters(sm)
+}
+
+// newResourceBindingU returns an empty unstructured ResourceBinding
+func newResourceBindingU() *unstructured.Unstructured {
+	rb := &unstructured.Unstructured{}
+	rb.SetGroupVersionKind(apischema.GroupVersionKind{
+		Group:   "work.karmada.io",
+		Version: "v1alpha2",
+		Kind:    "ResourceBinding",
+	})
+	return rb
+}
+
+// newCheckpointBackupU returns an empty unstructured CheckpointBackup
+func newCheckpointBackupU() *unstructured.Unstructured {
+	b := &unstructured.Unstructured{}
+	b.SetGroupVersionKind(migrationv1.GroupVersion.WithKind("CheckpointBackup"))
+	return b
+}
+
+// newCheckpointRestoreU returns an empty unstructured CheckpointRestore
+func newCheckpointRestoreU() *unstructured.Unstructured {
+	restore := &unstructured.Unstructured{}
+	restore.SetGroupVersionKind(migrationv1.GroupVersion.WithKind("CheckpointRestore"))
+	return restore
+}
+
+// smIndexedManagers remembers the managers whose cache already indexes StatefulMigrations by
+// IndexKeyResourceRef; the restore and failover controllers both need it and the cache rejects
+// a second registration of the same index
+var smIndexedManagers sync.Map
+
+// indexStatefulMigrationsByResourceRef registers the IndexKeyResourceRef index of StatefulMigrations once per manager
+func indexStatefulMigrationsByResourceRef(ctx context.Context, mgr ctrl.Manager) error {
+	if _, loaded := smIndexedManagers.LoadOrStore(mgr, struct{}{}); loaded {
+		return nil
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &migrationv1.StatefulMigration{}, IndexKeyResourceRef, func(obj client.Object) []string {
+		sm, ok := obj.(*migrationv1.StatefulMigration)
+		if !ok {
+			return nil
+		}
+		ref := sm.Spec.ResourceRef
+		return []string{resourceRefKey(ref.APIVersion, ref.Kind, ref.Namespace, ref.Name)}
+	}); err != nil {
+		smIndexedManagers.Delete(mgr)
+		return fmt.Errorf("index StatefulMigrations: %w", err)
+	}
+	return nil
+}
+
+// resourceRefKey is the value of IndexKeyResourceRef for a workload reference
+func resourceRefKey(apiVersion, kind, namespace, name string) string {
+	return strings.Join([]string{apiVersion, strings.ToLower(kind), namespace, name}, "/")
+}
+
+// rbResourceKeyU returns the IndexKeyResourceRef value of the workload an RB binds
+func rbResourceKeyU(rb *unstructured.Unstructured) string {
+	apiV, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "apiVersion")
+	kind, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "kind")
+	ns, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "namespace")
+	name, _, _ := unstructured.NestedString(rb.Object, "spec", "resource", "name")
+	return resourceRefKey(apiV, kind, ns, name)
+}
+
+// backupResourceKeyU returns the IndexKeyResourceRef value of the workload a CheckpointBackup protects
+func backupResourceKeyU(backup *unstructured.Unstructured) string {
+	apiV, _, _ := unstructured.NestedString(backup.Object, "spec", "resourceRef", "apiVersion")
+	kind, _, _ := unstructured.NestedString(backup.Object, "spec", "resourceRef", "kind")
+	ns, _, _ := unstructured.NestedString(backup.Object, "spec", "resourceRef", "namespace")
+	name, _, _ := unstructured.NestedString(backup.Object, "spec", "resourceRef", "name")
+	return resourceRefKey(apiV, kind, ns, name)
+}
+
 func namespacedNameU(u *unstructured.Unstructured) string {
 	return fmt.Sprintf("%s/%s", u.GetNamespace(), u.GetName())
 }
 
 
+func setRBAnnotation(u *unstructured.Unstructured, key, value string) {
+	ann := u.GetAnnotations()
+	if ann == nil {
+		ann = map[string]string{}
+	}
+	ann[key] = value
+	u.SetAnnotations(ann)
+}
+
 // 유틸 추가
 func getRBAnnotation(u *unstructured.Unstructured, key string) string {
     if ann := u.GetAnnotations(); ann != nil {
@@ -16,7 +16,20 @@ limitations under the License.
 package controller
 
 import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 )
 
 var _ = Describe("MigrationRestore Controller", func() {
@@ -29,3 +42,287 @@ var _ = Describe("MigrationRestore Controller", func() {
 		})
 	})
 })
+
+func TestPodOrdinal(t *testing.T) {
+	tests := []struct {
+		name    string
+		podName string
+		labels  map[string]string
+		want    int64
+		wantOK  bool
+	}{
+		{name: "name suffix", podName: "db-2", want: 2, wantOK: true},
+		{name: "pod-index label wins", podName: "db-2", labels: map[string]string{"apps.kubernetes.io/pod-index": "5"}, want: 5, wantOK: true},
+		{name: "invalid label falls back to name", podName: "db-3", labels: map[string]string{"apps.kubernetes.io/pod-index": "x"}, want: 3, wantOK: true},
+		{name: "generated suffix", podName: "web-7d9f8-abcde", wantOK: false},
+		{name: "no dash", podName: "db", wantOK: false},
+		{name: "empty", podName: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := podOrdinal(tt.podName, tt.labels)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("podOrdinal(%q) = %d, %v; want %d, %v", tt.podName, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRestorePodMatchU(t *testing.T) {
+	backup := func(kind, podName, generateName string, labels map[string]interface{}) *unstructured.Unstructured {
+		podRef := map[string]interface{}{"name": podName}
+		if generateName != "" {
+			podRef["generateName"] = generateName
+		}
+		if labels != nil {
+			podRef["labels"] = labels
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{
+			"resourceRef": map[string]interface{}{"kind": kind},
+			"podRef":      podRef,
+		}}}
+	}
+	unset := map[string]interface{}{
+		"podGenerateName": nil, "podSelector": nil, "podMapping": nil, "podOrdinal": nil, "podLabelValue": nil,
+	}
+	with := func(fields map[string]interface{}) map[string]interface{} {
+		out := map[string]interface{}{}
+		for k, v := range unset {
+			out[k] = v
+		}
+		for k, v := range fields {
+			out[k] = v
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		mapping *migrationv1.PodMapping
+		backup  *unstructured.Unstructured
+		want    map[string]interface{}
+	}{
+		{
+			name:   "bare pod matches by name only",
+			backup: backup("Pod", "solo", "", nil),
+			want:   unset,
+		},
+		{
+			name: "statefulset defaults to ordinal",
+			backup: backup("StatefulSet", "db-1", "", map[string]interface{}{
+				"app": "db", "controller-revision-hash": "db-6b7", "statefulset.kubernetes.io/pod-name": "db-1",
+			}),
+			want: with(map[string]interface{}{
+				"podMapping":  map[string]interface{}{"strategy": "Ordinal"},
+				"podSelector": map[string]interface{}{"app": "db"},
+				"podOrdinal":  int64(1),
+			}),
+		},
+		{
+			name:   "deployment defaults to round robin by generateName",
+			backup: backup("Deployment", "web-7d9f8-abcde", "web-7d9f8-", map[string]interface{}{"pod-template-hash": "7d9f8"}),
+			want: with(map[string]interface{}{
+				"podMapping":      map[string]interface{}{"strategy": "RoundRobin"},
+				"podGenerateName": "web-7d9f8-",
+			}),
+		},
+		{
+			name:    "label mapping keeps the label out of the selector",
+			mapping: &migrationv1.PodMapping{Strategy: migrationv1.PodMappingLabel, LabelKey: "shard"},
+			backup:  backup("Deployment", "kv-abc", "kv-", map[string]interface{}{"app": "kv", "shard": "3"}),
+			want: with(map[string]interface{}{
+				"podMapping":    map[string]interface{}{"strategy": "Label", "labelKey": "shard"},
+				"podSelector":   map[string]interface{}{"app": "kv"},
+				"podLabelValue": "3",
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := &migrationv1.StatefulMigration{}
+			sm.Spec.PodMapping = tt.mapping
+			if got := restorePodMatchU(sm, tt.backup); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("restorePodMatchU() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestoreRecheckAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		started time.Duration // 0: no start annotation
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{name: "not started", want: RestoreCheckInterval},
+		{name: "just started", started: 10 * time.Second, want: RestoreCheckInterval},
+		{name: "a quarter of the elapsed time", started: 4 * time.Minute, want: time.Minute},
+		{name: "capped", started: time.Hour, timeout: 2 * time.Hour, want: RestoreMaxCheckInterval},
+		{name: "not past the deadline", started: 9*time.Minute + 50*time.Second, timeout: 10 * time.Minute, want: 11 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := &migrationv1.StatefulMigration{}
+			if tt.timeout > 0 {
+				sm.Spec.RestorePolicy = &migrationv1.RestorePolicy{Timeout: &metav1.Duration{Duration: tt.timeout}}
+			}
+			rb := newResourceBindingU()
+			if tt.started > 0 {
+				rb.SetAnnotations(map[string]string{AnnoRestoreStartedAt: time.Now().Add(-tt.started).UTC().Format(time.RFC3339)})
+			}
+			got := restoreRecheckAfter(rb, sm)
+			// 20% 지터 + 초 단위 시작 시각
+			if lo, hi := tt.want*8/10-time.Second, tt.want*12/10+time.Second; got < lo || got > hi {
+				t.Fatalf("restoreRecheckAfter = %v, want %v ±20%%", got, tt.want)
+			}
+		})
+	}
+}
+
+// newRestoreTestClient returns a fake Karmada client with the indexes of the restore controller's cache
+func newRestoreTestClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	return fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(objs...).
+		WithIndex(newResourceBindingU(), IndexKeyResourceRef, func(obj client.Object) []string {
+			if rb, ok := obj.(*unstructured.Unstructured); ok {
+				return []string{rbResourceKeyU(rb)}
+			}
+			return nil
+		}).
+		WithIndex(newCheckpointBackupU(), IndexKeyResourceRef, func(obj client.Object) []string {
+			if backup, ok := obj.(*unstructured.Unstructured); ok {
+				return []string{backupResourceKeyU(backup)}
+			}
+			return nil
+		}).
+		Build()
+}
+
+// newBindingU returns a ResourceBinding of the resource, bound to the clusters
+func newBindingU(name, apiVersion, kind, resourceName string, suspended bool, annotations map[string]string, clusters ...string) *unstructured.Unstructured {
+	rb := newResourceBindingU()
+	rb.SetNamespace("app")
+	rb.SetName(name)
+	rb.SetAnnotations(annotations)
+	spec := map[string]interface{}{
+		"resource": map[string]interface{}{"apiVersion": apiVersion, "kind": kind, "namespace": "app", "name": resourceName},
+	}
+	if suspended {
+		spec["suspension"] = map[string]interface{}{"dispatching": true}
+	}
+	var targets []interface{}
+	for _, c := range clusters {
+		targets = append(targets, map[string]interface{}{"name": c})
+	}
+	if targets != nil {
+		spec["clusters"] = targets
+	}
+	rb.Object["spec"] = spec
+	return rb
+}
+
+// newBackupU returns a CheckpointBackup of the workload
+func newBackupU(name, kind, workload string) *unstructured.Unstructured {
+	backup := newCheckpointBackupU()
+	backup.SetNamespace("app")
+	backup.SetName(name)
+	backup.Object["spec"] = map[string]interface{}{
+		"resourceRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": kind, "namespace": "app", "name": workload},
+	}
+	return backup
+}
+
+// newRestoreU returns a CheckpointRestore with the labels
+func newRestoreU(name string, labels map[string]string) *unstructured.Unstructured {
+	restore := newCheckpointRestoreU()
+	restore.SetNamespace("app")
+	restore.SetName(name)
+	restore.SetLabels(labels)
+	return restore
+}
+
+func TestRestoreLookupsFromCache(t *testing.T) {
+	ctx := context.Background()
+	c := newRestoreTestClient(t,
+		newBackupU("db-0", "StatefulSet", "db"),
+		newBackupU("db-1", "StatefulSet", "db"),
+		newBackupU("web-0", "StatefulSet", "web"),
+		newBackupU("db-deploy-0", "Deployment", "db"),
+		newBindingU("db-0-restore-checkpointrestore", migrationv1.GroupVersion.String(), "CheckpointRestore", "db-0-restore", false, nil, "member2"),
+	)
+	r := &MigrationRestoreReconciler{karmadaCluster: fakeKarmadaCluster{c: c}}
+
+	backups, err := r.listRelatedBackupsU(ctx, "apps/v1", "StatefulSet", "app", "db")
+	if err != nil {
+		t.Fatalf("listRelatedBackupsU: %v", err)
+	}
+	var names []string
+	for i := range backups {
+		names = append(names, backups[i].GetName())
+	}
+	if want := []string{"db-0", "db-1"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("backups = %v, want %v", names, want)
+	}
+
+	tests := []struct {
+		restore string
+		targets []string
+		want    bool
+	}{
+		{restore: "db-0-restore", targets: []string{"member2"}, want: true},
+		{restore: "db-0-restore", targets: []string{"member1"}},
+		{restore: "db-0-restore", targets: []string{"member1", "member2"}},
+		{restore: "db-1-restore", targets: []string{"member2"}},
+	}
+	for _, tt := range tests {
+		bound, err := r.isRestoreBoundU(ctx, newRestoreU(tt.restore, nil), tt.targets)
+		if err != nil {
+			t.Fatalf("isRestoreBoundU(%s): %v", tt.restore, err)
+		}
+		if bound != tt.want {
+			t.Errorf("isRestoreBoundU(%s, %v) = %v, want %v", tt.restore, tt.targets, bound, tt.want)
+		}
+	}
+}
+
+func TestRestoreEventMapping(t *testing.T) {
+	ctx := context.Background()
+	sm := &migrationv1.StatefulMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "app"},
+		Spec: migrationv1.StatefulMigrationSpec{
+			ResourceRef: migrationv1.ResourceRef{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "app", Name: "db"},
+		},
+	}
+	restore := newRestoreU("db-0-restore", map[string]string{LabelKeySM: "db"})
+	c := newRestoreTestClient(t, sm,
+		newBindingU("db-statefulset", "apps/v1", "StatefulSet", "db", true, nil, "member1"),
+		newBindingU("db-done-statefulset", "apps/v1", "StatefulSet", "db", true, map[string]string{AnnoRestorePhase: "succeeded"}, "member1"),
+		newBindingU("web-statefulset", "apps/v1", "StatefulSet", "web", true, nil, "member1"),
+		restore,
+		newRestoreU("other-restore", nil),
+	)
+	r := &MigrationRestoreReconciler{Client: c, karmadaCluster: fakeKarmadaCluster{c: c}}
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "app", Name: "db-statefulset"}}}
+
+	tests := []struct {
+		name string
+		got  []reconcile.Request
+		want []reconcile.Request
+	}{
+		{name: "statefulmigration", got: r.resourceBindingsForSM(ctx, sm), want: want},
+		{name: "backup", got: r.resourceBindingsForBackup(ctx, newBackupU("db-0", "StatefulSet", "db")), want: want},
+		{name: "backup of another workload", got: r.resourceBindingsForBackup(ctx, newBackupU("api-0", "StatefulSet", "api"))},
+		{name: "restore", got: r.resourceBindingsForRestore(ctx, restore), want: want},
+		{name: "restore without statefulmigration", got: r.resourceBindingsForRestore(ctx, newRestoreU("other-restore", nil))},
+		{name: "restore binding", got: r.resourceBindingsForRestoreRB(ctx,
+			newBindingU("db-0-restore-checkpointrestore", migrationv1.GroupVersion.String(), "CheckpointRestore", "db-0-restore", false, nil, "member2")), want: want},
+		{name: "binding of a missing restore", got: r.resourceBindingsForRestoreRB(ctx,
+			newBindingU("gone-checkpointrestore", migrationv1.GroupVersion.String(), "CheckpointRestore", "gone", false, nil, "member2"))},
+	}
+	for _, tt := range tests {
+		if len(tt.got) != len(tt.want) || (len(tt.want) > 0 && !reflect.DeepEqual(tt.got, tt.want)) {
+			t.Errorf("%s: requests = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,809 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+const (
+	// StatefulMigration 어노테이션: 진행 중인 MigrationRun 이름 (백업 컨트롤러는 이 동안 멈춤)
+	AnnoMigrationRun = "migration.dcnlab.com/migration-run"
+	// 최종 체크포인트 CheckpointBackup 라벨: 만든 MigrationRun 이름
+	LabelMigrationRun = "migration.dcnlab.com/migration-run"
+	// PropagationPolicy 어노테이션: 재배치 전 placement(JSON), 롤백 시 복원
+	AnnoOriginalPlacement = "migration.dcnlab.com/original-placement"
+
+	MigrationRunFinalizer = "migrationrun.migration.dcnlab.com/finalizer"
+
+	// 진행 중인 MigrationRun 재확인 주기
+	MigrationRunCheckInterval = 5 * time.Second
+	// MigrationRun 마감 기본값 (spec.timeout으로 변경 가능)
+	DefaultMigrationRunTimeout = 30 * time.Minute
+)
+
+// MigrationRunReconciler drives a planned migration of a StatefulMigration's workload to a
+// destination cluster: suspend dispatching, take a final checkpoint with stopPod, retarget the
+// PropagationPolicy, let MigrationRestoreReconciler create the restores, then resume.
+type MigrationRunReconciler struct {
+	// 관리 클러스터 client (MigrationRun, StatefulMigration)
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Karmada control-plane client (RB/PP/Backup/Restore 접근)
+	KarmadaClient *KarmadaClient
+
+	// 소스 클러스터의 최종 체크포인트 상태, 목적지 웹훅 확인
+	MemberClusterClient *MemberClusterClient
+
+	// MigrationRun에 단계별 이벤트 기록
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=migrationruns,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=migrationruns/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=migrationruns/finalizers,verbs=update
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile advances a MigrationRun by one step. Every step is idempotent and the run is
+// requeued until it reaches Succeeded, Failed or Cancelled.
+func (r *MigrationRunReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var run migrationv1.MigrationRun
+	if err := r.Get(ctx, req.NamespacedName, &run); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if r.KarmadaClient == nil {
+		return ctrl.Result{}, fmt.Errorf("Karmada client not initialized")
+	}
+
+	if run.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, r.reconcileDelete(ctx, &run)
+	}
+	if !controllerutil.ContainsFinalizer(&run, MigrationRunFinalizer) {
+		controllerutil.AddFinalizer(&run, MigrationRunFinalizer)
+		if err := r.Update(ctx, &run); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if migrationRunFinished(run.Status.Phase) {
+		if run.Status.Phase == migrationv1.MigrationRunSucceeded && volumeSnapshotsPending(&run) {
+			return r.deleteVolumeSnapshots(ctx, &run)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	sm, err := r.getStatefulMigration(ctx, &run)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if sm == nil {
+		return ctrl.Result{}, r.abort(ctx, &run, nil, migrationv1.MigrationRunFailed,
+			fmt.Sprintf("StatefulMigration %s not found", run.Spec.StatefulMigrationName))
+	}
+
+	// 취소 또는 마감 초과 시 원래 배치로 롤백
+	if run.Spec.Cancel {
+		return ctrl.Result{}, r.abort(ctx, &run, sm, migrationv1.MigrationRunCancelled, "cancelled by spec.cancel")
+	}
+	if st := run.Status.StartTime; st != nil && time.Since(st.Time) > migrationRunTimeout(&run) {
+		return ctrl.Result{}, r.abort(ctx, &run, sm, migrationv1.MigrationRunFailed,
+			fmt.Sprintf("run did not complete within %s: %s", migrationRunTimeout(&run), run.Status.Message))
+	}
+
+	switch run.Status.Phase {
+	case "", migrationv1.MigrationRunPending:
+		err = r.start(ctx, &run, sm)
+	case migrationv1.MigrationRunSuspending:
+		err = r.suspend(ctx, &run, sm)
+	case migrationv1.MigrationRunTransferringVolumes:
+		err = r.transferVolumes(ctx, &run, sm)
+	case migrationv1.MigrationRunCheckpointing:
+		err = r.checkpoint(ctx, &run, sm)
+	case migrationv1.MigrationRunRetargeting:
+		err = r.retarget(ctx, &run, sm)
+	case migrationv1.MigrationRunRestoring:
+		err = r.waitForRestore(ctx, &run, sm)
+	case migrationv1.MigrationRunResuming:
+		err = r.resume(ctx, &run, sm)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if migrationRunFinished(run.Status.Phase) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: withJitter(MigrationRunCheckInterval, 0.2)}, nil
+}
+
+// start validates the run, records the current placement and takes the StatefulMigration over
+func (r *MigrationRunReconciler) start(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration) error {
+	// 같은 SM의 다른 MigrationRun이 끝날 때까지 대기
+	if owner := sm.Annotations[AnnoMigrationRun]; owner != "" && owner != run.Name {
+		return r.setMessage(ctx, run, migrationv1.MigrationRunPending, fmt.Sprintf("waiting for MigrationRun %s to finish", owner))
+	}
+	dest := run.Spec.DestinationCluster
+	if dest == "" {
+		return r.abort(ctx, run, sm, migrationv1.MigrationRunFailed, "spec.destinationCluster is empty")
+	}
+
+	rb, err := findResourceBindingU(ctx, r.KarmadaClient, sm.Spec.ResourceRef)
+	if err != nil {
+		return r.setMessage(ctx, run, migrationv1.MigrationRunPending, err.Error())
+	}
+	ann := rb.GetAnnotations()
+	ppName := ann[karmadav1alpha1.PropagationPolicyNameAnnotation]
+	ppNS := ann[karmadav1alpha1.PropagationPolicyNamespaceAnnotation]
+	if ppName == "" {
+		return r.abort(ctx, run, sm, migrationv1.MigrationRunFailed,
+			fmt.Sprintf("ResourceBinding %s is not placed by a PropagationPolicy", rb.GetName()))
+	}
+	rbClusters, err := getRBClusterNamesU(rb)
+	if err != nil {
+		return fmt.Errorf("parse RB clusters: %w", err)
+	}
+	sources := diffClusters(rbClusters, []string{dest})
+	if len(sources) == 0 {
+		return r.abort(ctx, run, sm, migrationv1.MigrationRunFailed,
+			fmt.Sprintf("workload is not running outside %s (ResourceBinding clusters %v)", dest, rbClusters))
+	}
+
+	// 백업 컨트롤러가 스펙/고아 정리를 하지 않도록 SM을 점유
+	if sm.Annotations[AnnoMigrationRun] != run.Name {
+		patch := client.MergeFromWithOptions(sm.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		if sm.Annotations == nil {
+			sm.Annotations = map[string]string{}
+		}
+		sm.Annotations[AnnoMigrationRun] = run.Name
+		if err := r.Patch(ctx, sm, patch); err != nil {
+			return fmt.Errorf("claim StatefulMigration: %w", err)
+		}
+	}
+
+	r.recordEvent(run, corev1.EventTypeNormal, "MigrationStarted", "Migrating %s from %v to %s", sm.Spec.ResourceRef.Name, sources, dest)
+	return r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+		now := metav1.Now()
+		st.Phase = migrationv1.MigrationRunSuspending
+		st.Message = "suspending dispatching"
+		st.ResourceBinding = rb.GetName()
+		st.PropagationPolicy = ppNS + "/" + ppName
+		st.SourceClusters = sources
+		st.StartTime = &now
+	})
+}
+
+// suspend stops Karmada from dispatching the workload and holds the restore controller back
+// until the final checkpoints are pushed
+func (r *MigrationRunReconciler) suspend(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration) error {
+	// 1) PP: 원래 placement 기록 + dispatching 중단 (RB는 PP를 따라감)
+	if err := r.updatePropagationPolicy(ctx, run, func(pp *karmadav1alpha1.PropagationPolicy) error {
+		if _, ok := pp.Annotations[AnnoOriginalPlacement]; !ok {
+			raw, err := json.Marshal(pp.Spec.Placement)
+			if err != nil {
+				return err
+			}
+			if pp.Annotations == nil {
+				pp.Annotations = map[string]string{}
+			}
+			pp.Annotations[AnnoOriginalPlacement] = string(raw)
+		}
+		suspended := true
+		pp.Spec.Suspension = &karmadav1alpha1.Suspension{Dispatching: &suspended}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("suspend PropagationPolicy: %w", err)
+	}
+
+	// 2) RB: 즉시 중단 + 이전 복원 표식 초기화, 체크포인트 완료 전에는 복원 보류(pending)
+	if err := r.updateResourceBinding(ctx, run, func(rb *unstructured.Unstructured) error {
+		ann := rb.GetAnnotations()
+		if ann == nil {
+			ann = map[string]string{}
+		}
+		delete(ann, AnnoRestoreStartedAt)
+		delete(ann, AnnoRestoreFailureReason)
+		delete(ann, AnnoRestoreRolledBack)
+		delete(ann, AnnoRestoreGeneration)
+		ann[AnnoRestorePhase] = "pending"
+		rb.SetAnnotations(ann)
+		return unstructured.SetNestedField(rb.Object, true, "spec", "suspension", "dispatching")
+	}); err != nil {
+		return fmt.Errorf("suspend ResourceBinding: %w", err)
+	}
+
+	r.recordEvent(run, corev1.EventTypeNormal, "DispatchingSuspended", "Suspended dispatching of ResourceBinding %s", run.Status.ResourceBinding)
+	if sm.Spec.VolumeTransfer != nil {
+		return r.setMessage(ctx, run, migrationv1.MigrationRunTransferringVolumes, "copying volume data")
+	}
+	return r.setMessage(ctx, run, migrationv1.MigrationRunCheckpointing, "taking final checkpoints")
+}
+
+// transferVolumes copies the PersistentVolumeClaims mounted by the protected pods on the source
+// clusters, so the final checkpoint is taken only after their data left the source
+func (r *MigrationRunReconciler) transferVolumes(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration) error {
+	policy := sm.Spec.VolumeTransfer
+	if len(run.Status.Volumes) == 0 {
+		volumes, err := discoverVolumes(ctx, r.KarmadaClient, r.MemberClusterClient, sm, run.Status.SourceClusters)
+		if err != nil {
+			return r.abort(ctx, run, sm, migrationv1.MigrationRunFailed, fmt.Sprintf("discover volumes: %v", err))
+		}
+		if len(volumes) == 0 {
+			return r.setMessage(ctx, run, migrationv1.MigrationRunCheckpointing, "no volumes to transfer, taking final checkpoints")
+		}
+		for _, vol := range volumes {
+			if err := startVolumeCopy(ctx, r.MemberClusterClient, policy, run.Namespace, run.Name, vol); err != nil {
+				return r.abort(ctx, run, sm, migrationv1.MigrationRunFailed, fmt.Sprintf("copy volume %s on %s: %v", vol.ClaimName, vol.Cluster, err))
+			}
+		}
+		r.recordEvent(run, corev1.EventTypeNormal, "VolumeCopyStarted", "Copying %d volume(s) with %s", len(volumes), policy.Method)
+		return r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+			st.Volumes = volumes
+			st.Message = fmt.Sprintf("copying %d volume(s)", len(volumes))
+		})
+	}
+
+	volumes := make([]migrationv1.MigrationRunVolume, len(run.Status.Volumes))
+	copy(volumes, run.Status.Volumes)
+	copied := 0
+	for i := range volumes {
+		if volumes[i].Phase == VolumePhaseCopying {
+			done, err := volumeCopyDone(ctx, r.MemberClusterClient, policy, run.Namespace, run.Name, volumes[i])
+			if err != nil {
+				return r.abort(ctx, run, sm, migrationv1.MigrationRunFailed, fmt.Sprintf("copy volume %s: %v", volumes[i].ClaimName, err))
+			}
+			if done {
+				volumes[i].Phase = VolumePhaseCopied
+			}
+		}
+		if volumes[i].Phase != VolumePhaseCopying {
+			copied++
+		}
+	}
+	if copied < len(volumes) {
+		return r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+			st.Volumes = volumes
+			st.Message = fmt.Sprintf("%d/%d volume(s) copied", copied, len(volumes))
+		})
+	}
+	r.recordEvent(run, corev1.EventTypeNormal, "VolumesCopied", "All %d volume(s) copied", copied)
+	return r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+		st.Volumes = volumes
+		st.Phase = migrationv1.MigrationRunCheckpointing
+		st.Message = "taking final checkpoints"
+	})
+}
+
+// checkpoint creates a one-shot CheckpointBackup with stopPod for every protected pod on the
+// source clusters and waits until the checkpoint agent reports each of them pushed
+func (r *MigrationRunReconciler) checkpoint(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration) error {
+	if len(run.Status.Checkpoints) == 0 {
+		checkpoints, err := createFinalCheckpoints(ctx, r.KarmadaClient, sm, run.Status.SourceClusters, run.Name,
+			map[string]string{LabelMigrationRun: run.Name, LabelCheckpointGeneration: run.Name}, true)
+		if err != nil {
+			return err
+		}
+		if len(checkpoints) == 0 {
+			return r.abort(ctx, run, sm, migrationv1.MigrationRunFailed,
+				fmt.Sprintf("no CheckpointBackup protects a pod on %v yet", run.Status.SourceClusters))
+		}
+		r.recordEvent(run, corev1.EventTypeNormal, "CheckpointRequested", "Requested %d final checkpoint(s)", len(checkpoints))
+		return r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+			st.Checkpoints = checkpoints
+			st.Message = fmt.Sprintf("waiting for %d final checkpoint(s)", len(checkpoints))
+		})
+	}
+
+	checkpoints := make([]migrationv1.MigrationRunCheckpoint, len(run.Status.Checkpoints))
+	copy(checkpoints, run.Status.Checkpoints)
+	done, err := finalCheckpointProgress(ctx, r.MemberClusterClient, run.Namespace, checkpoints)
+	if err != nil {
+		return r.abort(ctx, run, sm, migrationv1.MigrationRunFailed, err.Error())
+	}
+
+	if done < len(checkpoints) {
+		return r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+			st.Checkpoints = checkpoints
+			st.Message = fmt.Sprintf("%d/%d final checkpoint(s) pushed", done, len(checkpoints))
+		})
+	}
+	r.recordEvent(run, corev1.EventTypeNormal, "CheckpointsPushed", "All %d final checkpoint(s) pushed", done)
+	return r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+		st.Checkpoints = checkpoints
+		st.Phase = migrationv1.MigrationRunRetargeting
+		st.Message = fmt.Sprintf("moving placement to %s", run.Spec.DestinationCluster)
+	})
+}
+
+// retarget moves the PropagationPolicy placement to the destination cluster and, once Karmada
+// has rescheduled the binding and the transferred volumes are bound there, releases the
+// ResourceBinding to the restore controller
+func (r *MigrationRunReconciler) retarget(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration) error {
+	dest := run.Spec.DestinationCluster
+	if err := r.updatePropagationPolicy(ctx, run, func(pp *karmadav1alpha1.PropagationPolicy) error {
+		pp.Spec.Placement.ClusterAffinity = &karmadav1alpha1.ClusterAffinity{ClusterNames: []string{dest}}
+		pp.Spec.Placement.ClusterAffinities = nil
+		return nil
+	}); err != nil {
+		return fmt.Errorf("retarget PropagationPolicy: %w", err)
+	}
+
+	rb := newResourceBindingU()
+	if err := r.KarmadaClient.Get(ctx, types.NamespacedName{Namespace: run.Namespace, Name: run.Status.ResourceBinding}, rb); err != nil {
+		return fmt.Errorf("get ResourceBinding: %w", err)
+	}
+	clusters, err := getRBClusterNamesU(rb)
+	if err != nil {
+		return fmt.Errorf("parse RB clusters: %w", err)
+	}
+	if !stringSetsEqual(clusters, []string{dest}) {
+		return r.setMessage(ctx, run, migrationv1.MigrationRunRetargeting,
+			fmt.Sprintf("waiting for Karmada to schedule %s to %s (currently %v)", rb.GetName(), dest, clusters))
+	}
+	if len(run.Status.Volumes) > 0 {
+		ready, err := r.restoreVolumes(ctx, run, sm)
+		if err != nil {
+			return r.abort(ctx, run, sm, migrationv1.MigrationRunFailed, err.Error())
+		}
+		if !ready {
+			return nil
+		}
+	}
+
+	// 복원 보류 해제: MigrationRestoreReconciler가 이 run의 최종 체크포인트로 Restore 생성
+	if err := r.updateResourceBinding(ctx, run, func(rb *unstructured.Unstructured) error {
+		ann := rb.GetAnnotations()
+		if ann == nil {
+			ann = map[string]string{}
+		}
+		delete(ann, AnnoRestorePhase)
+		ann[AnnoRestoreGeneration] = run.Name
+		rb.SetAnnotations(ann)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("release ResourceBinding for restore: %w", err)
+	}
+	r.recordEvent(run, corev1.EventTypeNormal, "Retargeted", "ResourceBinding %s scheduled to %s", rb.GetName(), dest)
+	return r.setMessage(ctx, run, migrationv1.MigrationRunRestoring, fmt.Sprintf("waiting for CheckpointRestores on %s", dest))
+}
+
+// restoreVolumes creates the transferred claims on the destination and reports whether all of
+// them hold their data
+func (r *MigrationRunReconciler) restoreVolumes(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration) (bool, error) {
+	policy := sm.Spec.VolumeTransfer
+	if policy == nil {
+		return false, fmt.Errorf("spec.volumeTransfer was removed while volumes are transferred")
+	}
+	dest := run.Spec.DestinationCluster
+	if err := r.MemberClusterClient.EnsureNamespace(ctx, dest, run.Namespace); err != nil {
+		return false, fmt.Errorf("ensure namespace on %s: %w", dest, err)
+	}
+
+	volumes := make([]migrationv1.MigrationRunVolume, len(run.Status.Volumes))
+	copy(volumes, run.Status.Volumes)
+	ready := 0
+	for i := range volumes {
+		switch volumes[i].Phase {
+		case VolumePhaseCopied:
+			if err := startVolumeRestore(ctx, r.MemberClusterClient, policy, run.Namespace, run.Name, dest, volumes[i]); err != nil {
+				return false, fmt.Errorf("restore volume %s on %s: %w", volumes[i].ClaimName, dest, err)
+			}
+			volumes[i].Phase = VolumePhaseRestoring
+		case VolumePhaseRestoring:
+			done, err := volumeRestoreDone(ctx, r.MemberClusterClient, policy, run.Namespace, run.Name, dest, volumes[i])
+			if err != nil {
+				return false, fmt.Errorf("restore volume %s on %s: %w", volumes[i].ClaimName, dest, err)
+			}
+			if done {
+				volumes[i].Phase = VolumePhaseReady
+			}
+		}
+		if volumes[i].Phase == VolumePhaseReady {
+			ready++
+		}
+	}
+	if ready == len(volumes) {
+		r.recordEvent(run, corev1.EventTypeNormal, "VolumesRestored", "All %d volume(s) bound on %s", ready, dest)
+	}
+	return ready == len(volumes), r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+		st.Volumes = volumes
+		st.Message = fmt.Sprintf("%d/%d volume(s) restored on %s", ready, len(volumes), dest)
+	})
+}
+
+// waitForRestore follows the restore controller through the ResourceBinding annotations
+func (r *MigrationRunReconciler) waitForRestore(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration) error {
+	rb := newResourceBindingU()
+	if err := r.KarmadaClient.Get(ctx, types.NamespacedName{Namespace: run.Namespace, Name: run.Status.ResourceBinding}, rb); err != nil {
+		return fmt.Errorf("get ResourceBinding: %w", err)
+	}
+	switch getRBAnnotation(rb, AnnoRestorePhase) {
+	case "succeeded":
+		return r.setMessage(ctx, run, migrationv1.MigrationRunResuming, "resuming dispatching")
+	case "failed":
+		return r.abort(ctx, run, sm, migrationv1.MigrationRunFailed, "restore failed: "+getRBAnnotation(rb, AnnoRestoreFailureReason))
+	}
+	return nil
+}
+
+// resume lifts the suspension once the restore webhook is ready on the destination, points a
+// static StatefulMigration at the destination and hands it back to the backup controller
+func (r *MigrationRunReconciler) resume(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration) error {
+	dest := run.Spec.DestinationCluster
+	if err := restoreWebhookReady(ctx, r.MemberClusterClient, dest); err != nil {
+		return r.setMessage(ctx, run, migrationv1.MigrationRunResuming, fmt.Sprintf("restore webhook not ready on %s: %v", dest, err))
+	}
+	if err := r.updatePropagationPolicy(ctx, run, func(pp *karmadav1alpha1.PropagationPolicy) error {
+		delete(pp.Annotations, AnnoOriginalPlacement)
+		pp.Spec.Suspension = nil
+		return nil
+	}); err != nil {
+		return fmt.Errorf("resume PropagationPolicy: %w", err)
+	}
+	if err := r.updateResourceBinding(ctx, run, func(rb *unstructured.Unstructured) error {
+		return unstructured.SetNestedField(rb.Object, false, "spec", "suspension", "dispatching")
+	}); err != nil {
+		return fmt.Errorf("resume ResourceBinding: %w", err)
+	}
+	// 복사용 pod 정리 (스냅샷은 목적지 PVC가 Bound된 뒤 deleteVolumeSnapshots에서 정리)
+	if policy := sm.Spec.VolumeTransfer; policy != nil && len(run.Status.Volumes) > 0 {
+		clusters := append([]string{dest}, run.Status.SourceClusters...)
+		if err := deleteVolumeTransfer(ctx, r.MemberClusterClient, clusters, run.Namespace, run.Name, volumeTransferKinds(policy, false)); err != nil {
+			log.FromContext(ctx).Info("Cannot delete copier pods", "run", run.Name, "error", err.Error())
+		}
+	}
+
+	if err := r.releaseStatefulMigration(ctx, run, sm, func(sm *migrationv1.StatefulMigration) {
+		// 정적 소스 클러스터는 목적지로 갱신해야 다음 백업이 새 위치를 따라감
+		if len(sm.Spec.SourceClusters) > 0 {
+			sm.Spec.SourceClusters = []string{dest}
+		}
+	}); err != nil {
+		return err
+	}
+
+	r.recordEvent(run, corev1.EventTypeNormal, "MigrationSucceeded", "Workload %s now runs on %s", sm.Spec.ResourceRef.Name, dest)
+	return r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+		now := metav1.Now()
+		st.Phase = migrationv1.MigrationRunSucceeded
+		st.Message = fmt.Sprintf("migrated to %s", dest)
+		st.CompletionTime = &now
+	})
+}
+
+// volumeSnapshotsPending reports whether a succeeded run still has transferred volumes to clean up
+func volumeSnapshotsPending(run *migrationv1.MigrationRun) bool {
+	for _, vol := range run.Status.Volumes {
+		if vol.Phase == VolumePhaseReady {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteVolumeSnapshots removes the snapshots of a succeeded run once every claim is bound on the
+// destination, then marks the volumes CleanedUp. Copier runs have nothing left after resume.
+func (r *MigrationRunReconciler) deleteVolumeSnapshots(ctx context.Context, run *migrationv1.MigrationRun) (ctrl.Result, error) {
+	sm, err := r.getStatefulMigration(ctx, run)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	dest := run.Spec.DestinationCluster
+	if sm == nil || sm.Spec.VolumeTransfer == nil || sm.Spec.VolumeTransfer.Method == migrationv1.VolumeTransferSnapshot {
+		bound, err := destinationClaimsBound(ctx, r.MemberClusterClient, run.Namespace, dest, run.Status.Volumes)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("check claims on %s: %w", dest, err)
+		}
+		if !bound {
+			return ctrl.Result{RequeueAfter: withJitter(MigrationRunCheckInterval, 0.2)}, nil
+		}
+		// 목적지의 import된 스냅샷을 먼저 지우고 소스 스냅샷(백엔드 스냅샷 소유)을 마지막에 삭제
+		clusters := append([]string{dest}, run.Status.SourceClusters...)
+		if err := deleteVolumeTransfer(ctx, r.MemberClusterClient, clusters, run.Namespace, run.Name, volumeSnapshotKinds); err != nil {
+			return ctrl.Result{}, fmt.Errorf("delete volume snapshots: %w", err)
+		}
+		r.recordEvent(run, corev1.EventTypeNormal, "VolumeSnapshotsDeleted", "Deleted the volume snapshots of %d claim(s)", len(run.Status.Volumes))
+	}
+	return ctrl.Result{}, r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+		for i := range st.Volumes {
+			if st.Volumes[i].Phase == VolumePhaseReady {
+				st.Volumes[i].Phase = VolumePhaseCleanedUp
+			}
+		}
+	})
+}
+
+// abort finishes the run with phase and reason. Once dispatching was suspended the original
+// placement is restored: restores and final checkpoints are removed, the PropagationPolicy
+// gets its placement back and dispatching resumes on the source clusters.
+func (r *MigrationRunReconciler) abort(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration, phase migrationv1.MigrationRunPhase, reason string) error {
+	lg := log.FromContext(ctx)
+	lg.Info("Aborting MigrationRun", "run", client.ObjectKeyFromObject(run), "phase", phase, "reason", reason)
+
+	rolledBack := false
+	if run.Status.PropagationPolicy != "" {
+		if err := r.rollback(ctx, run, sm); err != nil {
+			return fmt.Errorf("roll back MigrationRun: %w", err)
+		}
+		rolledBack = true
+		r.recordEvent(run, corev1.EventTypeNormal, "RolledBack", "Restored the original placement %v", run.Status.SourceClusters)
+	} else if sm != nil {
+		if err := r.releaseStatefulMigration(ctx, run, sm, nil); err != nil {
+			return err
+		}
+	}
+
+	reasonEvent := "MigrationFailed"
+	if phase == migrationv1.MigrationRunCancelled {
+		reasonEvent = "MigrationCancelled"
+	}
+	r.recordEvent(run, corev1.EventTypeWarning, reasonEvent, "%s", reason)
+	return r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+		now := metav1.Now()
+		st.Phase = phase
+		st.Message = reason
+		st.CompletionTime = &now
+		st.RolledBack = rolledBack
+	})
+}
+
+// rollback undoes every step taken so far; each part is safe to repeat
+func (r *MigrationRunReconciler) rollback(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration) error {
+	// 1) Restore가 남아 있으면 웹훅이 체크포인트 이미지로 바꾸므로 먼저 삭제
+	if sm != nil {
+		switch run.Status.Phase {
+		case migrationv1.MigrationRunRetargeting, migrationv1.MigrationRunRestoring, migrationv1.MigrationRunResuming:
+			if err := deleteRestoreArtifacts(ctx, r.KarmadaClient, sm); err != nil {
+				return fmt.Errorf("delete restores: %w", err)
+			}
+		}
+	}
+	// 2) 최종 체크포인트 정리
+	if err := deleteFinalCheckpoints(ctx, r.KarmadaClient, run.Namespace, map[string]string{LabelMigrationRun: run.Name}); err != nil {
+		return err
+	}
+	// 3) 볼륨 이전 정리: 목적지 PVC와 스냅샷, 복사용 pod (소스 PVC에는 라벨이 없어 유지됨)
+	if sm != nil && sm.Spec.VolumeTransfer != nil && len(run.Status.Volumes) > 0 {
+		clusters := append([]string{run.Spec.DestinationCluster}, run.Status.SourceClusters...)
+		if err := deleteVolumeTransfer(ctx, r.MemberClusterClient, clusters, run.Namespace, run.Name, volumeTransferKinds(sm.Spec.VolumeTransfer, true)); err != nil {
+			return fmt.Errorf("delete volume transfer: %w", err)
+		}
+	}
+	// 4) PP: 원래 placement 복원 + dispatching 재개
+	if err := r.updatePropagationPolicy(ctx, run, func(pp *karmadav1alpha1.PropagationPolicy) error {
+		if raw, ok := pp.Annotations[AnnoOriginalPlacement]; ok {
+			var placement karmadav1alpha1.Placement
+			if err := json.Unmarshal([]byte(raw), &placement); err != nil {
+				return fmt.Errorf("decode %s: %w", AnnoOriginalPlacement, err)
+			}
+			pp.Spec.Placement = placement
+			delete(pp.Annotations, AnnoOriginalPlacement)
+		}
+		pp.Spec.Suspension = nil
+		return nil
+	}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("restore PropagationPolicy: %w", err)
+	}
+	// 5) RB: 복원 보류 해제 + dispatching 재개
+	if err := r.updateResourceBinding(ctx, run, func(rb *unstructured.Unstructured) error {
+		ann := rb.GetAnnotations()
+		if ann[AnnoRestorePhase] == "pending" {
+			delete(ann, AnnoRestorePhase)
+		}
+		if ann[AnnoRestoreGeneration] == run.Name {
+			delete(ann, AnnoRestoreGeneration)
+		}
+		rb.SetAnnotations(ann)
+		return unstructured.SetNestedField(rb.Object, false, "spec", "suspension", "dispatching")
+	}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("resume ResourceBinding: %w", err)
+	}
+	// 6) SM 점유 해제
+	if sm != nil {
+		return r.releaseStatefulMigration(ctx, run, sm, nil)
+	}
+	return nil
+}
+
+// reconcileDelete rolls back a run deleted before it finished and removes its final checkpoints
+func (r *MigrationRunReconciler) reconcileDelete(ctx context.Context, run *migrationv1.MigrationRun) error {
+	if !controllerutil.ContainsFinalizer(run, MigrationRunFinalizer) {
+		return nil
+	}
+	if !migrationRunFinished(run.Status.Phase) {
+		sm, err := r.getStatefulMigration(ctx, run)
+		if err != nil {
+			return err
+		}
+		if err := r.abort(ctx, run, sm, migrationv1.MigrationRunCancelled, "MigrationRun deleted"); err != nil {
+			return err
+		}
+	}
+	if err := deleteFinalCheckpoints(ctx, r.KarmadaClient, run.Namespace, map[string]string{LabelMigrationRun: run.Name}); err != nil {
+		return err
+	}
+	// abort가 상태를 갱신해 resourceVersion이 바뀌었으므로 Update 대신 patch
+	patch := client.MergeFrom(run.DeepCopy())
+	controllerutil.RemoveFinalizer(run, MigrationRunFinalizer)
+	return r.Patch(ctx, run, patch)
+}
+
+// releaseStatefulMigration removes the run's claim on the StatefulMigration, applying mutate in the same update
+func (r *MigrationRunReconciler) releaseStatefulMigration(ctx context.Context, run *migrationv1.MigrationRun, sm *migrationv1.StatefulMigration, mutate func(*migrationv1.StatefulMigration)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest migrationv1.StatefulMigration
+		if err := r.Get(ctx, client.ObjectKeyFromObject(sm), &latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if latest.Annotations[AnnoMigrationRun] != run.Name {
+			return nil
+		}
+		delete(latest.Annotations, AnnoMigrationRun)
+		if mutate != nil {
+			mutate(&latest)
+		}
+		if err := r.Update(ctx, &latest); err != nil {
+			return err
+		}
+		sm.ObjectMeta = latest.ObjectMeta
+		sm.Spec = latest.Spec
+		return nil
+	})
+}
+
+// updatePropagationPolicy applies mutate to the workload's PropagationPolicy on Karmada
+func (r *MigrationRunReconciler) updatePropagationPolicy(ctx context.Context, run *migrationv1.MigrationRun, mutate func(*karmadav1alpha1.PropagationPolicy) error) error {
+	ns, name, ok := strings.Cut(run.Status.PropagationPolicy, "/")
+	if !ok {
+		return fmt.Errorf("invalid PropagationPolicy reference %q", run.Status.PropagationPolicy)
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pp := &karmadav1alpha1.PropagationPolicy{}
+		if err := r.KarmadaClient.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, pp); err != nil {
+			return err
+		}
+		orig := pp.DeepCopy()
+		if err := mutate(pp); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(orig, pp) {
+			return nil
+		}
+		return r.KarmadaClient.Update(ctx, pp)
+	})
+}
+
+// updateResourceBinding applies mutate to the workload's ResourceBinding on Karmada
+func (r *MigrationRunReconciler) updateResourceBinding(ctx context.Context, run *migrationv1.MigrationRun, mutate func(*unstructured.Unstructured) error) error {
+	key := types.NamespacedName{Namespace: run.Namespace, Name: run.Status.ResourceBinding}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		rb := newResourceBindingU()
+		if err := r.KarmadaClient.Get(ctx, key, rb); err != nil {
+			return err
+		}
+		orig := rb.DeepCopy()
+		if err := mutate(rb); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(orig.Object, rb.Object) {
+			return nil
+		}
+		return r.KarmadaClient.Update(ctx, rb)
+	})
+}
+
+// getStatefulMigration returns the run's StatefulMigration, or nil when it does not exist
+func (r *MigrationRunReconciler) getStatefulMigration(ctx context.Context, run *migrationv1.MigrationRun) (*migrationv1.StatefulMigration, error) {
+	var sm migrationv1.StatefulMigration
+	if err := r.Get(ctx, types.NamespacedName{Namespace: run.Namespace, Name: run.Spec.StatefulMigrationName}, &sm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &sm, nil
+}
+
+// setMessage moves the run to phase with message
+func (r *MigrationRunReconciler) setMessage(ctx context.Context, run *migrationv1.MigrationRun, phase migrationv1.MigrationRunPhase, message string) error {
+	return r.updateStatus(ctx, run, func(st *migrationv1.MigrationRunStatus) {
+		st.Phase = phase
+		st.Message = message
+	})
+}
+
+// updateStatus applies mutate to the run's status, skipping the write when nothing changed
+func (r *MigrationRunReconciler) updateStatus(ctx context.Context, run *migrationv1.MigrationRun, mutate func(*migrationv1.MigrationRunStatus)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest migrationv1.MigrationRun
+		if err := r.Get(ctx, client.ObjectKeyFromObject(run), &latest); err != nil {
+			return err
+		}
+		orig := latest.Status.DeepCopy()
+		mutate(&latest.Status)
+		if !reflect.DeepEqual(orig, &latest.Status) {
+			if err := r.Status().Update(ctx, &latest); err != nil {
+				return err
+			}
+		}
+		run.Status = latest.Status
+		return nil
+	})
+}
+
+// recordEvent records an event on the MigrationRun when a recorder is configured
+func (r *MigrationRunReconciler) recordEvent(run *migrationv1.MigrationRun, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(run, eventType, reason, messageFmt, args...)
+	}
+}
+
+// migrationRunTimeout returns the deadline configured on the MigrationRun
+func migrationRunTimeout(run *migrationv1.MigrationRun) time.Duration {
+	if t := run.Spec.Timeout; t != nil && t.Duration > 0 {
+		return t.Duration
+	}
+	return DefaultMigrationRunTimeout
+}
+
+// migrationRunFinished reports whether phase is terminal
+func migrationRunFinished(phase migrationv1.MigrationRunPhase) bool {
+	return phase == migrationv1.MigrationRunSucceeded ||
+		phase == migrationv1.MigrationRunFailed ||
+		phase == migrationv1.MigrationRunCancelled
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MigrationRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.KarmadaClient == nil {
+		return fmt.Errorf("Karmada client not initialized")
+	}
+	if r.MemberClusterClient == nil {
+		memberClient, err := NewMemberClusterClient(r.KarmadaClient)
+		if err != nil {
+			return fmt.Errorf("create member cluster client: %w", err)
+		}
+		r.MemberClusterClient = memberClient
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("migrationrun")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&migrationv1.MigrationRun{}).
+		Named("migrationrun").
+		Complete(r)
+}
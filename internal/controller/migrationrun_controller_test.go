@@ -0,0 +1,485 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// migrationRunTest moves the workload app/db from member1 to member2 with the MigrationRun app/move.
+// Karmada, the restore controller and the checkpoint agent are played by the test.
+type migrationRunTest struct {
+	t        *testing.T
+	ctx      context.Context
+	c        client.Client
+	proxy    *fakeMemberProxy
+	recorder *record.FakeRecorder
+	r        *MigrationRunReconciler
+	// 다음 ResourceBinding 업데이트를 실패시킬 횟수
+	failRBUpdates int
+}
+
+func newMigrationRunTest(t *testing.T) *migrationRunTest {
+	tc := &migrationRunTest{t: t, ctx: context.Background(), proxy: newFakeMemberProxy(t), recorder: record.NewFakeRecorder(100)}
+
+	sm := &migrationv1.StatefulMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "app", UID: "sm-uid"},
+		Spec: migrationv1.StatefulMigrationSpec{
+			ResourceRef:    migrationv1.ResourceRef{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "app", Name: "db"},
+			SourceClusters: []string{"member1"},
+		},
+	}
+	scheduled := &migrationv1.CheckpointBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-db-0-member1", Namespace: "app", Labels: map[string]string{
+			"stateful-migration": "db", "target-cluster": "member1", "target-pod": "db-0",
+		}},
+		Spec: migrationv1.CheckpointBackupSpec{Schedule: "*/5 * * * *"},
+	}
+	pp := &karmadav1alpha1.PropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-pp", Namespace: "app"},
+		Spec: karmadav1alpha1.PropagationSpec{
+			Placement: karmadav1alpha1.Placement{ClusterAffinity: &karmadav1alpha1.ClusterAffinity{ClusterNames: []string{"member1"}}},
+		},
+	}
+	rb := newBindingU("db-statefulset", "apps/v1", "StatefulSet", "db", false, map[string]string{
+		karmadav1alpha1.PropagationPolicyNameAnnotation:      "db-pp",
+		karmadav1alpha1.PropagationPolicyNamespaceAnnotation: "app",
+	}, "member1")
+	run := &migrationv1.MigrationRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "move", Namespace: "app"},
+		Spec:       migrationv1.MigrationRunSpec{StatefulMigrationName: "db", DestinationCluster: "member2"},
+	}
+
+	tc.c = fake.NewClientBuilder().WithScheme(newTestScheme(t)).
+		WithObjects(sm, scheduled, pp, rb, run).
+		WithStatusSubresource(&migrationv1.MigrationRun{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if u, ok := obj.(*unstructured.Unstructured); ok && u.GetKind() == "ResourceBinding" && tc.failRBUpdates > 0 {
+					tc.failRBUpdates--
+					return errors.New("injected ResourceBinding update failure")
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	tc.r = &MigrationRunReconciler{
+		Client:              tc.c,
+		KarmadaClient:       newTestKarmadaClient(t, tc.c, tc.proxy),
+		MemberClusterClient: newTestMemberClusterClient(t, tc.c, tc.proxy),
+		Recorder:            tc.recorder,
+	}
+	return tc
+}
+
+// reconcile runs one reconcile of the MigrationRun and returns its error
+func (tc *migrationRunTest) reconcile() error {
+	_, err := tc.r.Reconcile(tc.ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "app", Name: "move"}})
+	return err
+}
+
+// step runs one reconcile that must succeed and returns the MigrationRun afterwards
+func (tc *migrationRunTest) step() *migrationv1.MigrationRun {
+	tc.t.Helper()
+	if err := tc.reconcile(); err != nil {
+		tc.t.Fatalf("reconcile: %v", err)
+	}
+	return tc.run()
+}
+
+func (tc *migrationRunTest) run() *migrationv1.MigrationRun {
+	tc.t.Helper()
+	var run migrationv1.MigrationRun
+	if err := tc.c.Get(tc.ctx, types.NamespacedName{Namespace: "app", Name: "move"}, &run); err != nil {
+		tc.t.Fatalf("get MigrationRun: %v", err)
+	}
+	return &run
+}
+
+func (tc *migrationRunTest) sm() *migrationv1.StatefulMigration {
+	tc.t.Helper()
+	var sm migrationv1.StatefulMigration
+	if err := tc.c.Get(tc.ctx, types.NamespacedName{Namespace: "app", Name: "db"}, &sm); err != nil {
+		tc.t.Fatalf("get StatefulMigration: %v", err)
+	}
+	return &sm
+}
+
+func (tc *migrationRunTest) pp() *karmadav1alpha1.PropagationPolicy {
+	tc.t.Helper()
+	var pp karmadav1alpha1.PropagationPolicy
+	if err := tc.c.Get(tc.ctx, types.NamespacedName{Namespace: "app", Name: "db-pp"}, &pp); err != nil {
+		tc.t.Fatalf("get PropagationPolicy: %v", err)
+	}
+	return &pp
+}
+
+func (tc *migrationRunTest) rb() *unstructured.Unstructured {
+	tc.t.Helper()
+	rb := newResourceBindingU()
+	if err := tc.c.Get(tc.ctx, types.NamespacedName{Namespace: "app", Name: "db-statefulset"}, rb); err != nil {
+		tc.t.Fatalf("get ResourceBinding: %v", err)
+	}
+	return rb
+}
+
+// updateRB plays Karmada or the restore controller on the ResourceBinding
+func (tc *migrationRunTest) updateRB(mutate func(rb *unstructured.Unstructured)) {
+	tc.t.Helper()
+	rb := tc.rb()
+	mutate(rb)
+	if err := tc.c.Update(tc.ctx, rb); err != nil {
+		tc.t.Fatalf("update ResourceBinding: %v", err)
+	}
+}
+
+// finalCheckpoints returns the names of the run's final CheckpointBackups on Karmada
+func (tc *migrationRunTest) finalCheckpoints() []string {
+	tc.t.Helper()
+	var list migrationv1.CheckpointBackupList
+	if err := tc.c.List(tc.ctx, &list, client.InNamespace("app"), client.MatchingLabels{LabelMigrationRun: "move"}); err != nil {
+		tc.t.Fatalf("list final checkpoints: %v", err)
+	}
+	var names []string
+	for _, b := range list.Items {
+		names = append(names, b.Name)
+	}
+	return names
+}
+
+// act plays the part of the other components in the phase, so the run can move on
+func (tc *migrationRunTest) act(run *migrationv1.MigrationRun) {
+	switch run.Status.Phase {
+	case migrationv1.MigrationRunCheckpointing:
+		for _, cp := range run.Status.Checkpoints {
+			tc.proxy.add(cp.Cluster, newMemberObject(migrationv1.GroupVersion.String(), "CheckpointBackup", "app", cp.Name,
+				map[string]interface{}{"status": map[string]interface{}{"phase": PhaseCompleted}}))
+		}
+	case migrationv1.MigrationRunRetargeting:
+		tc.updateRB(func(rb *unstructured.Unstructured) {
+			_ = unstructured.SetNestedSlice(rb.Object, []interface{}{map[string]interface{}{"name": "member2"}}, "spec", "clusters")
+		})
+	case migrationv1.MigrationRunRestoring:
+		tc.updateRB(func(rb *unstructured.Unstructured) {
+			ann := rb.GetAnnotations()
+			ann[AnnoRestorePhase] = "succeeded"
+			rb.SetAnnotations(ann)
+		})
+	case migrationv1.MigrationRunResuming:
+		tc.addRestoreWebhook()
+	}
+}
+
+// advanceTo reconciles, acting for the other components, until the run enters phase
+func (tc *migrationRunTest) advanceTo(phase migrationv1.MigrationRunPhase) *migrationv1.MigrationRun {
+	tc.t.Helper()
+	run := tc.run()
+	for i := 0; i < 20; i++ {
+		if run.Status.Phase == phase {
+			return run
+		}
+		tc.act(run)
+		run = tc.step()
+	}
+	tc.t.Fatalf("run did not reach %s, stuck in %s: %s", phase, run.Status.Phase, run.Status.Message)
+	return nil
+}
+
+// addRestoreWebhook makes the restore webhook ready on the destination
+func (tc *migrationRunTest) addRestoreWebhook() {
+	tc.proxy.add("member2", newMemberObject("admissionregistration.k8s.io/v1", "MutatingWebhookConfiguration", "", RestoreWebhookName, nil))
+	tc.proxy.add("member2", newMemberObject("apps/v1", "Deployment", RestoreWebhookNamespace, RestoreWebhookName,
+		map[string]interface{}{"status": map[string]interface{}{"availableReplicas": int64(1)}}))
+}
+
+func TestMigrationRunMovesWorkload(t *testing.T) {
+	tc := newMigrationRunTest(t)
+
+	// 시작: SM 점유, 현재 배치 기록
+	run := tc.step()
+	if run.Status.Phase != migrationv1.MigrationRunSuspending {
+		t.Fatalf("phase = %s, want Suspending", run.Status.Phase)
+	}
+	if !reflect.DeepEqual(run.Status.SourceClusters, []string{"member1"}) || run.Status.PropagationPolicy != "app/db-pp" ||
+		run.Status.ResourceBinding != "db-statefulset" || run.Status.StartTime == nil {
+		t.Fatalf("start status = %+v", run.Status)
+	}
+	if got := tc.sm().Annotations[AnnoMigrationRun]; got != "move" {
+		t.Fatalf("StatefulMigration claimed by %q, want move", got)
+	}
+
+	// 중단: PP/RB dispatching 중단, 복원 보류
+	run = tc.step()
+	if run.Status.Phase != migrationv1.MigrationRunCheckpointing {
+		t.Fatalf("phase = %s, want Checkpointing", run.Status.Phase)
+	}
+	pp := tc.pp()
+	if pp.Spec.Suspension == nil || pp.Spec.Suspension.Dispatching == nil || !*pp.Spec.Suspension.Dispatching {
+		t.Fatalf("PropagationPolicy not suspended: %+v", pp.Spec.Suspension)
+	}
+	if pp.Annotations[AnnoOriginalPlacement] == "" {
+		t.Fatalf("original placement not recorded")
+	}
+	if rb := tc.rb(); !isRBSuspendedU(rb) || getRBAnnotation(rb, AnnoRestorePhase) != "pending" {
+		t.Fatalf("ResourceBinding not suspended and pending: %v", rb.GetAnnotations())
+	}
+
+	// 최종 체크포인트 요청 후 push 완료 대기
+	run = tc.step()
+	want := []migrationv1.MigrationRunCheckpoint{{Name: "move-db-0-member1", Cluster: "member1", PodName: "db-0"}}
+	if !reflect.DeepEqual(run.Status.Checkpoints, want) {
+		t.Fatalf("checkpoints = %+v, want %+v", run.Status.Checkpoints, want)
+	}
+	var final migrationv1.CheckpointBackup
+	if err := tc.c.Get(tc.ctx, types.NamespacedName{Namespace: "app", Name: "move-db-0-member1"}, &final); err != nil {
+		t.Fatalf("final checkpoint: %v", err)
+	}
+	if final.Spec.StopPod == nil || !*final.Spec.StopPod || final.Labels[LabelCheckpointGeneration] != "move" {
+		t.Fatalf("final checkpoint = %+v", final)
+	}
+	if run = tc.step(); run.Status.Phase != migrationv1.MigrationRunCheckpointing {
+		t.Fatalf("phase = %s before the checkpoint was pushed, want Checkpointing", run.Status.Phase)
+	}
+	tc.act(run)
+	if run = tc.step(); run.Status.Phase != migrationv1.MigrationRunRetargeting {
+		t.Fatalf("phase = %s, want Retargeting", run.Status.Phase)
+	}
+
+	// 재배치: Karmada가 RB를 목적지로 옮길 때까지 대기
+	run = tc.step()
+	if run.Status.Phase != migrationv1.MigrationRunRetargeting || !strings.Contains(run.Status.Message, "waiting for Karmada") {
+		t.Fatalf("phase = %s (%s), want Retargeting waiting for Karmada", run.Status.Phase, run.Status.Message)
+	}
+	if got := tc.pp().Spec.Placement.ClusterAffinity.ClusterNames; !reflect.DeepEqual(got, []string{"member2"}) {
+		t.Fatalf("placement = %v, want member2", got)
+	}
+	tc.act(run)
+	if run = tc.step(); run.Status.Phase != migrationv1.MigrationRunRestoring {
+		t.Fatalf("phase = %s, want Restoring", run.Status.Phase)
+	}
+	rb := tc.rb()
+	if getRBAnnotation(rb, AnnoRestoreGeneration) != "move" || getRBAnnotation(rb, AnnoRestorePhase) != "" {
+		t.Fatalf("ResourceBinding not released for restore: %v", rb.GetAnnotations())
+	}
+
+	// 복원 완료 대기
+	if run = tc.step(); run.Status.Phase != migrationv1.MigrationRunRestoring {
+		t.Fatalf("phase = %s before the restore finished, want Restoring", run.Status.Phase)
+	}
+	tc.act(run)
+	if run = tc.step(); run.Status.Phase != migrationv1.MigrationRunResuming {
+		t.Fatalf("phase = %s, want Resuming", run.Status.Phase)
+	}
+
+	// 재개: 목적지 웹훅 준비 후 dispatching 재개
+	if run = tc.step(); run.Status.Phase != migrationv1.MigrationRunResuming || !strings.Contains(run.Status.Message, "webhook not ready") {
+		t.Fatalf("phase = %s (%s), want Resuming until the webhook is ready", run.Status.Phase, run.Status.Message)
+	}
+	tc.addRestoreWebhook()
+	run = tc.step()
+	if run.Status.Phase != migrationv1.MigrationRunSucceeded || run.Status.CompletionTime == nil || run.Status.RolledBack {
+		t.Fatalf("status = %+v, want Succeeded", run.Status)
+	}
+	pp = tc.pp()
+	if pp.Spec.Suspension != nil || pp.Annotations[AnnoOriginalPlacement] != "" {
+		t.Fatalf("PropagationPolicy not resumed: %+v %v", pp.Spec.Suspension, pp.Annotations)
+	}
+	if isRBSuspendedU(tc.rb()) {
+		t.Fatalf("ResourceBinding still suspended")
+	}
+	sm := tc.sm()
+	if sm.Annotations[AnnoMigrationRun] != "" || !reflect.DeepEqual(sm.Spec.SourceClusters, []string{"member2"}) {
+		t.Fatalf("StatefulMigration not released to member2: %v %v", sm.Annotations, sm.Spec.SourceClusters)
+	}
+
+	// 끝난 run은 다시 건드리지 않음
+	if run = tc.step(); run.Status.Phase != migrationv1.MigrationRunSucceeded {
+		t.Fatalf("phase = %s after another reconcile", run.Status.Phase)
+	}
+}
+
+func TestMigrationRunAbortRollsBack(t *testing.T) {
+	tests := []struct {
+		phase migrationv1.MigrationRunPhase
+		// 단계에 들어간 뒤 추가로 실행할 reconcile 수
+		extra int
+		// 중단 전에 복원 컨트롤러가 만든 Restore
+		restore    bool
+		rolledBack bool
+	}{
+		{phase: ""},
+		{phase: migrationv1.MigrationRunSuspending, rolledBack: true},
+		{phase: migrationv1.MigrationRunCheckpointing, extra: 1, rolledBack: true},
+		{phase: migrationv1.MigrationRunRetargeting, extra: 1, rolledBack: true},
+		{phase: migrationv1.MigrationRunRestoring, restore: true, rolledBack: true},
+		{phase: migrationv1.MigrationRunResuming, extra: 1, restore: true, rolledBack: true},
+	}
+	for _, tt := range tests {
+		name := string(tt.phase)
+		if name == "" {
+			name = "NotStarted"
+		}
+		t.Run(name, func(t *testing.T) {
+			tc := newMigrationRunTest(t)
+			if tt.phase != "" {
+				tc.advanceTo(tt.phase)
+				for i := 0; i < tt.extra; i++ {
+					if run := tc.step(); run.Status.Phase != tt.phase {
+						t.Fatalf("left %s for %s", tt.phase, run.Status.Phase)
+					}
+				}
+			}
+			if tt.restore {
+				restore := &migrationv1.CheckpointRestore{ObjectMeta: metav1.ObjectMeta{
+					Name: "db-0-restore", Namespace: "app", Labels: map[string]string{LabelKeySM: "db"},
+				}}
+				if err := tc.c.Create(tc.ctx, restore); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			run := tc.run()
+			run.Spec.Cancel = true
+			if err := tc.c.Update(tc.ctx, run); err != nil {
+				t.Fatal(err)
+			}
+			// 롤백 도중 실패하면 단계를 유지하고 다음 reconcile에서 처음부터 다시 롤백
+			if tt.rolledBack {
+				tc.failRBUpdates = 1
+				if err := tc.reconcile(); err == nil {
+					t.Fatalf("reconcile succeeded despite the injected failure")
+				}
+				if run = tc.run(); run.Status.Phase != tt.phase {
+					t.Fatalf("phase = %s after a failed rollback, want %s", run.Status.Phase, tt.phase)
+				}
+			}
+			run = tc.step()
+			if run.Status.Phase != migrationv1.MigrationRunCancelled || run.Status.RolledBack != tt.rolledBack || run.Status.CompletionTime == nil {
+				t.Fatalf("status = %+v, want Cancelled with rolledBack=%v", run.Status, tt.rolledBack)
+			}
+			tc.checkRolledBack()
+
+			// 끝난 run은 재시도해도 상태가 바뀌지 않음
+			if run = tc.step(); run.Status.Phase != migrationv1.MigrationRunCancelled {
+				t.Fatalf("phase = %s after another reconcile", run.Status.Phase)
+			}
+			tc.checkRolledBack()
+			if tt.rolledBack {
+				if err := tc.r.rollback(tc.ctx, run, tc.sm()); err != nil {
+					t.Fatalf("repeated rollback: %v", err)
+				}
+				tc.checkRolledBack()
+			}
+		})
+	}
+}
+
+// checkRolledBack verifies that the workload is back on member1 as before the run
+func (tc *migrationRunTest) checkRolledBack() {
+	tc.t.Helper()
+	pp := tc.pp()
+	if got := pp.Spec.Placement.ClusterAffinity; got == nil || !reflect.DeepEqual(got.ClusterNames, []string{"member1"}) {
+		tc.t.Errorf("placement = %+v, want member1", got)
+	}
+	if pp.Spec.Suspension != nil || pp.Annotations[AnnoOriginalPlacement] != "" {
+		tc.t.Errorf("PropagationPolicy still suspended: %+v %v", pp.Spec.Suspension, pp.Annotations)
+	}
+	rb := tc.rb()
+	if isRBSuspendedU(rb) {
+		tc.t.Errorf("ResourceBinding still suspended")
+	}
+	if getRBAnnotation(rb, AnnoRestorePhase) == "pending" || getRBAnnotation(rb, AnnoRestoreGeneration) != "" {
+		tc.t.Errorf("ResourceBinding still held for the run: %v", rb.GetAnnotations())
+	}
+	if names := tc.finalCheckpoints(); len(names) != 0 {
+		tc.t.Errorf("final checkpoints left: %v", names)
+	}
+	var policy karmadav1alpha1.PropagationPolicy
+	if err := tc.c.Get(tc.ctx, types.NamespacedName{Namespace: "app", Name: "move-db-0-member1-policy"}, &policy); !apierrors.IsNotFound(err) {
+		tc.t.Errorf("final checkpoint policy left: %v", err)
+	}
+	var restores migrationv1.CheckpointRestoreList
+	if err := tc.c.List(tc.ctx, &restores, client.InNamespace("app")); err != nil || len(restores.Items) != 0 {
+		tc.t.Errorf("restores left: %d (%v)", len(restores.Items), err)
+	}
+	sm := tc.sm()
+	if sm.Annotations[AnnoMigrationRun] != "" || !reflect.DeepEqual(sm.Spec.SourceClusters, []string{"member1"}) {
+		tc.t.Errorf("StatefulMigration = %v %v, want released on member1", sm.Annotations, sm.Spec.SourceClusters)
+	}
+}
+
+func TestMigrationRunReconcileDelete(t *testing.T) {
+	t.Run("unfinished run rolls back", func(t *testing.T) {
+		tc := newMigrationRunTest(t)
+		tc.advanceTo(migrationv1.MigrationRunRetargeting)
+		if len(tc.finalCheckpoints()) == 0 {
+			t.Fatalf("no final checkpoints before the delete")
+		}
+		if err := tc.c.Delete(tc.ctx, tc.run()); err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.reconcile(); err != nil {
+			t.Fatalf("reconcile: %v", err)
+		}
+		var run migrationv1.MigrationRun
+		if err := tc.c.Get(tc.ctx, types.NamespacedName{Namespace: "app", Name: "move"}, &run); !apierrors.IsNotFound(err) {
+			t.Fatalf("MigrationRun still there (finalizers %v): %v", run.Finalizers, err)
+		}
+		tc.checkRolledBack()
+	})
+
+	t.Run("finished run keeps the placement", func(t *testing.T) {
+		tc := newMigrationRunTest(t)
+		tc.advanceTo(migrationv1.MigrationRunSucceeded)
+		if err := tc.c.Delete(tc.ctx, tc.run()); err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.reconcile(); err != nil {
+			t.Fatalf("reconcile: %v", err)
+		}
+		var run migrationv1.MigrationRun
+		if err := tc.c.Get(tc.ctx, types.NamespacedName{Namespace: "app", Name: "move"}, &run); !apierrors.IsNotFound(err) {
+			t.Fatalf("MigrationRun still there (finalizers %v): %v", run.Finalizers, err)
+		}
+		if names := tc.finalCheckpoints(); len(names) != 0 {
+			t.Errorf("final checkpoints left: %v", names)
+		}
+		if got := tc.pp().Spec.Placement.ClusterAffinity.ClusterNames; !reflect.DeepEqual(got, []string{"member2"}) {
+			t.Errorf("placement = %v, want member2", got)
+		}
+		if isRBSuspendedU(tc.rb()) {
+			t.Errorf("ResourceBinding suspended again")
+		}
+	})
+}
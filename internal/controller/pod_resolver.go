@@ -0,0 +1,225 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+const (
+	// DefaultPodSelectorPath is the selector field used when PodResolution.SelectorPath is empty
+	DefaultPodSelectorPath = "spec.selector"
+
+	// DefaultMaxOwnerDepth is the owner reference depth used when PodResolution.MaxOwnerDepth is not set
+	DefaultMaxOwnerDepth = 3
+)
+
+// resolvePodsWithPodResolution finds the pods of the referenced workload on a member cluster
+// using the strategy declared in spec.podResolution. It also returns the label selector the
+// pods were listed by, or nil for the OwnerReference strategy.
+func (r *MigrationBackupReconciler) resolvePodsWithPodResolution(ctx context.Context, sm *migrationv1.StatefulMigration, cluster string) ([]corev1.Pod, labels.Selector, error) {
+	res := sm.Spec.PodResolution
+	ref := sm.Spec.ResourceRef
+
+	switch res.Strategy {
+	case migrationv1.PodResolutionLabelSelector:
+		if res.LabelSelector == nil {
+			return nil, nil, fmt.Errorf("podResolution.labelSelector is required for the %s strategy", res.Strategy)
+		}
+		sel, err := metav1.LabelSelectorAsSelector(res.LabelSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid podResolution.labelSelector: %w", err)
+		}
+		if sel.Empty() {
+			return nil, nil, fmt.Errorf("podResolution.labelSelector must not be empty")
+		}
+		pods, err := r.MemberClusterClient.ListPodsBySelector(ctx, cluster, ref.Namespace, sel)
+		return pods, sel, err
+
+	case migrationv1.PodResolutionSelectorPath:
+		obj, err := r.MemberClusterClient.GetResourceFromCluster(ctx, cluster, ref.APIVersion, ref.Kind, ref.Namespace, ref.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		path := res.SelectorPath
+		if path == "" {
+			path = DefaultPodSelectorPath
+		}
+		sel, err := selectorFromPath(obj, path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s %s/%s: %w", ref.Kind, ref.Namespace, ref.Name, err)
+		}
+		pods, err := r.MemberClusterClient.ListPodsBySelector(ctx, cluster, ref.Namespace, sel)
+		return pods, sel, err
+
+	case migrationv1.PodResolutionOwnerReference:
+		obj, err := r.MemberClusterClient.GetResourceFromCluster(ctx, cluster, ref.APIVersion, ref.Kind, ref.Namespace, ref.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		depth := DefaultMaxOwnerDepth
+		if res.MaxOwnerDepth != nil {
+			depth = int(*res.MaxOwnerDepth)
+		}
+		pods, err := r.listPodsOwnedBy(ctx, cluster, obj, depth)
+		return pods, nil, err
+
+	default:
+		return nil, nil, fmt.Errorf("unsupported pod resolution strategy: %s", res.Strategy)
+	}
+}
+
+// selectorFromPath reads a pod selector at the dot-separated path of the object.
+// The field may be a LabelSelector, a plain label map or a selector string (e.g. status.selector of the scale subresource)
+func selectorFromPath(obj *unstructured.Unstructured, path string) (labels.Selector, error) {
+	fields := strings.Split(strings.Trim(path, "."), ".")
+	val, found, err := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+	if err != nil {
+		return nil, fmt.Errorf("read selector path %q: %w", path, err)
+	}
+	if !found || val == nil {
+		return nil, fmt.Errorf("selector path %q not found", path)
+	}
+
+	var sel labels.Selector
+	switch v := val.(type) {
+	case string:
+		sel, err = labels.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("parse selector at %q: %w", path, err)
+		}
+	case map[string]interface{}:
+		_, hasMatchLabels := v["matchLabels"]
+		_, hasMatchExpressions := v["matchExpressions"]
+		if hasMatchLabels || hasMatchExpressions {
+			var ls metav1.LabelSelector
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(v, &ls); err != nil {
+				return nil, fmt.Errorf("decode label selector at %q: %w", path, err)
+			}
+			sel, err = metav1.LabelSelectorAsSelector(&ls)
+			if err != nil {
+				return nil, fmt.Errorf("convert label selector at %q: %w", path, err)
+			}
+		} else {
+			set := labels.Set{}
+			for k, lv := range v {
+				s, ok := lv.(string)
+				if !ok {
+					return nil, fmt.Errorf("selector at %q has non-string value for label %q", path, k)
+				}
+				set[k] = s
+			}
+			sel = labels.SelectorFromSet(set)
+		}
+	default:
+		return nil, fmt.Errorf("selector path %q has unsupported type %T", path, val)
+	}
+
+	// An empty selector would match every pod in the namespace
+	if sel.Empty() {
+		return nil, fmt.Errorf("selector at %q is empty", path)
+	}
+	return sel, nil
+}
+
+// listPodsOwnedBy returns the pods whose owner reference chain reaches the owner within maxDepth hops
+func (r *MigrationBackupReconciler) listPodsOwnedBy(ctx context.Context, cluster string, owner *unstructured.Unstructured, maxDepth int) ([]corev1.Pod, error) {
+	podList, err := r.MemberClusterClient.ListPodsFromCluster(ctx, cluster, owner.GetNamespace(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Intermediate owners (e.g. ReplicaSets) are shared by many pods, so their references are fetched once
+	ownerRefsByUID := map[types.UID][]metav1.OwnerReference{}
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		owned, err := r.ownerChainContains(ctx, cluster, pod.Namespace, pod.OwnerReferences, owner.GetUID(), maxDepth, ownerRefsByUID)
+		if err != nil {
+			return nil, fmt.Errorf("walk owner references of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		if owned {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func (r *MigrationBackupReconciler) ownerChainContains(ctx context.Context, cluster, namespace string, refs []metav1.OwnerReference, uid types.UID, depth int, ownerRefsByUID map[types.UID][]metav1.OwnerReference) (bool, error) {
+	if depth <= 0 {
+		return false, nil
+	}
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true, nil
+		}
+	}
+	if depth == 1 {
+		return false, nil
+	}
+	for _, ref := range refs {
+		next, cached := ownerRefsByUID[ref.UID]
+		if !cached {
+			obj, err := r.MemberClusterClient.GetResourceFromCluster(ctx, cluster, ref.APIVersion, ref.Kind, namespace, ref.Name)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return false, err
+			}
+			if obj != nil {
+				next = obj.GetOwnerReferences()
+			}
+			ownerRefsByUID[ref.UID] = next
+		}
+		found, err := r.ownerChainContains(ctx, cluster, namespace, next, uid, depth-1, ownerRefsByUID)
+		if err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
+// setCustomResourceLabel adds or removes the checkpoint migration label on a workload of a custom kind
+func (r *MigrationBackupReconciler) setCustomResourceLabel(ctx context.Context, cluster string, ref migrationv1.ResourceRef, enabled bool) error {
+	obj, err := r.MemberClusterClient.GetResourceFromCluster(ctx, cluster, ref.APIVersion, ref.Kind, ref.Namespace, ref.Name)
+	if err != nil {
+		return err
+	}
+	lbls := obj.GetLabels()
+	if enabled == (lbls[CheckpointMigrationLabel] == "true") {
+		return nil
+	}
+	if lbls == nil {
+		lbls = map[string]string{}
+	}
+	if enabled {
+		lbls[CheckpointMigrationLabel] = "true"
+	} else {
+		delete(lbls, CheckpointMigrationLabel)
+	}
+	obj.SetLabels(lbls)
+	return r.MemberClusterClient.UpdateResourceInCluster(ctx, cluster, obj)
+}
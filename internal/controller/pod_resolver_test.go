@@ -0,0 +1,111 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSelectorFromPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		object  map[string]interface{}
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "label selector",
+			object: map[string]interface{}{"spec": map[string]interface{}{"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": "db"},
+			}}},
+			path: "spec.selector",
+			want: "app=db",
+		},
+		{
+			name: "label selector with expressions",
+			object: map[string]interface{}{"spec": map[string]interface{}{"selector": map[string]interface{}{
+				"matchExpressions": []interface{}{map[string]interface{}{
+					"key": "tier", "operator": "In", "values": []interface{}{"cache"},
+				}},
+			}}},
+			path: "spec.selector",
+			want: "tier in (cache)",
+		},
+		{
+			name:   "plain label map",
+			object: map[string]interface{}{"spec": map[string]interface{}{"podLabels": map[string]interface{}{"app": "kv"}}},
+			path:   "spec.podLabels",
+			want:   "app=kv",
+		},
+		{
+			name:   "selector string with surrounding dots",
+			object: map[string]interface{}{"status": map[string]interface{}{"selector": "app=web,tier=front"}},
+			path:   ".status.selector.",
+			want:   "app=web,tier=front",
+		},
+		{
+			name:    "missing path",
+			object:  map[string]interface{}{"spec": map[string]interface{}{}},
+			path:    "spec.selector",
+			wantErr: true,
+		},
+		{
+			name:    "empty selector matches everything",
+			object:  map[string]interface{}{"spec": map[string]interface{}{"selector": map[string]interface{}{}}},
+			path:    "spec.selector",
+			wantErr: true,
+		},
+		{
+			name:    "non-string label value",
+			object:  map[string]interface{}{"spec": map[string]interface{}{"selector": map[string]interface{}{"app": int64(1)}}},
+			path:    "spec.selector",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			object:  map[string]interface{}{"spec": map[string]interface{}{"selector": []interface{}{"app=db"}}},
+			path:    "spec.selector",
+			wantErr: true,
+		},
+		{
+			name:    "invalid selector string",
+			object:  map[string]interface{}{"status": map[string]interface{}{"selector": "app in ("}},
+			path:    "status.selector",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel, err := selectorFromPath(&unstructured.Unstructured{Object: tt.object}, tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got selector %q", sel)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := sel.String(); got != tt.want {
+				t.Errorf("selector = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// backupStatusTTL is how long the CheckpointBackup statuses listed from a member cluster namespace are
+// reused, so the checks of a waiting restore and of other workloads share one LIST per interval
+const backupStatusTTL = RestoreCheckInterval
+
+// backupStatusCache remembers the last CheckpointBackup LIST of each member cluster and namespace
+type backupStatusCache struct {
+	mu      sync.Mutex
+	entries map[string]backupStatusEntry
+}
+
+type backupStatusEntry struct {
+	at       time.Time
+	statuses map[string]*migrationv1.CheckpointBackupStatus
+	err      error
+}
+
+// restoreCandidate is the checkpoint chosen to restore one pod
+type restoreCandidate struct {
+	backup *unstructured.Unstructured
+	// spec.containers of the CheckpointRestore: images pinned to <repository>@<digest>
+	containers []interface{}
+	// when the newest of the chosen images was built
+	checkpointTime time.Time
+}
+
+// restoreSourceFor returns where the restore of the RB takes its checkpoints from. A generation set on
+// the RB by a MigrationRun or a failover wins over spec.restorePolicy.source.
+func restoreSourceFor(rb *unstructured.Unstructured, sm *migrationv1.StatefulMigration) migrationv1.RestoreSource {
+	switch gen := getRBAnnotation(rb, AnnoRestoreGeneration); gen {
+	case "":
+	case CheckpointGenerationLatest:
+		return migrationv1.RestoreSource{Type: migrationv1.RestoreSourceLatest}
+	default:
+		return migrationv1.RestoreSource{Type: migrationv1.RestoreSourceGeneration, Generation: gen}
+	}
+	if p := sm.Spec.RestorePolicy; p != nil && p.Source != nil {
+		src := *p.Source
+		if src.Type == "" {
+			src.Type = migrationv1.RestoreSourceLatest
+		}
+		return src
+	}
+	return migrationv1.RestoreSource{Type: migrationv1.RestoreSourceLatest}
+}
+
+// describeRestoreSource returns the value reported in status.restore.generation
+func describeRestoreSource(src migrationv1.RestoreSource) string {
+	switch src.Type {
+	case migrationv1.RestoreSourceGeneration:
+		return src.Generation
+	case migrationv1.RestoreSourcePointInTime:
+		if src.Time != nil {
+			return "before " + src.Time.UTC().Format(time.RFC3339)
+		}
+	}
+	return CheckpointGenerationLatest
+}
+
+// selectRestoreCandidates picks one checkpoint per pod from the backups according to src. Backup
+// status only exists on the member clusters, so it is listed through the Karmada proxy once per
+// member cluster and namespace. A pod is blocking, with the reason as value, when none of its
+// backups has a complete checkpoint.
+func (r *MigrationRestoreReconciler) selectRestoreCandidates(ctx context.Context, backups []unstructured.Unstructured, src migrationv1.RestoreSource) ([]restoreCandidate, map[string]string, error) {
+	var before *time.Time
+	switch src.Type {
+	case migrationv1.RestoreSourceGeneration:
+		if src.Generation == "" {
+			return nil, nil, fmt.Errorf("restorePolicy.source.generation is required for the Generation source")
+		}
+		backups = backupsForGeneration(backups, src.Generation)
+	case migrationv1.RestoreSourcePointInTime:
+		if src.Time == nil {
+			return nil, nil, fmt.Errorf("restorePolicy.source.time is required for the PointInTime source")
+		}
+		before = &src.Time.Time
+	}
+
+	byPod := map[string]restoreCandidate{}
+	blocking := map[string]string{}
+	for i := range backups {
+		backup := &backups[i]
+		pod := backupPodName(backup)
+		status, err := r.memberBackupStatus(ctx, backup)
+		if err != nil {
+			log.FromContext(ctx).Info("Cannot read CheckpointBackup status", "backup", backup.GetName(), "error", err.Error())
+			blocking[pod] = fmt.Sprintf("%s: status unavailable", backup.GetName())
+			continue
+		}
+		candidate, reason := candidateFromStatus(backup, status, before)
+		if reason != "" {
+			blocking[pod] = fmt.Sprintf("%s: %s", backup.GetName(), reason)
+			continue
+		}
+		if curr, found := byPod[pod]; !found || candidate.checkpointTime.After(curr.checkpointTime) {
+			byPod[pod] = candidate
+		}
+	}
+
+	pods := make([]string, 0, len(byPod))
+	for pod := range byPod {
+		pods = append(pods, pod)
+		delete(blocking, pod)
+	}
+	sort.Strings(pods)
+	out := make([]restoreCandidate, 0, len(pods))
+	for _, pod := range pods {
+		out = append(out, byPod[pod])
+	}
+	return out, blocking, nil
+}
+
+// memberBackupStatus reads the status of the backup from the member cluster it was propagated to
+func (r *MigrationRestoreReconciler) memberBackupStatus(ctx context.Context, backup *unstructured.Unstructured) (*migrationv1.CheckpointBackupStatus, error) {
+	if r.MemberClusterClient == nil {
+		return nil, fmt.Errorf("member cluster client not initialized")
+	}
+	cluster := backup.GetLabels()["target-cluster"]
+	if cluster == "" {
+		return nil, fmt.Errorf("no target-cluster label")
+	}
+	statuses, err := r.memberBackupStatuses(ctx, cluster, backup.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	status, found := statuses[backup.GetName()]
+	if !found {
+		return nil, fmt.Errorf("not found on cluster %s", cluster)
+	}
+	return status, nil
+}
+
+// memberBackupStatuses returns the status of every CheckpointBackup in the namespace of the member
+// cluster by name. A LIST younger than backupStatusTTL, or its error, is reused.
+func (r *MigrationRestoreReconciler) memberBackupStatuses(ctx context.Context, cluster, ns string) (map[string]*migrationv1.CheckpointBackupStatus, error) {
+	r.backupStatuses.mu.Lock()
+	defer r.backupStatuses.mu.Unlock()
+	key := cluster + "/" + ns
+	if e, found := r.backupStatuses.entries[key]; found && time.Since(e.at) < backupStatusTTL {
+		return e.statuses, e.err
+	}
+	statuses, err := r.listMemberBackupStatuses(ctx, cluster, ns)
+	// 취소된 요청의 오류는 다음 확인에 남기지 않음
+	if ctx.Err() != nil {
+		return statuses, err
+	}
+	if r.backupStatuses.entries == nil {
+		r.backupStatuses.entries = map[string]backupStatusEntry{}
+	}
+	r.backupStatuses.entries[key] = backupStatusEntry{at: time.Now(), statuses: statuses, err: err}
+	return statuses, err
+}
+
+// listMemberBackupStatuses lists the CheckpointBackups of the namespace on the member cluster and decodes their status.
+// Backups whose status cannot be decoded are left out.
+func (r *MigrationRestoreReconciler) listMemberBackupStatuses(ctx context.Context, cluster, ns string) (map[string]*migrationv1.CheckpointBackupStatus, error) {
+	list, err := r.MemberClusterClient.ListResourcesFromCluster(ctx, cluster, migrationv1.GroupVersion.String(), "CheckpointBackup", ns, "")
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*migrationv1.CheckpointBackupStatus, len(list.Items))
+	for i := range list.Items {
+		status := &migrationv1.CheckpointBackupStatus{}
+		if raw, found, _ := unstructured.NestedMap(list.Items[i].Object, "status"); found {
+			// 해석할 수 없는 상태는 해당 Backup만 확인 불가로 남김
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, status); err != nil {
+				log.FromContext(ctx).Info("Cannot decode CheckpointBackup status", "cluster", cluster, "backup", list.Items[i].GetName(), "error", err.Error())
+				continue
+			}
+		}
+		out[list.Items[i].GetName()] = status
+	}
+	return out, nil
+}
+
+// candidateFromStatus pins every container of the backup to the newest pushed image built at or
+// before the given time. Without a time, the latest run must have completed. The returned reason
+// explains why the backup cannot be restored yet.
+func candidateFromStatus(backup *unstructured.Unstructured, status *migrationv1.CheckpointBackupStatus, before *time.Time) (restoreCandidate, string) {
+	if before == nil {
+		switch status.Phase {
+		case PhaseCompleted, PhaseCompletedPodDeleted:
+		case "":
+			return restoreCandidate{}, "no checkpoint taken yet"
+		case PhaseFailed, PhaseCompletedWithError:
+			return restoreCandidate{}, fmt.Sprintf("%s: %s", status.Phase, status.Message)
+		default:
+			return restoreCandidate{}, status.Phase
+		}
+	}
+	specContainers, _, _ := unstructured.NestedSlice(backup.Object, "spec", "containers")
+	candidate := restoreCandidate{backup: backup}
+	for _, it := range specContainers {
+		m, ok := it.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		image, _ := m["image"].(string)
+		var pick *migrationv1.BuiltImage
+		for j := range status.BuiltImages {
+			img := &status.BuiltImages[j]
+			if img.ContainerName != name || !img.Pushed || img.Digest == "" || img.BuildTime == nil {
+				continue
+			}
+			if before != nil && img.BuildTime.After(*before) {
+				continue
+			}
+			if pick == nil || img.BuildTime.After(pick.BuildTime.Time) {
+				pick = img
+			}
+		}
+		if pick == nil {
+			if before != nil {
+				return restoreCandidate{}, fmt.Sprintf("container %s has no image pushed before %s", name, before.UTC().Format(time.RFC3339))
+			}
+			return restoreCandidate{}, fmt.Sprintf("container %s has no pushed image", name)
+		}
+		candidate.containers = append(candidate.containers, map[string]interface{}{
+			"name":  name,
+			"image": imageRepository(image) + "@" + pick.Digest,
+		})
+		if pick.BuildTime.After(candidate.checkpointTime) {
+			candidate.checkpointTime = pick.BuildTime.Time
+		}
+	}
+	if len(candidate.containers) == 0 {
+		return restoreCandidate{}, "no container is pushed to a registry"
+	}
+	return candidate, ""
+}
+
+// backupPodName returns the pod the backup checkpoints
+func backupPodName(backup *unstructured.Unstructured) string {
+	if pod := backup.GetLabels()["target-pod"]; pod != "" {
+		return pod
+	}
+	pod, _, _ := unstructured.NestedString(backup.Object, "spec", "podRef", "name")
+	return pod
+}
+
+// imageRepository strips the tag and digest from an image reference
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+func TestImageRepository(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "nginx", want: "nginx"},
+		{image: "nginx:1.27", want: "nginx"},
+		{image: "docker.io/library/nginx:1.27", want: "docker.io/library/nginx"},
+		{image: "registry:5000/team/app", want: "registry:5000/team/app"},
+		{image: "registry:5000/team/app:v2", want: "registry:5000/team/app"},
+		{image: "registry:5000/team/app@sha256:abc", want: "registry:5000/team/app"},
+		{image: "registry:5000/team/app:v2@sha256:abc", want: "registry:5000/team/app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := imageRepository(tt.image); got != tt.want {
+				t.Errorf("imageRepository(%q) = %q, want %q", tt.image, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCandidateFromStatus(t *testing.T) {
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(t0.Add(d))
+		return &t
+	}
+	timeAt := func(d time.Duration) *time.Time {
+		t := t0.Add(d)
+		return &t
+	}
+	backup := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "registry:5000/ns/db-0-app:latest"},
+		}},
+	}}
+	twoContainers := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "registry:5000/ns/db-0-app:latest"},
+			map[string]interface{}{"name": "sidecar", "image": "registry:5000/ns/db-0-sidecar"},
+		}},
+	}}
+	images := []migrationv1.BuiltImage{
+		{ContainerName: "app", BuildTime: at(0), Pushed: true, Digest: "sha256:old"},
+		{ContainerName: "app", BuildTime: at(time.Hour), Pushed: true, Digest: "sha256:new"},
+		{ContainerName: "app", BuildTime: at(2 * time.Hour), Pushed: false, Digest: "sha256:unpushed"},
+		{ContainerName: "app", BuildTime: at(3 * time.Hour), Pushed: true},
+		{ContainerName: "sidecar", BuildTime: at(30 * time.Minute), Pushed: true, Digest: "sha256:side"},
+	}
+
+	tests := []struct {
+		name       string
+		backup     *unstructured.Unstructured
+		status     migrationv1.CheckpointBackupStatus
+		before     *time.Time
+		want       []interface{}
+		wantTime   time.Time
+		wantReason string
+	}{
+		{
+			name:     "latest pushed image with a digest",
+			backup:   backup,
+			status:   migrationv1.CheckpointBackupStatus{Phase: PhaseCompleted, BuiltImages: images},
+			want:     []interface{}{map[string]interface{}{"name": "app", "image": "registry:5000/ns/db-0-app@sha256:new"}},
+			wantTime: t0.Add(time.Hour),
+		},
+		{
+			name:     "completed after the pod was deleted",
+			backup:   backup,
+			status:   migrationv1.CheckpointBackupStatus{Phase: PhaseCompletedPodDeleted, BuiltImages: images},
+			want:     []interface{}{map[string]interface{}{"name": "app", "image": "registry:5000/ns/db-0-app@sha256:new"}},
+			wantTime: t0.Add(time.Hour),
+		},
+		{
+			name:     "point in time picks the newest image at or before it",
+			backup:   backup,
+			status:   migrationv1.CheckpointBackupStatus{Phase: PhaseFailed, BuiltImages: images},
+			before:   timeAt(30 * time.Minute),
+			want:     []interface{}{map[string]interface{}{"name": "app", "image": "registry:5000/ns/db-0-app@sha256:old"}},
+			wantTime: t0,
+		},
+		{
+			name:   "checkpoint time is the newest of the containers",
+			backup: twoContainers,
+			status: migrationv1.CheckpointBackupStatus{Phase: PhaseCompleted, BuiltImages: images},
+			want: []interface{}{
+				map[string]interface{}{"name": "app", "image": "registry:5000/ns/db-0-app@sha256:new"},
+				map[string]interface{}{"name": "sidecar", "image": "registry:5000/ns/db-0-sidecar@sha256:side"},
+			},
+			wantTime: t0.Add(time.Hour),
+		},
+		{
+			name:       "no checkpoint yet",
+			backup:     backup,
+			wantReason: "no checkpoint taken yet",
+		},
+		{
+			name:       "latest run failed",
+			backup:     backup,
+			status:     migrationv1.CheckpointBackupStatus{Phase: PhaseFailed, Message: "push refused", BuiltImages: images},
+			wantReason: PhaseFailed + ": push refused",
+		},
+		{
+			name:       "run in progress",
+			backup:     backup,
+			status:     migrationv1.CheckpointBackupStatus{Phase: PhaseCheckpointing},
+			wantReason: PhaseCheckpointing,
+		},
+		{
+			name:       "nothing pushed before the point in time",
+			backup:     backup,
+			status:     migrationv1.CheckpointBackupStatus{Phase: PhaseCompleted, BuiltImages: images},
+			before:     timeAt(-time.Minute),
+			wantReason: "container app has no image pushed before",
+		},
+		{
+			name:       "one container without a pushed image",
+			backup:     twoContainers,
+			status:     migrationv1.CheckpointBackupStatus{Phase: PhaseCompleted, BuiltImages: images[:4]},
+			wantReason: "container sidecar has no pushed image",
+		},
+		{
+			name:       "backup without containers",
+			backup:     &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}},
+			status:     migrationv1.CheckpointBackupStatus{Phase: PhaseCompleted, BuiltImages: images},
+			wantReason: "no container is pushed to a registry",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := candidateFromStatus(tt.backup, &tt.status, tt.before)
+			if tt.wantReason != "" {
+				if !strings.HasPrefix(reason, tt.wantReason) {
+					t.Fatalf("reason = %q, want prefix %q", reason, tt.wantReason)
+				}
+				return
+			}
+			if reason != "" {
+				t.Fatalf("unexpected reason %q", reason)
+			}
+			if got.backup != tt.backup {
+				t.Errorf("candidate does not point at the backup")
+			}
+			if !reflect.DeepEqual(got.containers, tt.want) {
+				t.Errorf("containers = %v, want %v", got.containers, tt.want)
+			}
+			if !got.checkpointTime.Equal(tt.wantTime) {
+				t.Errorf("checkpointTime = %v, want %v", got.checkpointTime, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestSelectRestoreCandidates(t *testing.T) {
+	ctx := context.Background()
+	backup := func(name, cluster string) unstructured.Unstructured {
+		b := newBackupU(name, "StatefulSet", "db")
+		labels := map[string]string{"target-pod": name}
+		if cluster != "" {
+			labels["target-cluster"] = cluster
+		}
+		b.SetLabels(labels)
+		b.Object["spec"].(map[string]interface{})["containers"] = []interface{}{
+			map[string]interface{}{"name": "app", "image": "registry:5000/app/" + name + ":latest"},
+		}
+		return *b
+	}
+	status := func(phase string) map[string]interface{} {
+		return map[string]interface{}{"status": map[string]interface{}{
+			"phase": phase,
+			"builtImages": []interface{}{map[string]interface{}{
+				"containerName": "app", "imageName": "app", "buildTime": "2026-01-01T00:00:00Z", "pushed": true, "digest": "sha256:abc",
+			}},
+		}}
+	}
+	proxy := newFakeMemberProxy(t)
+	proxy.add("member1", newMemberObject(migrationv1.GroupVersion.String(), "CheckpointBackup", "app", "db-0", status(PhaseCompleted)))
+	proxy.add("member1", newMemberObject(migrationv1.GroupVersion.String(), "CheckpointBackup", "app", "db-1", status(PhaseCheckpointing)))
+	proxy.add("member2", newMemberObject(migrationv1.GroupVersion.String(), "CheckpointBackup", "app", "db-2", status(PhaseCompleted)))
+	backups := []unstructured.Unstructured{
+		backup("db-0", "member1"),
+		backup("db-1", "member1"),
+		backup("db-2", "member2"),
+		backup("db-3", "member1"), // 멤버 클러스터에 아직 없음
+		backup("db-4", ""),
+	}
+	r := &MigrationRestoreReconciler{MemberClusterClient: newTestMemberClusterClient(t, nil, proxy)}
+	latest := migrationv1.RestoreSource{Type: migrationv1.RestoreSourceLatest}
+
+	check := func(wantPods []string, wantBlocking map[string]string) {
+		t.Helper()
+		candidates, blocking, err := r.selectRestoreCandidates(ctx, backups, latest)
+		if err != nil {
+			t.Fatalf("selectRestoreCandidates: %v", err)
+		}
+		var pods []string
+		for _, c := range candidates {
+			pods = append(pods, backupPodName(c.backup))
+		}
+		if !reflect.DeepEqual(pods, wantPods) {
+			t.Errorf("candidates = %v, want %v", pods, wantPods)
+		}
+		if !reflect.DeepEqual(blocking, wantBlocking) {
+			t.Errorf("blocking = %v, want %v", blocking, wantBlocking)
+		}
+	}
+	wantBlocking := map[string]string{
+		"db-1": "db-1: " + PhaseCheckpointing,
+		"db-3": "db-3: status unavailable",
+		"db-4": "db-4: status unavailable",
+	}
+	check([]string{"db-0", "db-2"}, wantBlocking)
+	if n1, n2 := proxy.countRequests("GET", "member1"), proxy.countRequests("GET", "member2"); n1 != 1 || n2 != 1 {
+		t.Fatalf("requests = member1 %d, member2 %d, want one LIST each", n1, n2)
+	}
+
+	// backupStatusTTL 안에서는 다시 LIST하지 않음
+	proxy.setDown("member2", true)
+	check([]string{"db-0", "db-2"}, wantBlocking)
+	if n1 := proxy.countRequests("GET", "member1"); n1 != 1 {
+		t.Fatalf("member1 listed %d times within the TTL, want 1", n1)
+	}
+
+	// TTL이 지나면 다시 LIST하고, 응답하지 않는 클러스터의 Backup은 확인 불가
+	for key, e := range r.backupStatuses.entries {
+		e.at = e.at.Add(-backupStatusTTL)
+		r.backupStatuses.entries[key] = e
+	}
+	wantBlocking["db-2"] = "db-2: status unavailable"
+	check([]string{"db-0"}, wantBlocking)
+	if n1 := proxy.countRequests("GET", "member1"); n1 != 2 {
+		t.Fatalf("member1 listed %d times after the TTL, want 2", n1)
+	}
+}
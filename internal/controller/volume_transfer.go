@@ -0,0 +1,422 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+const (
+	// LabelVolumeTransfer carries the MigrationRun name on every object created to transfer volumes
+	LabelVolumeTransfer = "migration.dcnlab.com/volume-transfer"
+	// AnnoVolumeReady marks a claim on the destination whose data was transferred; the restore
+	// webhook rejects restored pods until every claim they mount carries it
+	AnnoVolumeReady = "migration.dcnlab.com/volume-ready"
+
+	// DefaultCopierImage runs the restic copier pods
+	DefaultCopierImage = "restic/restic:0.17.3"
+
+	// MigrationRunVolume.Phase 값
+	VolumePhaseCopying   = "Copying"
+	VolumePhaseCopied    = "Copied"
+	VolumePhaseRestoring = "Restoring"
+	VolumePhaseReady     = "Ready"
+	VolumePhaseCleanedUp = "CleanedUp"
+
+	snapshotAPIVersion = "snapshot.storage.k8s.io/v1"
+)
+
+// discoverVolumes lists the PersistentVolumeClaims mounted by the protected pods on clusters
+func discoverVolumes(ctx context.Context, kc *KarmadaClient, mc *MemberClusterClient, sm *migrationv1.StatefulMigration, clusters []string) ([]migrationv1.MigrationRunVolume, error) {
+	var backupList migrationv1.CheckpointBackupList
+	if err := kc.List(ctx, &backupList, &client.ListOptions{
+		Namespace:     sm.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{"stateful-migration": sm.Name}),
+	}); err != nil {
+		return nil, fmt.Errorf("list CheckpointBackups on Karmada: %w", err)
+	}
+	wanted := make(map[string]bool, len(clusters))
+	for _, c := range clusters {
+		wanted[c] = true
+	}
+	seen := map[string]bool{}
+	var out []migrationv1.MigrationRunVolume
+	for _, backup := range backupList.Items {
+		cluster := backup.Labels["target-cluster"]
+		if !wanted[cluster] {
+			continue
+		}
+		pod, err := mc.GetPodFromCluster(ctx, cluster, backup.Spec.PodRef.Namespace, backup.Spec.PodRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range pod.Spec.Volumes {
+			if v.PersistentVolumeClaim == nil || seen[cluster+"/"+v.PersistentVolumeClaim.ClaimName] {
+				continue
+			}
+			seen[cluster+"/"+v.PersistentVolumeClaim.ClaimName] = true
+			out = append(out, migrationv1.MigrationRunVolume{
+				ClaimName: v.PersistentVolumeClaim.ClaimName,
+				Cluster:   cluster,
+				PodName:   pod.Name,
+				Phase:     VolumePhaseCopying,
+			})
+		}
+	}
+	return out, nil
+}
+
+// volumeTransferName names the snapshot or copier pods of a claim
+func volumeTransferName(runName, claim string) string {
+	return fmt.Sprintf("%s-%s", runName, claim)
+}
+
+// startVolumeCopy snapshots the claim on its source cluster, or backs it up with a copier pod
+// scheduled next to the pod that mounts it
+func startVolumeCopy(ctx context.Context, mc *MemberClusterClient, policy *migrationv1.VolumeTransferPolicy, ns, runName string, vol migrationv1.MigrationRunVolume) error {
+	name := volumeTransferName(runName, vol.ClaimName)
+	var obj *unstructured.Unstructured
+	switch policy.Method {
+	case migrationv1.VolumeTransferSnapshot:
+		spec := map[string]interface{}{
+			"source": map[string]interface{}{"persistentVolumeClaimName": vol.ClaimName},
+		}
+		if policy.VolumeSnapshotClassName != "" {
+			spec["volumeSnapshotClassName"] = policy.VolumeSnapshotClassName
+		}
+		obj = newTransferObject(snapshotAPIVersion, "VolumeSnapshot", ns, name, runName, spec)
+	case migrationv1.VolumeTransferCopier:
+		// RWO 볼륨을 함께 마운트하려면 같은 노드에서 실행해야 함
+		pod, err := mc.GetPodFromCluster(ctx, vol.Cluster, ns, vol.PodName)
+		if err != nil {
+			return err
+		}
+		script := fmt.Sprintf("restic cat config >/dev/null 2>&1 || restic init; restic backup --host %s --tag %s /data", runName, name)
+		u, err := copierPod(policy, ns, name+"-backup", runName, vol.ClaimName, pod.Spec.NodeName, true, script)
+		if err != nil {
+			return err
+		}
+		obj = u
+	default:
+		return fmt.Errorf("unknown volume transfer method %q", policy.Method)
+	}
+	if err := mc.CreateResourceInCluster(ctx, vol.Cluster, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// volumeCopyDone reports whether the snapshot is ready to use or the copier pod succeeded
+func volumeCopyDone(ctx context.Context, mc *MemberClusterClient, policy *migrationv1.VolumeTransferPolicy, ns, runName string, vol migrationv1.MigrationRunVolume) (bool, error) {
+	name := volumeTransferName(runName, vol.ClaimName)
+	if policy.Method == migrationv1.VolumeTransferSnapshot {
+		vs, err := mc.GetResourceFromCluster(ctx, vol.Cluster, snapshotAPIVersion, "VolumeSnapshot", ns, name)
+		if err != nil {
+			return false, err
+		}
+		if msg, found, _ := unstructured.NestedString(vs.Object, "status", "error", "message"); found && msg != "" {
+			return false, fmt.Errorf("snapshot of %s on %s: %s", vol.ClaimName, vol.Cluster, msg)
+		}
+		ready, _, _ := unstructured.NestedBool(vs.Object, "status", "readyToUse")
+		return ready, nil
+	}
+	return copierPodDone(ctx, mc, vol.Cluster, ns, name+"-backup")
+}
+
+// startVolumeRestore creates the claim on the destination from the source claim's size and access
+// modes. With snapshots it is provisioned from the imported snapshot; with the copier a restore
+// pod fills it from the repository.
+func startVolumeRestore(ctx context.Context, mc *MemberClusterClient, policy *migrationv1.VolumeTransferPolicy, ns, runName, dest string, vol migrationv1.MigrationRunVolume) error {
+	name := volumeTransferName(runName, vol.ClaimName)
+	if existing, err := mc.GetResourceFromCluster(ctx, dest, "v1", "PersistentVolumeClaim", ns, vol.ClaimName); err == nil {
+		if existing.GetLabels()[LabelVolumeTransfer] != runName {
+			return fmt.Errorf("claim %s/%s already exists on %s", ns, vol.ClaimName, dest)
+		}
+		if policy.Method == migrationv1.VolumeTransferSnapshot {
+			return nil
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	} else {
+		src, err := mc.GetResourceFromCluster(ctx, vol.Cluster, "v1", "PersistentVolumeClaim", ns, vol.ClaimName)
+		if err != nil {
+			return err
+		}
+		spec := map[string]interface{}{}
+		for _, field := range []string{"accessModes", "resources", "storageClassName", "volumeMode"} {
+			if v, found, _ := unstructured.NestedFieldCopy(src.Object, "spec", field); found {
+				spec[field] = v
+			}
+		}
+		if policy.StorageClassName != nil {
+			spec["storageClassName"] = *policy.StorageClassName
+		}
+		if policy.Method == migrationv1.VolumeTransferSnapshot {
+			if err := importSnapshot(ctx, mc, policy, ns, runName, dest, vol); err != nil {
+				return err
+			}
+			spec["dataSource"] = map[string]interface{}{
+				"apiGroup": "snapshot.storage.k8s.io",
+				"kind":     "VolumeSnapshot",
+				"name":     name,
+			}
+		}
+		pvc := newTransferObject("v1", "PersistentVolumeClaim", ns, vol.ClaimName, runName, spec)
+		if policy.Method == migrationv1.VolumeTransferSnapshot {
+			// 스냅샷에서 프로비저닝되므로 생성 즉시 사용 가능
+			pvc.SetAnnotations(map[string]string{AnnoVolumeReady: "true"})
+		}
+		if err := mc.CreateResourceInCluster(ctx, dest, pvc); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		if policy.Method == migrationv1.VolumeTransferSnapshot {
+			return nil
+		}
+	}
+
+	script := fmt.Sprintf("restic restore latest:/data --host %s --tag %s --target /data", runName, name)
+	pod, err := copierPod(policy, ns, name+"-restore", runName, vol.ClaimName, "", false, script)
+	if err != nil {
+		return err
+	}
+	if err := mc.CreateResourceInCluster(ctx, dest, pod); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// importSnapshot recreates the source snapshot on the destination as a pre-provisioned
+// VolumeSnapshotContent pointing at the same snapshot handle
+func importSnapshot(ctx context.Context, mc *MemberClusterClient, policy *migrationv1.VolumeTransferPolicy, ns, runName, dest string, vol migrationv1.MigrationRunVolume) error {
+	name := volumeTransferName(runName, vol.ClaimName)
+	vs, err := mc.GetResourceFromCluster(ctx, vol.Cluster, snapshotAPIVersion, "VolumeSnapshot", ns, name)
+	if err != nil {
+		return err
+	}
+	contentName, _, _ := unstructured.NestedString(vs.Object, "status", "boundVolumeSnapshotContentName")
+	if contentName == "" {
+		return fmt.Errorf("snapshot %s/%s on %s is not bound to a VolumeSnapshotContent", ns, name, vol.Cluster)
+	}
+	content, err := mc.GetResourceFromCluster(ctx, vol.Cluster, snapshotAPIVersion, "VolumeSnapshotContent", "", contentName)
+	if err != nil {
+		return err
+	}
+	driver, _, _ := unstructured.NestedString(content.Object, "spec", "driver")
+	handle, _, _ := unstructured.NestedString(content.Object, "status", "snapshotHandle")
+	if handle == "" {
+		return fmt.Errorf("VolumeSnapshotContent %s on %s has no snapshot handle", contentName, vol.Cluster)
+	}
+
+	importedName := fmt.Sprintf("%s-%s", ns, name)
+	contentSpec := map[string]interface{}{
+		"deletionPolicy": "Retain",
+		"driver":         driver,
+		"source":         map[string]interface{}{"snapshotHandle": handle},
+		"volumeSnapshotRef": map[string]interface{}{
+			"name":      name,
+			"namespace": ns,
+		},
+	}
+	snapshotSpec := map[string]interface{}{
+		"source": map[string]interface{}{"volumeSnapshotContentName": importedName},
+	}
+	if policy.VolumeSnapshotClassName != "" {
+		contentSpec["volumeSnapshotClassName"] = policy.VolumeSnapshotClassName
+		snapshotSpec["volumeSnapshotClassName"] = policy.VolumeSnapshotClassName
+	}
+	for _, obj := range []*unstructured.Unstructured{
+		newTransferObject(snapshotAPIVersion, "VolumeSnapshotContent", "", importedName, runName, contentSpec),
+		newTransferObject(snapshotAPIVersion, "VolumeSnapshot", ns, name, runName, snapshotSpec),
+	} {
+		if err := mc.CreateResourceInCluster(ctx, dest, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// volumeRestoreDone reports whether the claim on the destination holds the data. A finished
+// restore pod marks the claim ready for the restore webhook.
+func volumeRestoreDone(ctx context.Context, mc *MemberClusterClient, policy *migrationv1.VolumeTransferPolicy, ns, runName, dest string, vol migrationv1.MigrationRunVolume) (bool, error) {
+	pvc, err := mc.GetResourceFromCluster(ctx, dest, "v1", "PersistentVolumeClaim", ns, vol.ClaimName)
+	if err != nil {
+		return false, err
+	}
+	if pvc.GetAnnotations()[AnnoVolumeReady] == "true" {
+		return true, nil
+	}
+	if policy.Method == migrationv1.VolumeTransferSnapshot {
+		return false, nil
+	}
+	done, err := copierPodDone(ctx, mc, dest, ns, volumeTransferName(runName, vol.ClaimName)+"-restore")
+	if err != nil || !done {
+		return false, err
+	}
+	ann := pvc.GetAnnotations()
+	if ann == nil {
+		ann = map[string]string{}
+	}
+	ann[AnnoVolumeReady] = "true"
+	pvc.SetAnnotations(ann)
+	if err := mc.UpdateResourceInCluster(ctx, dest, pvc); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// deleteVolumeTransfer deletes the objects of kinds (apiVersion/kind pairs) created by the run on clusters
+func deleteVolumeTransfer(ctx context.Context, mc *MemberClusterClient, clusters []string, ns, runName string, kinds [][2]string) error {
+	selector := labels.SelectorFromSet(map[string]string{LabelVolumeTransfer: runName}).String()
+	for _, cluster := range clusters {
+		for _, kind := range kinds {
+			namespace := ns
+			if kind[1] == "VolumeSnapshotContent" {
+				namespace = ""
+			}
+			list, err := mc.ListResourcesFromCluster(ctx, cluster, kind[0], kind[1], namespace, selector)
+			if err != nil {
+				return err
+			}
+			for i := range list.Items {
+				if err := mc.DeleteResourceFromCluster(ctx, cluster, &list.Items[i]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// destinationClaimsBound reports whether every transferred claim is bound on the destination. A
+// claim provisioned from a snapshot may wait for its first consumer, so the snapshot it is
+// created from must be kept until then.
+func destinationClaimsBound(ctx context.Context, mc *MemberClusterClient, ns, dest string, volumes []migrationv1.MigrationRunVolume) (bool, error) {
+	for _, vol := range volumes {
+		pvc, err := mc.GetResourceFromCluster(ctx, dest, "v1", "PersistentVolumeClaim", ns, vol.ClaimName)
+		if err != nil {
+			return false, err
+		}
+		if phase, _, _ := unstructured.NestedString(pvc.Object, "status", "phase"); phase != string(corev1.ClaimBound) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// volumeSnapshotKinds are the snapshot objects of a run: the snapshots on the source clusters and
+// the imported snapshots and contents on the destination. The imported contents are Retain, so
+// only the source snapshots release the snapshot on the storage backend.
+var volumeSnapshotKinds = [][2]string{{snapshotAPIVersion, "VolumeSnapshot"}, {snapshotAPIVersion, "VolumeSnapshotContent"}}
+
+// volumeTransferKinds returns the kinds a transfer with the policy creates, copier pods first
+func volumeTransferKinds(policy *migrationv1.VolumeTransferPolicy, withData bool) [][2]string {
+	kinds := [][2]string{{"v1", "Pod"}}
+	if !withData {
+		return kinds
+	}
+	kinds = append(kinds, [2]string{"v1", "PersistentVolumeClaim"})
+	if policy.Method == migrationv1.VolumeTransferSnapshot {
+		kinds = append(kinds, [2]string{snapshotAPIVersion, "VolumeSnapshot"}, [2]string{snapshotAPIVersion, "VolumeSnapshotContent"})
+	}
+	return kinds
+}
+
+// copierPod returns a restic pod that mounts the claim at /data and runs script
+func copierPod(policy *migrationv1.VolumeTransferPolicy, ns, name, runName, claim, nodeName string, readOnly bool, script string) (*unstructured.Unstructured, error) {
+	if policy.RepositorySecretRef == nil || policy.RepositorySecretRef.Name == "" {
+		return nil, fmt.Errorf("volumeTransfer.repositorySecretRef is required for the Copier method")
+	}
+	image := policy.CopierImage
+	if image == "" {
+		image = DefaultCopierImage
+	}
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    map[string]string{LabelVolumeTransfer: runName},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			NodeName:      nodeName,
+			Containers: []corev1.Container{{
+				Name:    "copier",
+				Image:   image,
+				Command: []string{"/bin/sh", "-c", script},
+				EnvFrom: []corev1.EnvFromSource{{
+					SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: policy.RepositorySecretRef.Name}},
+				}},
+				VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data", ReadOnly: readOnly}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim, ReadOnly: readOnly},
+				},
+			}},
+		},
+	}
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: raw}, nil
+}
+
+// copierPodDone reports whether the copier pod succeeded; a failed pod is returned as an error
+func copierPodDone(ctx context.Context, mc *MemberClusterClient, cluster, ns, name string) (bool, error) {
+	pod, err := mc.GetPodFromCluster(ctx, cluster, ns, name)
+	if err != nil {
+		return false, err
+	}
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return true, nil
+	case corev1.PodFailed:
+		msg := pod.Status.Message
+		for _, cs := range pod.Status.ContainerStatuses {
+			if t := cs.State.Terminated; t != nil && t.Message != "" {
+				msg = t.Message
+			}
+		}
+		return false, fmt.Errorf("copier pod %s on %s failed: %s", name, cluster, msg)
+	}
+	return false, nil
+}
+
+// newTransferObject returns an unstructured object labeled with the run
+func newTransferObject(apiVersion, kind, ns, name, runName string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(ns)
+	obj.SetName(name)
+	obj.SetLabels(map[string]string{LabelVolumeTransfer: runName})
+	return obj
+}
@@ -0,0 +1,385 @@
+/*
+Copyright 2025 Le huan and Jeong SeungJun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// newClaimPod returns a member cluster pod mounting the claims
+func newClaimPod(ns, name, node string, claims ...string) *unstructured.Unstructured {
+	var volumes []interface{}
+	for _, c := range claims {
+		volumes = append(volumes, map[string]interface{}{"name": c, "persistentVolumeClaim": map[string]interface{}{"claimName": c}})
+	}
+	volumes = append(volumes, map[string]interface{}{"name": "config", "configMap": map[string]interface{}{"name": "db-config"}})
+	return newMemberObject("v1", "Pod", ns, name, map[string]interface{}{"spec": map[string]interface{}{
+		"nodeName":   node,
+		"containers": []interface{}{map[string]interface{}{"name": "db", "image": "postgres:16"}},
+		"volumes":    volumes,
+	}})
+}
+
+func TestDiscoverVolumes(t *testing.T) {
+	sm := &migrationv1.StatefulMigration{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "app"}}
+	backup := func(pod, cluster string) client.Object {
+		return &migrationv1.CheckpointBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-" + pod + "-" + cluster, Namespace: "app", Labels: map[string]string{
+				"stateful-migration": "db", "target-cluster": cluster, "target-pod": pod,
+			}},
+			Spec: migrationv1.CheckpointBackupSpec{PodRef: migrationv1.PodRef{Namespace: "app", Name: pod}},
+		}
+	}
+	other := backup("web-0", "member1").(*migrationv1.CheckpointBackup)
+	other.Labels["stateful-migration"] = "web"
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).
+		WithObjects(backup("db-0", "member1"), backup("db-1", "member1"), backup("db-0", "member2"), other).Build()
+	proxy := newFakeMemberProxy(t)
+	proxy.add("member1", newClaimPod("app", "db-0", "node-1", "data-db-0", "shared"))
+	proxy.add("member1", newClaimPod("app", "db-1", "node-2", "data-db-1", "shared"))
+	proxy.add("member1", newClaimPod("app", "web-0", "node-1", "data-web-0"))
+	proxy.add("member2", newClaimPod("app", "db-0", "node-3", "data-db-0"))
+
+	got, err := discoverVolumes(context.Background(), newTestKarmadaClient(t, c, proxy), newTestMemberClusterClient(t, c, proxy), sm, []string{"member1"})
+	if err != nil {
+		t.Fatalf("discoverVolumes: %v", err)
+	}
+	want := []migrationv1.MigrationRunVolume{
+		{ClaimName: "data-db-0", Cluster: "member1", PodName: "db-0", Phase: VolumePhaseCopying},
+		{ClaimName: "shared", Cluster: "member1", PodName: "db-0", Phase: VolumePhaseCopying},
+		{ClaimName: "data-db-1", Cluster: "member1", PodName: "db-1", Phase: VolumePhaseCopying},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("volumes = %+v, want %+v", got, want)
+	}
+
+	// 보호 중인 pod를 읽을 수 없으면 일부만 옮기지 않도록 실패
+	proxy.setDown("member1", true)
+	if _, err := discoverVolumes(context.Background(), newTestKarmadaClient(t, c, proxy), newTestMemberClusterClient(t, c, proxy), sm, []string{"member1"}); err == nil {
+		t.Fatalf("discoverVolumes succeeded with the source cluster down")
+	}
+}
+
+func TestStartVolumeRestore(t *testing.T) {
+	storageClass := "fast"
+	snapshot := &migrationv1.VolumeTransferPolicy{Method: migrationv1.VolumeTransferSnapshot, VolumeSnapshotClassName: "csi-snap"}
+	copier := &migrationv1.VolumeTransferPolicy{
+		Method:              migrationv1.VolumeTransferCopier,
+		StorageClassName:    &storageClass,
+		RepositorySecretRef: &migrationv1.SecretRef{Name: "restic"},
+	}
+	vol := migrationv1.MigrationRunVolume{ClaimName: "data-db-0", Cluster: "member1", PodName: "db-0", Phase: VolumePhaseCopied}
+	sourceClaim := newMemberObject("v1", "PersistentVolumeClaim", "app", "data-db-0", map[string]interface{}{"spec": map[string]interface{}{
+		"accessModes":      []interface{}{"ReadWriteOnce"},
+		"resources":        map[string]interface{}{"requests": map[string]interface{}{"storage": "10Gi"}},
+		"storageClassName": "standard",
+		"volumeName":       "pv-source",
+	}})
+	sourceSnapshot := newMemberObject(snapshotAPIVersion, "VolumeSnapshot", "app", "move-data-db-0",
+		map[string]interface{}{"status": map[string]interface{}{"boundVolumeSnapshotContentName": "snapcontent-1", "readyToUse": true}})
+	sourceContent := newMemberObject(snapshotAPIVersion, "VolumeSnapshotContent", "", "snapcontent-1", map[string]interface{}{
+		"spec":   map[string]interface{}{"driver": "ebs.csi.aws.com"},
+		"status": map[string]interface{}{"snapshotHandle": "snap-0abc"},
+	})
+	labeled := func(obj *unstructured.Unstructured, run string) *unstructured.Unstructured {
+		obj = obj.DeepCopy()
+		if run != "" {
+			obj.SetLabels(map[string]string{LabelVolumeTransfer: run})
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name    string
+		policy  *migrationv1.VolumeTransferPolicy
+		source  []*unstructured.Unstructured
+		dest    []*unstructured.Unstructured
+		wantErr string
+		check   func(t *testing.T, p *fakeMemberProxy)
+	}{
+		{
+			name:   "snapshot provisions the claim from the imported snapshot",
+			policy: snapshot,
+			source: []*unstructured.Unstructured{sourceClaim, sourceSnapshot, sourceContent},
+			check: func(t *testing.T, p *fakeMemberProxy) {
+				content := p.get("member2", snapshotAPIVersion, "VolumeSnapshotContent", "", "app-move-data-db-0")
+				if content == nil {
+					t.Fatalf("VolumeSnapshotContent not imported")
+				}
+				handle, _, _ := unstructured.NestedString(content.Object, "spec", "source", "snapshotHandle")
+				policy, _, _ := unstructured.NestedString(content.Object, "spec", "deletionPolicy")
+				if handle != "snap-0abc" || policy != "Retain" {
+					t.Errorf("imported content handle %q deletionPolicy %q", handle, policy)
+				}
+				if p.get("member2", snapshotAPIVersion, "VolumeSnapshot", "app", "move-data-db-0") == nil {
+					t.Errorf("VolumeSnapshot not imported")
+				}
+				pvc := p.get("member2", "v1", "PersistentVolumeClaim", "app", "data-db-0")
+				if pvc == nil {
+					t.Fatalf("claim not created")
+				}
+				ds, _, _ := unstructured.NestedString(pvc.Object, "spec", "dataSource", "name")
+				sc, _, _ := unstructured.NestedString(pvc.Object, "spec", "storageClassName")
+				if _, found, _ := unstructured.NestedString(pvc.Object, "spec", "volumeName"); found {
+					t.Errorf("claim copied the source volume name")
+				}
+				if ds != "move-data-db-0" || sc != "standard" || pvc.GetAnnotations()[AnnoVolumeReady] != "true" || pvc.GetLabels()[LabelVolumeTransfer] != "move" {
+					t.Errorf("claim = %v", pvc.Object)
+				}
+			},
+		},
+		{
+			name:    "snapshot not bound to a content",
+			policy:  snapshot,
+			source:  []*unstructured.Unstructured{sourceClaim, newMemberObject(snapshotAPIVersion, "VolumeSnapshot", "app", "move-data-db-0", nil)},
+			wantErr: "not bound to a VolumeSnapshotContent",
+		},
+		{
+			name:   "copier creates an empty claim and a restore pod",
+			policy: copier,
+			source: []*unstructured.Unstructured{sourceClaim},
+			check: func(t *testing.T, p *fakeMemberProxy) {
+				pvc := p.get("member2", "v1", "PersistentVolumeClaim", "app", "data-db-0")
+				if pvc == nil {
+					t.Fatalf("claim not created")
+				}
+				sc, _, _ := unstructured.NestedString(pvc.Object, "spec", "storageClassName")
+				if sc != "fast" || pvc.GetAnnotations()[AnnoVolumeReady] != "" {
+					t.Errorf("claim = %v", pvc.Object)
+				}
+				if p.get("member2", "v1", "Pod", "app", "move-data-db-0-restore") == nil {
+					t.Errorf("restore pod not created")
+				}
+			},
+		},
+		{
+			name:   "copier retry keeps the run's claim and creates the restore pod",
+			policy: copier,
+			dest:   []*unstructured.Unstructured{labeled(sourceClaim, "move")},
+			check: func(t *testing.T, p *fakeMemberProxy) {
+				if p.get("member2", "v1", "Pod", "app", "move-data-db-0-restore") == nil {
+					t.Errorf("restore pod not created")
+				}
+			},
+		},
+		{
+			name:   "snapshot retry leaves the run's claim alone",
+			policy: snapshot,
+			dest:   []*unstructured.Unstructured{labeled(sourceClaim, "move")},
+			check: func(t *testing.T, p *fakeMemberProxy) {
+				if n := p.countRequests("POST", "member2"); n != 0 {
+					t.Errorf("%d object(s) created on a retry", n)
+				}
+			},
+		},
+		{
+			name:    "claim of the workload already on the destination",
+			policy:  copier,
+			source:  []*unstructured.Unstructured{sourceClaim},
+			dest:    []*unstructured.Unstructured{labeled(sourceClaim, "")},
+			wantErr: "already exists on member2",
+		},
+		{
+			name:    "claim of another run already on the destination",
+			policy:  snapshot,
+			source:  []*unstructured.Unstructured{sourceClaim, sourceSnapshot, sourceContent},
+			dest:    []*unstructured.Unstructured{labeled(sourceClaim, "earlier")},
+			wantErr: "already exists on member2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newFakeMemberProxy(t)
+			for _, obj := range tt.source {
+				p.add("member1", obj)
+			}
+			for _, obj := range tt.dest {
+				p.add("member2", obj)
+			}
+			err := startVolumeRestore(context.Background(), newTestMemberClusterClient(t, nil, p), tt.policy, "app", "move", "member2", vol)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("startVolumeRestore: %v", err)
+			}
+			tt.check(t, p)
+		})
+	}
+}
+
+func TestVolumeRestoreDone(t *testing.T) {
+	snapshot := &migrationv1.VolumeTransferPolicy{Method: migrationv1.VolumeTransferSnapshot}
+	copier := &migrationv1.VolumeTransferPolicy{Method: migrationv1.VolumeTransferCopier}
+	vol := migrationv1.MigrationRunVolume{ClaimName: "data-db-0", Cluster: "member1", Phase: VolumePhaseRestoring}
+	claim := func(ready bool) *unstructured.Unstructured {
+		pvc := newMemberObject("v1", "PersistentVolumeClaim", "app", "data-db-0", nil)
+		if ready {
+			pvc.SetAnnotations(map[string]string{AnnoVolumeReady: "true"})
+		}
+		return pvc
+	}
+	restorePod := func(phase, message string) *unstructured.Unstructured {
+		status := map[string]interface{}{"phase": phase}
+		if message != "" {
+			status["containerStatuses"] = []interface{}{map[string]interface{}{
+				"name": "copier", "state": map[string]interface{}{"terminated": map[string]interface{}{"exitCode": int64(1), "message": message}},
+			}}
+		}
+		return newMemberObject("v1", "Pod", "app", "move-data-db-0-restore", map[string]interface{}{"status": status})
+	}
+
+	tests := []struct {
+		name      string
+		policy    *migrationv1.VolumeTransferPolicy
+		objs      []*unstructured.Unstructured
+		want      bool
+		wantErr   string
+		wantReady bool
+	}{
+		{name: "snapshot claim is ready on creation", policy: snapshot, objs: []*unstructured.Unstructured{claim(true)}, want: true, wantReady: true},
+		{name: "snapshot claim without the mark", policy: snapshot, objs: []*unstructured.Unstructured{claim(false)}},
+		{name: "claim missing", policy: snapshot, wantErr: "not found"},
+		{name: "copier still running", policy: copier, objs: []*unstructured.Unstructured{claim(false), restorePod("Running", "")}},
+		{name: "copier succeeded marks the claim", policy: copier, objs: []*unstructured.Unstructured{claim(false), restorePod("Succeeded", "")}, want: true, wantReady: true},
+		{name: "copier failed", policy: copier, objs: []*unstructured.Unstructured{claim(false), restorePod("Failed", "wrong password")}, wantErr: "wrong password"},
+		{name: "copier claim already marked", policy: copier, objs: []*unstructured.Unstructured{claim(true)}, want: true, wantReady: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newFakeMemberProxy(t)
+			for _, obj := range tt.objs {
+				p.add("member2", obj)
+			}
+			done, err := volumeRestoreDone(context.Background(), newTestMemberClusterClient(t, nil, p), tt.policy, "app", "move", "member2", vol)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("volumeRestoreDone: %v", err)
+			}
+			if done != tt.want {
+				t.Fatalf("done = %v, want %v", done, tt.want)
+			}
+			ready := p.get("member2", "v1", "PersistentVolumeClaim", "app", "data-db-0").GetAnnotations()[AnnoVolumeReady] == "true"
+			if ready != tt.wantReady {
+				t.Errorf("claim ready = %v, want %v", ready, tt.wantReady)
+			}
+		})
+	}
+}
+
+func TestVolumeTransferKinds(t *testing.T) {
+	pod := [2]string{"v1", "Pod"}
+	pvc := [2]string{"v1", "PersistentVolumeClaim"}
+	vs := [2]string{snapshotAPIVersion, "VolumeSnapshot"}
+	vsc := [2]string{snapshotAPIVersion, "VolumeSnapshotContent"}
+	snapshot := &migrationv1.VolumeTransferPolicy{Method: migrationv1.VolumeTransferSnapshot}
+	copier := &migrationv1.VolumeTransferPolicy{Method: migrationv1.VolumeTransferCopier}
+
+	tests := []struct {
+		name     string
+		policy   *migrationv1.VolumeTransferPolicy
+		withData bool
+		want     [][2]string
+	}{
+		{name: "snapshot pods only", policy: snapshot, want: [][2]string{pod}},
+		{name: "copier pods only", policy: copier, want: [][2]string{pod}},
+		{name: "snapshot with data", policy: snapshot, withData: true, want: [][2]string{pod, pvc, vs, vsc}},
+		{name: "copier with data", policy: copier, withData: true, want: [][2]string{pod, pvc}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := volumeTransferKinds(tt.policy, tt.withData); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("volumeTransferKinds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteVolumeTransfer(t *testing.T) {
+	p := newFakeMemberProxy(t)
+	labeled := func(obj *unstructured.Unstructured, run string) *unstructured.Unstructured {
+		obj.SetLabels(map[string]string{LabelVolumeTransfer: run})
+		return obj
+	}
+	for _, cluster := range []string{"member1", "member2"} {
+		p.add(cluster, labeled(newMemberObject("v1", "Pod", "app", "move-data-db-0-backup", nil), "move"))
+		p.add(cluster, labeled(newMemberObject("v1", "PersistentVolumeClaim", "app", "data-db-0", nil), "move"))
+		p.add(cluster, labeled(newMemberObject(snapshotAPIVersion, "VolumeSnapshot", "app", "move-data-db-0", nil), "move"))
+		p.add(cluster, labeled(newMemberObject(snapshotAPIVersion, "VolumeSnapshotContent", "", "app-move-data-db-0", nil), "move"))
+		// 다른 run과 워크로드 자신의 객체는 유지
+		p.add(cluster, labeled(newMemberObject("v1", "Pod", "app", "earlier-data-db-0-backup", nil), "earlier"))
+		p.add(cluster, newMemberObject("v1", "PersistentVolumeClaim", "app", "data-db-1", nil))
+	}
+	mc := newTestMemberClusterClient(t, nil, p)
+	ctx := context.Background()
+
+	// 스냅샷만 삭제: 목적지와 소스 모두
+	if err := deleteVolumeTransfer(ctx, mc, []string{"member2", "member1"}, "app", "move", volumeSnapshotKinds); err != nil {
+		t.Fatalf("deleteVolumeTransfer: %v", err)
+	}
+	for _, cluster := range []string{"member1", "member2"} {
+		if p.get(cluster, snapshotAPIVersion, "VolumeSnapshot", "app", "move-data-db-0") != nil ||
+			p.get(cluster, snapshotAPIVersion, "VolumeSnapshotContent", "", "app-move-data-db-0") != nil {
+			t.Errorf("snapshots left on %s", cluster)
+		}
+		if p.get(cluster, "v1", "PersistentVolumeClaim", "app", "data-db-0") == nil || p.get(cluster, "v1", "Pod", "app", "move-data-db-0-backup") == nil {
+			t.Errorf("claim or copier pod deleted with the snapshots on %s", cluster)
+		}
+	}
+
+	// 롤백: 이 run의 나머지 객체를 한 클러스터에서만 삭제
+	kinds := volumeTransferKinds(&migrationv1.VolumeTransferPolicy{Method: migrationv1.VolumeTransferSnapshot}, true)
+	if err := deleteVolumeTransfer(ctx, mc, []string{"member2"}, "app", "move", kinds); err != nil {
+		t.Fatalf("deleteVolumeTransfer: %v", err)
+	}
+	if p.get("member2", "v1", "PersistentVolumeClaim", "app", "data-db-0") != nil || p.get("member2", "v1", "Pod", "app", "move-data-db-0-backup") != nil {
+		t.Errorf("run objects left on member2")
+	}
+	if p.get("member1", "v1", "PersistentVolumeClaim", "app", "data-db-0") == nil {
+		t.Errorf("claim deleted on a cluster that was not listed")
+	}
+	for _, cluster := range []string{"member1", "member2"} {
+		if p.get(cluster, "v1", "Pod", "app", "earlier-data-db-0-backup") == nil || p.get(cluster, "v1", "PersistentVolumeClaim", "app", "data-db-1") == nil {
+			t.Errorf("objects of another run or of the workload deleted on %s", cluster)
+		}
+	}
+
+	// 응답하지 않는 클러스터는 오류로 알려 다음 reconcile에서 재시도
+	p.setDown("member1", true)
+	if err := deleteVolumeTransfer(ctx, mc, []string{"member1"}, "app", "move", kinds); err == nil {
+		t.Errorf("deleteVolumeTransfer succeeded with the cluster down")
+	}
+}